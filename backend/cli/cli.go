@@ -0,0 +1,140 @@
+// Package cli คือคำสั่งย่อยของ backend สำหรับงานดูแลระบบที่ทำครั้งเดียวแล้วจบ เช่น
+//
+//	backend migrate up
+//	backend seed demo
+//	backend create-admin -email ops@example.com
+//	backend reset-password alice
+//	backend reindex-search
+//	backend purge-trash -media-grace 0
+//	backend export -o articles.zip
+//	backend import -author alice -format wordpress blog.xml
+//
+// ทุกคำสั่งอ่านค่าตั้งค่าจาก config.yaml, .env และ environment และเชื่อมต่อฐานข้อมูลแบบเดียวกับ serve
+// log ออกทาง stderr ส่วนผลลัพธ์ของคำสั่งออกทาง stdout
+package cli
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/logging"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Command คือคำสั่งย่อยหนึ่งคำสั่ง Run ได้ argument ที่อยู่หลังชื่อคำสั่ง
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(args []string) error
+}
+
+// Commands คือคำสั่งย่อยที่ทำงานแล้วจบ เรียงตามลำดับที่แสดงใน usage
+var Commands = []Command{
+	{Name: "migrate", Summary: "apply, revert, list or create database migrations", Run: Migrate},
+	{Name: "seed", Summary: "load named fixture sets into the database", Run: Seed},
+	{Name: "create-admin", Summary: "create an admin account or promote an existing one", Run: CreateAdmin},
+	{Name: "reset-password", Summary: "set a new password and sign the user out everywhere", Run: ResetPassword},
+	{Name: "reindex-search", Summary: "rebuild article image references and sitemap entries", Run: ReindexSearch},
+	{Name: "purge-trash", Summary: "delete due accounts and unused images now", Run: PurgeTrash},
+	{Name: "export", Summary: "export articles as a zip of markdown files", Run: Export},
+	{Name: "import", Summary: "import markdown, WordPress or Medium files", Run: Import},
+}
+
+// errUsage คือ argument ไม่ครบหรือผิด usage ของคำสั่งถูกพิมพ์ไปแล้ว
+var errUsage = errors.New("invalid usage")
+
+// Run รันคำสั่งตามชื่อใน args[0] แล้วคืน exit code: 0 สำเร็จ, 1 คำสั่งผิดพลาด, 2 ใช้คำสั่งผิด
+func Run(commands []Command, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(commands)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.Name != args[0] {
+			continue
+		}
+		err := cmd.Run(args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.Name, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	printUsage(commands)
+	return 2
+}
+
+func printUsage(commands []Command) {
+	fmt.Fprintln(os.Stderr, "usage: backend <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `run "backend <command> -h" for the flags of a command`)
+}
+
+// newFlagSet สร้าง FlagSet ที่คืน error แทนการจบโปรแกรม usage คือ argument ที่อยู่หลัง flag
+func newFlagSet(name, usage, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: backend %s %s\n\n%s\n", name, usage, summary)
+		var hasFlags bool
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(fs.Output())
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parse อ่าน flag แล้วตรวจจำนวน argument ที่เหลือ max < 0 คือไม่จำกัด
+func parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	rest := fs.Args()
+	if len(rest) < min || (max >= 0 && len(rest) > max) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return rest, nil
+}
+
+// connect โหลดค่าตั้งค่าแล้วเชื่อมต่อฐานข้อมูล check ตรวจค่าอื่นที่คำสั่งต้องใช้เพิ่มจากฐานข้อมูล
+// ผู้เรียกต้อง defer database.Close()
+func connect(check func(*config.Config) error) (*config.Config, error) {
+	cfg := config.MustLoad(func(c *config.Config) error {
+		if err := c.Log.Validate(); err != nil {
+			return err
+		}
+		if err := c.Database.Validate(); err != nil {
+			return err
+		}
+		if check != nil {
+			return check(c)
+		}
+		return nil
+	})
+	logging.Init(cfg.Log)
+	if err := database.Connect(cfg.Database); err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return cfg, nil
+}
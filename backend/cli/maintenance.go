@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/jobs"
+	"backend/models"
+	"backend/storage"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReindexSearch สร้างข้อมูลที่คำนวณจากบทความใหม่ทั้งหมด คือรายการรูปที่แต่ละบทความใช้ และ sitemap ที่ search engine อ่าน
+// การค้นหาในบล็อกเองใช้ query บนตาราง articles โดยตรง จึงไม่มี index แยกให้สร้าง
+func ReindexSearch(args []string) error {
+	fs := newFlagSet("reindex-search", "",
+		"Rebuilds the data derived from articles: the images each article uses\nand the sitemap entries read by search engines.")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// รูปที่บทความใช้ต้องถูกต้องก่อน ไม่อย่างนั้น purge-trash อาจลบรูปที่ยังถูกใช้อยู่
+	// SyncArticleMedia ไม่บันทึกบทความซ้ำ รันคำสั่งนี้กี่ครั้งวันที่แก้ไขของบทความก็ไม่เปลี่ยน
+	var ids []uint
+	if err := database.DB.Model(&models.Article{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("load articles: %w", err)
+	}
+	synced := 0
+	for _, id := range ids {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var article models.Article
+			if err := tx.Select("id", "author_id", "content", "cover_media_id").First(&article, id).Error; err != nil {
+				return err
+			}
+			return composables.SyncArticleMedia(tx, &article)
+		})
+		// บทความที่ถูกลบระหว่างนี้ไม่ต้องทำอะไร
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("article %d: %w", id, err)
+		}
+		synced++
+	}
+
+	added, updated, removed, err := jobs.RebuildSitemap()
+	if err != nil {
+		return fmt.Errorf("rebuild sitemap: %w", err)
+	}
+	fmt.Printf("🔎 Reindexed %d article(s), sitemap: %d added, %d updated, %d removed\n", synced, added, updated, removed)
+	return nil
+}
+
+// PurgeTrash ลบสิ่งที่รอการลบอยู่ทันทีโดยไม่ต้องรองานเบื้องหลังรอบถัดไป
+// คือบัญชีที่พ้นช่วงเวลาผ่อนผันแล้ว รูปที่ไม่มีบทความใช้ และไฟล์ที่ไม่มีข้อมูลในฐานข้อมูล
+func PurgeTrash(args []string) error {
+	fs := newFlagSet("purge-trash", "[-media-grace duration]",
+		"Deletes accounts whose deletion grace period has passed, images no article\nhas used for -media-grace, and uploaded files that no image refers to.")
+	mediaGrace := fs.Duration("media-grace", 0, "keep unused images for this long, 0 deletes them all (default: MEDIA_ORPHAN_GRACE)")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	// บัญชีที่ถูกลบอาจมีไฟล์อัปโหลดที่ต้องลบด้วย
+	cfg, err := connect(func(c *config.Config) error { return c.Storage.Validate() })
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := storage.Init(cfg.Storage); err != nil {
+		return fmt.Errorf("open storage: %w", err)
+	}
+	graceSet := false
+	fs.Visit(func(f *flag.Flag) { graceSet = graceSet || f.Name == "media-grace" })
+	if !graceSet {
+		*mediaGrace = cfg.MediaOrphanGrace
+	}
+
+	deleted, failed, err := jobs.DeleteDueAccounts()
+	if err != nil {
+		return fmt.Errorf("delete accounts: %w", err)
+	}
+	media, files, err := jobs.RemoveOrphanMedia(time.Now().Add(-*mediaGrace))
+	if err != nil {
+		return fmt.Errorf("remove orphan media: %w", err)
+	}
+	fmt.Printf("🗑️  Deleted %d account(s), %d unused image(s) and %d stray file(s)\n", deleted, media, files)
+	if failed > 0 {
+		return fmt.Errorf("%d account(s) could not be deleted, see the log", failed)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"backend/database"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Migrate จัดการ migration ของฐานข้อมูล
+//
+//	migrate up           รัน migration ที่ยังไม่ได้รันทั้งหมด
+//	migrate down [n]     ย้อน migration ล่าสุด n รายการ (ค่าเริ่มต้น 1)
+//	migrate status       แสดงสถานะของทุก migration
+//	migrate new <name>   สร้างไฟล์ up/down ของ version ถัดไปในทุก driver
+func Migrate(args []string) error {
+	fs := newFlagSet("migrate", "[-dir folder] up | down [n] | status | new <name>",
+		"Applies, reverts, lists or creates database migrations.\nserve runs up on start unless DB_AUTO_MIGRATE=false.")
+	dir := fs.String("dir", "database/migrations", "migrations folder used by new")
+	args, err := parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	// new ไม่ต้องใช้ฐานข้อมูล
+	if args[0] == "new" {
+		if len(args) < 2 {
+			fs.Usage()
+			return errUsage
+		}
+		files, err := database.NewMigration(*dir, strings.Join(args[1:], " "))
+		if err != nil {
+			return fmt.Errorf("create migration: %w", err)
+		}
+		for _, file := range files {
+			fmt.Println("📝 Created", file)
+		}
+		return nil
+	}
+
+	var steps int
+	switch {
+	case args[0] == "down" && len(args) == 1:
+		steps = 1
+	case args[0] == "down" && len(args) == 2:
+		if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+			return errors.New("down expects a positive number of migrations")
+		}
+	case (args[0] == "up" || args[0] == "status") && len(args) == 1:
+	default:
+		fs.Usage()
+		return errUsage
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(database.DB)
+		for _, m := range applied {
+			fmt.Printf("🗃️ Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+	case "down":
+		reverted, err := database.MigrateDown(database.DB, steps)
+		for _, m := range reverted {
+			fmt.Printf("↩️ Reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Nothing to revert")
+		}
+	case "status":
+		list, err := database.MigrationsStatus(database.DB)
+		if err != nil {
+			return fmt.Errorf("read migrations: %w", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, m := range list {
+			appliedAt := "-"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", m.Version, m.Name, m.State, appliedAt)
+		}
+		return w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"backend/database"
+	"backend/seed"
+	"fmt"
+	"strings"
+)
+
+// Seed รันชุดข้อมูลตั้งต้นตามชื่อที่ให้มาตามลำดับ รันซ้ำได้โดยไม่สร้างข้อมูลซ้ำ
+func Seed(args []string) error {
+	var sets strings.Builder
+	for _, set := range seed.Sets {
+		fmt.Fprintf(&sets, "\n  %-10s %s", set.Name, set.Description)
+	}
+	fs := newFlagSet("seed", "<set> [set...]", "Loads fixture sets into the database. Sets:"+sets.String())
+	names, err := parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	// ตรวจชื่อทั้งหมดก่อน จะได้ไม่รันไปครึ่งเดียวเพราะพิมพ์ชื่อชุดหลังผิด
+	for _, name := range names {
+		if !hasSeedSet(name) {
+			fs.Usage()
+			return fmt.Errorf("unknown seed set %q", name)
+		}
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	for _, name := range names {
+		if err := seed.Run(name); err != nil {
+			return fmt.Errorf("seed %s: %w", name, err)
+		}
+		fmt.Println("🌱 Seeded", name)
+	}
+	return nil
+}
+
+func hasSeedSet(name string) bool {
+	for _, set := range seed.Sets {
+		if set.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/importer"
+	"backend/models"
+	"backend/repository"
+	"backend/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Export เขียนบทความเป็น ZIP ของไฟล์ Markdown รูปแบบเดียวกับปุ่มส่งออกในหน้าแอดมิน
+func Export(args []string) error {
+	fs := newFlagSet("export", "[-author username] [-o file]",
+		"Writes articles as a zip of markdown files, one folder per author.\nThe zip can be imported again with: backend import -format markdown.")
+	author := fs.String("author", "", "only export articles of this username (default: every author)")
+	output := fs.String("o", "", `output file, "-" for stdout (default "articles.zip" or "articles-<author>.zip")`)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = "articles.zip"
+		if *author != "" {
+			*output = "articles-" + *author + ".zip"
+		}
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *author != "" {
+		var exists int64
+		if err := database.DB.Model(&models.User{}).Where("username = ?", *author).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return fmt.Errorf("user %q not found", *author)
+		}
+	}
+	var articles []models.Article
+	if err := composables.ArticleListQuery(repository.ArticleFilter{Author: *author}).Find(&articles).Error; err != nil {
+		return fmt.Errorf("load articles: %w", err)
+	}
+
+	if *output == "-" {
+		return importer.Export(os.Stdout, articles)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := importer.Export(f, articles); err != nil {
+		f.Close()
+		os.Remove(*output) // ZIP ที่เขียนไม่จบเปิดไม่ได้ ไม่ควรเหลือไว้ให้สับสน
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "📦 Exported %d article(s) to %s\n", len(articles), *output)
+	return nil
+}
+
+// Import นำเข้าไฟล์ Markdown, WordPress หรือ Medium ในชื่อของผู้ใช้ที่กำหนด ใช้ตัวอ่านและกฎเดียวกับหน้าแอดมิน
+// จบด้วย error ถ้ามีรายการที่นำเข้าไม่ได้ เพื่อให้ script รู้ว่าต้องตรวจรายงาน
+func Import(args []string) error {
+	fs := newFlagSet("import", "-author user [flags] <file>",
+		"Imports articles from a file:\n"+
+			"  markdown   a .md file or a .zip of .md files\n"+
+			"  wordpress  a WXR export (.xml) or a .zip with the WXR file and wp-content/uploads\n"+
+			"  medium     the .zip downloaded from Medium")
+	format := fs.String("format", importer.SourceMarkdown, "markdown, wordpress or medium")
+	author := fs.String("author", "", "email or username that owns the imported articles (required)")
+	dryRun := fs.Bool("dry-run", false, "check the file and report what would happen without saving")
+	overwrite := fs.Bool("overwrite", false, "replace articles of the same author with the same slug")
+	createAuthors := fs.Bool("create-authors", false, "wordpress and medium: create accounts for authors that do not exist")
+	category := fs.String("category", "Uncategorized", "wordpress and medium: category for articles without one")
+	downloadImages := fs.Bool("download-images", true, "wordpress and medium: download images that are not in the zip")
+	asJSON := fs.Bool("json", false, "print the full report as JSON")
+	rest, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *author == "" {
+		fs.Usage()
+		return errUsage
+	}
+	switch *format {
+	case importer.SourceMarkdown, importer.SourceWordPress, importer.SourceMedium:
+	default:
+		return fmt.Errorf("unknown format %q, use markdown, wordpress or medium", *format)
+	}
+
+	data, err := os.ReadFile(rest[0])
+	if err != nil {
+		return err
+	}
+	posts, unread, archive, err := importer.ReadFile(*format, rest[0], data)
+	if err != nil {
+		return err
+	}
+
+	// รูปในบทความจาก WordPress และ Medium ถูกย้ายเข้าที่เก็บไฟล์อัปโหลด
+	needStorage := *format != importer.SourceMarkdown
+	cfg, err := connect(func(c *config.Config) error {
+		if needStorage {
+			return c.Storage.Validate()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if needStorage {
+		if err := storage.Init(cfg.Storage); err != nil {
+			return fmt.Errorf("open storage: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+	owner, err := repository.NewUsers(database.DB).FindByLogin(ctx, *author)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("user %q not found", *author)
+	} else if err != nil {
+		return err
+	}
+
+	opts := importer.Options{AuthorID: owner.ID, DryRun: *dryRun, Overwrite: *overwrite}
+	if needStorage {
+		opts.CreateAuthors = *createAuthors
+		opts.DefaultCategory = *category
+		opts.Images = importer.NewImageSource(archive, *downloadImages)
+	}
+	report := importer.Import(ctx, posts, opts)
+	report.AddUnread(unread)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printImportReport(os.Stdout, report)
+	}
+	if n := report.Conflicts + report.Invalid + report.Failed; n > 0 {
+		return fmt.Errorf("%d item(s) were not imported", n)
+	}
+	return nil
+}
+
+func printImportReport(w io.Writer, report *importer.Report) {
+	// รายการที่นำเข้าได้ปกติไม่ต้องแสดง แสดงเฉพาะรายการที่ผู้ดูแลต้องดูต่อ
+	for _, item := range report.Items {
+		if item.Status == importer.StatusCreate || item.Status == importer.StatusUpdate {
+			continue
+		}
+		fmt.Fprintf(w, "%-8s %s", item.Status, item.Source)
+		if item.Slug != "" {
+			fmt.Fprintf(w, " (%s)", item.Slug)
+		}
+		fmt.Fprintln(w)
+		fields := make([]string, 0, len(item.Errors))
+		for field := range item.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			fmt.Fprintf(w, "         %s: %s\n", field, item.Errors[field])
+		}
+	}
+	for _, author := range report.Authors {
+		line := []string{author.Status}
+		if author.Username != "" {
+			line = append(line, author.Username)
+		}
+		if author.Note != "" {
+			line = append(line, "("+author.Note+")")
+		}
+		fmt.Fprintf(w, "author   %s: %s\n", author.Login, strings.Join(line, " "))
+	}
+
+	verb := "Imported"
+	if report.DryRun {
+		verb = "Dry run, nothing was saved:"
+	}
+	fmt.Fprintf(w, "%s %d created, %d updated, %d conflicts, %d invalid, %d failed, %d skipped\n",
+		verb, report.Created, report.Updated, report.Conflicts, report.Invalid, report.Failed, report.Skipped)
+}
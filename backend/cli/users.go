@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"backend/database"
+	"backend/domain"
+	"backend/models"
+	"backend/passwords"
+	"backend/repository"
+	"backend/validation"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// CreateAdmin สร้างบัญชีแอดมินใหม่ ถ้ามีบัญชีที่ใช้อีเมลนี้อยู่แล้วจะเปลี่ยนบัญชีนั้นเป็นแอดมินโดยไม่แตะรหัสผ่าน
+func CreateAdmin(args []string) error {
+	fs := newFlagSet("create-admin", "-email address [flags]",
+		"Creates an admin account, or promotes the account that already uses the email.\nThe password is read from stdin when -password is not given.")
+	email := fs.String("email", "", "email of the account (required)")
+	username := fs.String("username", "", "username (default: the part of the email before @)")
+	firstName := fs.String("first-name", "Admin", "first name")
+	lastName := fs.String("last-name", "User", "last name")
+	nickname := fs.String("nickname", "", "nickname (default: the username)")
+	password := fs.String("password", "", "password of a new account, visible in the process list")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *email == "" {
+		fs.Usage()
+		return errUsage
+	}
+	if *username == "" {
+		*username, _, _ = strings.Cut(*email, "@")
+	}
+	if *nickname == "" {
+		*nickname = *username
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	users := repository.NewUsers(database.DB)
+	user, err := users.FindByLogin(ctx, *email)
+	switch {
+	case err == nil && strings.EqualFold(user.Email, *email):
+		if user.Role == models.RoleAdmin {
+			fmt.Printf("%s (%s) is already an admin\n", user.Username, user.Email)
+			return nil
+		}
+		user.Role = models.RoleAdmin
+		if err := users.Save(ctx, user); err != nil {
+			return err
+		}
+		fmt.Printf("👑 Promoted %s (%s) to admin, password unchanged\n", user.Username, user.Email)
+		return nil
+	case err != nil && !errors.Is(err, repository.ErrNotFound):
+		return err
+	}
+
+	if *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+	service := domain.UserService{Users: users}
+	created, err := service.Register(ctx, validation.RegisterInput{
+		Username:        *username,
+		Email:           *email,
+		Password:        *password,
+		ConfirmPassword: *password,
+		FirstName:       *firstName,
+		LastName:        *lastName,
+		Nickname:        *nickname,
+	})
+	if err != nil {
+		return err
+	}
+	// Register สร้างบัญชีผู้ใช้ทั่วไปเสมอ อ่านบัญชีเต็มกลับมาก่อนบันทึก ไม่อย่างนั้น hash ของรหัสผ่านจะหาย
+	if user, err = users.FindByID(ctx, created.ID); err != nil {
+		return err
+	}
+	user.Role = models.RoleAdmin
+	if err := users.Save(ctx, user); err != nil {
+		return err
+	}
+	fmt.Printf("👑 Created admin %s (%s) with id %d\n", user.Username, user.Email, user.ID)
+	return nil
+}
+
+// ResetPassword ตั้งรหัสผ่านใหม่ให้บัญชีจากอีเมลหรือ username แล้วยกเลิก session ทั้งหมดของบัญชีนั้น
+func ResetPassword(args []string) error {
+	fs := newFlagSet("reset-password", "[-password secret] <email-or-username>",
+		"Sets a new password and revokes every session of the account.\nThe password is read from stdin when -password is not given.")
+	password := fs.String("password", "", "new password, visible in the process list")
+	rest, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	if _, err := connect(nil); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	users := repository.NewUsers(database.DB)
+	user, err := users.FindByLogin(ctx, rest[0])
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("user %q not found", rest[0])
+	} else if err != nil {
+		return err
+	}
+
+	if *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+	// เกณฑ์เดียวกับตอนสมัครสมาชิก
+	if len(*password) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+	user.PasswordHash = passwords.Hash(*password)
+	if err := users.Save(ctx, user); err != nil {
+		return err
+	}
+
+	// token ที่ออกไปแล้วใช้ไม่ได้อีก ผู้ใช้ต้องเข้าสู่ระบบด้วยรหัสผ่านใหม่ทุกเครื่อง
+	result := database.DB.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", user.ID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("password changed but revoking sessions failed: %w", result.Error)
+	}
+	fmt.Printf("🔑 Password of %s (%s) changed, %d session(s) revoked\n", user.Username, user.Email, result.RowsAffected)
+	return nil
+}
+
+// readPassword ถามรหัสผ่านสองครั้งโดยไม่แสดงบนจอเมื่อรันใน terminal
+// ถ้า stdin ไม่ใช่ terminal เช่นส่งผ่าน pipe จะอ่านบรรทัดแรก
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// บรรทัดสุดท้ายที่ไม่มี \n ก็ใช้ได้ จึงสนใจแค่ว่าอ่านได้อะไรมาบ้าง
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line == "" {
+			return "", errors.New("no password on stdin")
+		}
+		return line, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	first, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "Confirm password: ")
+	second, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(first) != string(second) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(first), nil
+}
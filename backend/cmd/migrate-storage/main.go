@@ -0,0 +1,83 @@
+// migrate-storage คัดลอกไฟล์อัปโหลดทั้งหมดจากที่เก็บหนึ่งไปอีกที่หนึ่ง เช่น
+//
+//	go run ./cmd/migrate-storage -from local -to s3
+//
+// ค่าของแต่ละ driver อ่านจาก config.yaml, .env และ environment เหมือนตอนรัน backend
+// ไฟล์ที่มีอยู่แล้วและขนาดเท่ากันจะถูกข้าม จึงรันซ้ำได้ถ้าครั้งก่อนหยุดกลางคัน
+// URL ในฐานข้อมูล (/uploads/<key>) ไม่ต้องแก้ เพราะ backend ให้บริการไฟล์ตาม key เหมือนเดิม
+package main
+
+import (
+	"backend/config"
+	"backend/storage"
+	"context"
+	"flag"
+	"log"
+)
+
+func main() {
+	from := flag.String("from", storage.DriverLocal, "source storage driver (local or s3)")
+	to := flag.String("to", storage.DriverS3, "destination storage driver (local or s3)")
+	prefix := flag.String("prefix", "", "only migrate keys starting with this prefix")
+	deleteSource := flag.Bool("delete", false, "delete each file from the source after it is copied")
+	dryRun := flag.Bool("dry-run", false, "list what would be copied without copying")
+	flag.Parse()
+
+	cfg := config.MustLoad(nil)
+	if *from == *to {
+		log.Fatal("-from and -to must be different drivers")
+	}
+
+	src, err := storage.Open(*from, cfg.Storage)
+	if err != nil {
+		log.Fatal("Failed to open source storage: ", err)
+	}
+	dst, err := storage.Open(*to, cfg.Storage)
+	if err != nil {
+		log.Fatal("Failed to open destination storage: ", err)
+	}
+
+	ctx := context.Background()
+	objects, err := src.List(ctx, *prefix)
+	if err != nil {
+		log.Fatal("Failed to list source files: ", err)
+	}
+	existing := map[string]int64{}
+	if done, err := dst.List(ctx, *prefix); err == nil {
+		for _, obj := range done {
+			existing[obj.Key] = obj.Size
+		}
+	}
+
+	var copied, skipped, failed int
+	for _, obj := range objects {
+		if size, ok := existing[obj.Key]; ok && size == obj.Size {
+			skipped++
+			if *deleteSource && !*dryRun {
+				src.Delete(ctx, obj.Key)
+			}
+			continue
+		}
+		if *dryRun {
+			log.Println("would copy", obj.Key)
+			copied++
+			continue
+		}
+		if err := storage.Copy(ctx, src, dst, obj.Key); err != nil {
+			log.Println("❌ Failed to copy", obj.Key, err)
+			failed++
+			continue
+		}
+		copied++
+		if *deleteSource {
+			if err := src.Delete(ctx, obj.Key); err != nil {
+				log.Println("❌ Failed to delete source", obj.Key, err)
+			}
+		}
+	}
+
+	log.Printf("✅ %d copied, %d already present, %d failed", copied, skipped, failed)
+	if failed > 0 {
+		log.Fatal("migration finished with errors")
+	}
+}
@@ -0,0 +1,20 @@
+// migrate จัดการ migration ของฐานข้อมูล รันจากโฟลเดอร์ backend
+//
+//	go run ./cmd/migrate up           รัน migration ที่ยังไม่ได้รันทั้งหมด
+//	go run ./cmd/migrate down [n]     ย้อน migration ล่าสุด n รายการ (ค่าเริ่มต้น 1)
+//	go run ./cmd/migrate status       แสดงสถานะของทุก migration
+//	go run ./cmd/migrate new <name>   สร้างไฟล์ up/down ของ version ถัดไปในทุก driver
+//
+// เท่ากับ backend migrate ของ binary หลัก เก็บไว้ให้ script และคำแนะนำเดิมยังใช้ได้
+// ค่าการเชื่อมต่ออ่านจาก config.yaml, .env และ environment เหมือนตอนรัน backend
+// backend รัน up เองตอนเริ่มทำงาน ยกเว้นตั้ง DB_AUTO_MIGRATE=false
+package main
+
+import (
+	"backend/cli"
+	"os"
+)
+
+func main() {
+	os.Exit(cli.Run(cli.Commands, append([]string{"migrate"}, os.Args[1:]...)))
+}
@@ -0,0 +1,32 @@
+// mock-oidc รันผู้ให้บริการ OIDC จำลองสำหรับพัฒนาในเครื่อง เช่น
+//
+//	go run ./cmd/mock-oidc -addr :9000 -issuer http://localhost:9000
+//
+// แล้วตั้งค่า backend ด้วย OIDC_PROVIDERS=mock, OIDC_MOCK_ISSUER=http://localhost:9000,
+// OIDC_MOCK_CLIENT_ID=boblog, OIDC_MOCK_CLIENT_SECRET=secret และ OIDC_MOCK_REDIRECT_URL
+package main
+
+import (
+	"backend/oidc/mock"
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL, must match how the backend reaches this server")
+	clientID := flag.String("client-id", "boblog", "accepted client_id")
+	clientSecret := flag.String("client-secret", "secret", "accepted client_secret (empty for public clients)")
+	email := flag.String("email", "mock.user@example.com", "email returned for the default user")
+	flag.Parse()
+
+	server, err := mock.New(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.User.Email = *email
+
+	log.Println("🔐 Mock OIDC provider listening on", *addr, "issuer", *issuer)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
@@ -0,0 +1,167 @@
+package composables
+
+import (
+	"backend/database"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ค้นหาหรือสร้างบัญชี "deleted user" จากอีเมลที่สงวนไว้ ไม่ใช่จาก username
+// ถ้ามีคนใช้ username deleted-user อยู่ก่อนแล้ว บัญชีกลางจะได้ชื่ออื่นแทนที่จะไปใช้บัญชีของคนนั้น
+func DeletedUser(tx *gorm.DB) (*models.User, error) {
+	var user models.User
+	err := tx.Where("email = ?", models.DeletedUserEmail).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	user = models.User{
+		Username:  uniqueUsername(tx, models.DeletedUserUsername, func(string) bool { return false }),
+		Email:     models.DeletedUserEmail,
+		FirstName: "Deleted",
+		LastName:  "User",
+		Nickname:  "deleted user",
+		Role:      models.RoleUser,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ลบบัญชีจริง mode คือ models.DeletionModeAnonymize หรือ models.DeletionModeRemove
+func DeleteAccount(userID uint, mode string) error {
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Email == models.DeletedUserEmail {
+		return errors.New("the deleted user placeholder cannot be deleted")
+	}
+
+	// รูปแชร์ของบทความมีชื่อและรูปของผู้ใช้ ต้องลบทั้งสองแบบ
+	var authored []uint
+	if err := database.DB.Model(&models.Article{}).Where("author_id = ?", userID).Pluck("id", &authored).Error; err != nil {
+		return err
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		switch mode {
+		case models.DeletionModeAnonymize:
+			placeholder, err := DeletedUser(tx)
+			if err != nil {
+				return err
+			}
+			// ย้ายเจ้าของก่อนลบ user ไม่งั้น ON DELETE CASCADE ของ articles และ comments จะลบเนื้อหาทิ้ง
+			if err := tx.Model(&models.Article{}).Where("author_id = ?", userID).Update("author_id", placeholder.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Comment{}).Where("user_id = ?", userID).Update("user_id", placeholder.ID).Error; err != nil {
+				return err
+			}
+			// รูปในบทความที่ย้ายไปแล้วต้องอยู่ต่อ
+			if err := tx.Model(&models.Media{}).Where("user_id = ?", userID).Update("user_id", placeholder.ID).Error; err != nil {
+				return err
+			}
+		case models.DeletionModeRemove:
+			// article_tags ไม่มี ON DELETE CASCADE จึงต้องลบเองก่อน ส่วนที่เหลือลบเองด้วย
+			// เพื่อให้ได้ผลเหมือนกันแม้ฐานข้อมูลไม่ได้บังคับ foreign key
+			articleIDs := tx.Model(&models.Article{}).Select("id").Where("author_id = ?", userID)
+			if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN (?)", articleIDs).Error; err != nil {
+				return err
+			}
+			if err := DetachArticleMedia(tx, articleIDs); err != nil {
+				return err
+			}
+			mediaIDs := tx.Model(&models.Media{}).Select("id").Where("user_id = ?", userID)
+			if err := tx.Exec("DELETE FROM article_media WHERE media_id IN (?)", mediaIDs).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", userID).Delete(&models.Media{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("article_id IN (?)", articleIDs).Delete(&models.Comment{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("author_id = ?", userID).Delete(&models.Article{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", userID).Delete(&models.Comment{}).Error; err != nil {
+				return err
+			}
+		default:
+			return errors.New("unknown deletion mode")
+		}
+
+		// ประวัติการเข้าสู่ระบบมีอีเมลและ IP ของผู้ใช้ ลบทั้งแถวที่ผูกกับบัญชีและแถวที่พิมพ์อีเมลหรือ username นี้
+		identifiers := []string{NormalizeLoginIdentifier(user.Email), NormalizeLoginIdentifier(user.Username)}
+		if err := tx.Where("user_id = ? OR identifier IN ?", userID, identifiers).Delete(&models.LoginAttempt{}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&models.Session{},
+			&models.PersonalAccessToken{},
+			&models.UserIdentity{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.User{}, userID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	RemoveUserUploads(userID)
+	for _, id := range authored {
+		RemoveArticleOGImages(id)
+	}
+	if mode == models.DeletionModeRemove {
+		deleteObjects(userMediaPrefix(userID))
+	}
+	return nil
+}
+
+// key ของไฟล์ avatar ทั้งหมดของผู้ใช้ (ชื่อไฟล์ขึ้นต้นด้วย user_<id>_)
+func userAvatarPrefix(userID uint) string {
+	return fmt.Sprintf("%suser_%d_", avatarPrefix, userID)
+}
+
+// ไฟล์ที่ผู้ใช้อัปโหลดไว้ทั้งหมดใน storage (avatar และรูปในบทความ)
+func UserUploadFiles(userID uint) ([]storage.Object, error) {
+	avatars, err := storage.Default().List(context.Background(), userAvatarPrefix(userID))
+	if err != nil {
+		return nil, err
+	}
+	media, err := storage.Default().List(context.Background(), userMediaPrefix(userID))
+	if err != nil {
+		return nil, err
+	}
+	return append(avatars, media...), nil
+}
+
+// ลบ avatar ของผู้ใช้ รูปในบทความจัดการแยกตามโหมดการลบบัญชี
+func RemoveUserUploads(userID uint) {
+	deleteObjects(userAvatarPrefix(userID))
+}
+
+// ลบทุกไฟล์ที่ key ขึ้นต้นด้วย prefix
+func deleteObjects(prefix string) {
+	ctx := context.Background()
+	objects, err := storage.Default().List(ctx, prefix)
+	if err != nil {
+		return
+	}
+	for _, obj := range objects {
+		storage.Default().Delete(ctx, obj.Key)
+	}
+}
@@ -3,16 +3,21 @@ package composables
 import (
 	"backend/database"
 	"backend/models"
+	"backend/repository"
 	"errors"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 // ดึง userID จาก token
 func GetCurrentUserID(c *fiber.Ctx) (uint, error) {
-	userToken := c.Locals("user").(*jwt.Token)
+	// middleware.Protected ตั้งค่า userID ไว้แล้วทั้งกรณี JWT และ personal access token
+	if id, ok := c.Locals("userID").(uint); ok {
+		return id, nil
+	}
+	userToken, _ := c.Locals("user").(*jwt.Token)
 	if userToken == nil {
 		return 0, errors.New("missing user token")
 	}
@@ -24,41 +29,13 @@ func GetCurrentUserID(c *fiber.Ctx) (uint, error) {
 	return uint(id), nil
 }
 
-// ค้นหาหรือสร้าง category
-func FindOrCreateCategory(name string) (*models.Category, error) {
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return nil, errors.New("empty category name")
-	}
-	var category models.Category
-	if err := database.DB.Where("name = ?", name).First(&category).Error; err == nil {
-		return &category, nil
-	}
-	category = models.Category{Name: name}
-	if err := database.DB.Create(&category).Error; err != nil {
-		return nil, err
-	}
-	return &category, nil
-}
-
-// ค้นหาหรือสร้าง tag หลายตัว
-func FindOrCreateTags(names []string) ([]models.Tags, error) {
-	var tags []models.Tags
-	for _, name := range names {
-		name = strings.TrimSpace(name)
-		if name == "" {
-			continue
-		}
-		var tag models.Tags
-		if err := database.DB.Where("name = ?", name).First(&tag).Error; err == nil {
-			tags = append(tags, tag)
-		} else {
-			tag = models.Tags{Name: name}
-			if err := database.DB.Create(&tag).Error; err != nil {
-				return nil, err
-			}
-			tags = append(tags, tag)
-		}
-	}
-	return tags, nil
+// ArticleListQuery คือ query รายการบทความสาธารณะเรียงจากใหม่ไปเก่า
+// หน้ารายการบทความ feed และ sitemap ใช้เงื่อนไขเดียวกันเพื่อให้ได้บทความชุดเดียวกัน
+func ArticleListQuery(filter repository.ArticleFilter) *gorm.DB {
+	tx := repository.FilterArticles(database.DB.Model(&models.Article{}), filter).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Preload("Cover")
+	return tx.Order("articles.created_at DESC")
 }
@@ -0,0 +1,35 @@
+package composables
+
+import (
+	"backend/models"
+	"backend/repository"
+
+	"gorm.io/gorm"
+)
+
+// ArticleHooks คืองานที่ GormArticles ต้องทำทุกครั้งที่บันทึกหรือลบบทความ
+var ArticleHooks repository.ArticleHooks = articleHooks{}
+
+type articleHooks struct{}
+
+func (articleHooks) SitemapGroups(tx *gorm.DB, article *models.Article) []models.SitemapEntry {
+	return ArticleSitemapGroups(tx, article)
+}
+
+// จำว่าบทความใช้รูปใดบ้าง รูปที่ถูกใช้อยู่จะไม่ถูกลบ
+func (articleHooks) Saving(tx *gorm.DB, article *models.Article) error {
+	return SyncArticleMedia(tx, article)
+}
+
+func (articleHooks) Deleting(tx *gorm.DB, article *models.Article) error {
+	return DetachArticleMedia(tx, []uint{article.ID})
+}
+
+func (articleHooks) Saved(db *gorm.DB, articleID uint, before []models.SitemapEntry) {
+	RefreshArticleSitemap(db, articleID, before)
+}
+
+func (articleHooks) Deleted(db *gorm.DB, article *models.Article, groups []models.SitemapEntry) {
+	RemoveArticleOGImages(article.ID)
+	RemoveArticleSitemap(db, article.Slug, groups)
+}
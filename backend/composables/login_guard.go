@@ -0,0 +1,169 @@
+package composables
+
+import (
+	"backend/database"
+	"backend/metrics"
+	"backend/models"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// จำนวนครั้งที่ใส่รหัสผิดติดกันก่อนเริ่มล็อกบัญชี
+	accountFailureThreshold = 5
+	// จำนวนครั้งที่ใส่รหัสผิดจาก IP เดียวกันก่อนเริ่มล็อก IP
+	ipFailureThreshold = 20
+
+	accountFailureWindow = 24 * time.Hour
+	ipFailureWindow      = time.Hour
+
+	lockoutBaseDelay = time.Minute
+	lockoutMaxDelay  = time.Hour
+
+	// ประวัติที่พ้นช่วงนับการล็อกแล้วเก็บไว้อีกนานเท่านี้ให้แอดมินดูย้อนหลัง
+	loginAttemptRetention = 30 * 24 * time.Hour
+)
+
+// normalize identifier so "Admin@Example.com " and "admin@example.com" share one counter
+func NormalizeLoginIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// BeginLoginAttempt checks the lockout and records the attempt as a failure in
+// one transaction, before the password is checked. Parallel guesses are
+// serialized on the account row, so each one sees the failures recorded by
+// the ones before it. Attempts are counted per account when attempt.UserID is
+// set, otherwise per identifier, so the email and the username of an account
+// share one budget and unknown identifiers are locked the same way.
+// A positive wait means the attempt was recorded as blocked and must be
+// rejected; otherwise call FinishLoginAttempt with the result.
+func BeginLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) (time.Duration, error) {
+	var wait time.Duration
+	err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		column, value := "identifier", any(attempt.Identifier)
+		if attempt.UserID != nil {
+			column, value = "user_id", *attempt.UserID
+			var user models.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, *attempt.UserID).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		accountWait, err := lockRemaining(tx, column, value, accountFailureThreshold, accountFailureWindow, true, now)
+		if err != nil {
+			return err
+		}
+		ipWait, err := lockRemaining(tx, "ip", attempt.IP, ipFailureThreshold, ipFailureWindow, false, now)
+		if err != nil {
+			return err
+		}
+		wait = max(accountWait, ipWait)
+
+		attempt.Blocked = wait > 0
+		attempt.Success = false
+		return tx.Create(attempt).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	if attempt.Blocked {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginBlocked).Inc()
+	}
+	return wait, nil
+}
+
+// FinishLoginAttempt records the result of an attempt started with
+// BeginLoginAttempt. A failure is already recorded, so only success writes.
+func FinishLoginAttempt(ctx context.Context, attempt *models.LoginAttempt, success bool) error {
+	if !success {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginFailure).Inc()
+		return nil
+	}
+	attempt.Success = true
+	if err := database.DB.WithContext(ctx).Model(attempt).Update("success", true).Error; err != nil {
+		return err
+	}
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+	return nil
+}
+
+// RecordLoginAttempt บันทึกผลการเข้าสู่ระบบที่ไม่ผ่านการตรวจรหัสผ่าน เช่น OIDC
+func RecordLoginAttempt(ctx context.Context, attempt models.LoginAttempt) error {
+	if err := database.DB.WithContext(ctx).Create(&attempt).Error; err != nil {
+		return err
+	}
+	result := metrics.LoginFailure
+	switch {
+	case attempt.Blocked:
+		result = metrics.LoginBlocked
+	case attempt.Success:
+		result = metrics.LoginSuccess
+	}
+	metrics.LoginAttempts.WithLabelValues(result).Inc()
+	return nil
+}
+
+// PruneLoginAttempts ลบประวัติการเข้าสู่ระบบที่เก่ากว่าช่วงนับการล็อกรวมกับระยะเวลาที่เก็บไว้ คืนจำนวนแถวที่ลบ
+func PruneLoginAttempts(now time.Time) (int64, error) {
+	cutoff := now.Add(-max(accountFailureWindow, ipFailureWindow) - loginAttemptRetention)
+	result := database.DB.Where("created_at < ?", cutoff).Delete(&models.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
+
+// คำนวณเวลาล็อกแบบ exponential backoff จากจำนวนครั้งที่ผิดในช่วงเวลาที่กำหนด
+func lockRemaining(tx *gorm.DB, column string, value any, threshold int64, window time.Duration, resetOnSuccess bool, now time.Time) (time.Duration, error) {
+	since := now.Add(-window)
+
+	// ถ้าเข้าสู่ระบบสำเร็จล่าสุดหลังจากช่วงเวลานี้ ให้เริ่มนับใหม่
+	if resetOnSuccess {
+		var lastSuccess models.LoginAttempt
+		err := tx.
+			Where(column+" = ? AND success = ?", value, true).
+			Order("created_at DESC").
+			First(&lastSuccess).Error
+		if err == nil && lastSuccess.CreatedAt.After(since) {
+			since = lastSuccess.CreatedAt
+		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+	}
+
+	// ไม่นับครั้งที่ถูกบล็อกไปแล้ว ไม่งั้นผู้โจมตีจะล็อกบัญชีคนอื่นได้ไม่มีวันหมด
+	failures := func() *gorm.DB {
+		return tx.Model(&models.LoginAttempt{}).
+			Where(column+" = ? AND success = ? AND blocked = ? AND created_at > ?", value, false, false, since)
+	}
+
+	var count int64
+	if err := failures().Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count < threshold {
+		return 0, nil
+	}
+
+	var lastFailure models.LoginAttempt
+	if err := failures().Order("created_at DESC").First(&lastFailure).Error; err != nil {
+		return 0, err
+	}
+
+	delay := lockoutBaseDelay
+	for i := threshold; i < count && delay < lockoutMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > lockoutMaxDelay {
+		delay = lockoutMaxDelay
+	}
+
+	remaining := lastFailure.CreatedAt.Add(delay).Sub(now)
+	if remaining < 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
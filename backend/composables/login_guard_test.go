@@ -0,0 +1,66 @@
+package composables
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/storage"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// เชื่อมต่อฐานข้อมูล sqlite และ storage แบบ local ใน t.TempDir
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(dir, "blog.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("STORAGE_LOCAL_DIR", filepath.Join(dir, "uploads"))
+	cfg := config.MustLoad(nil)
+	if err := database.Connect(cfg.Database); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if _, err := database.MigrateUp(database.DB); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Init(cfg.Storage); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginAttemptCleanup(t *testing.T) {
+	setupTestDB(t)
+	alice := models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "x"}
+	if err := database.DB.Create(&alice).Error; err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-accountFailureWindow - loginAttemptRetention - time.Hour)
+	attempts := []models.LoginAttempt{
+		{Identifier: "bob@example.com", IP: "10.0.0.1", CreatedAt: old},
+		{Identifier: "bob@example.com", IP: "10.0.0.1", CreatedAt: now},
+		{Identifier: "alice@example.com", UserID: &alice.ID, IP: "10.0.0.2", CreatedAt: now},
+		// ยังไม่รู้ว่าเป็นบัญชีไหน เช่นพิมพ์ username ก่อนบัญชีถูกสร้าง
+		{Identifier: "alice", IP: "10.0.0.2", CreatedAt: now},
+	}
+	if err := database.DB.Create(&attempts).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned, err := PruneLoginAttempts(now); err != nil || pruned != 1 {
+		t.Fatalf("prune: %d, %v", pruned, err)
+	}
+	if err := DeleteAccount(alice.ID, models.DeletionModeRemove); err != nil {
+		t.Fatal(err)
+	}
+	var left []models.LoginAttempt
+	if err := database.DB.Find(&left).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1 || left[0].ID != attempts[1].ID {
+		t.Fatalf("attempts left: %+v", left)
+	}
+}
@@ -0,0 +1,189 @@
+package composables
+
+import (
+	"backend/imaging"
+	"backend/models"
+	"backend/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ความกว้างของรูปที่สร้างไว้ให้ srcset รูปที่เล็กกว่าจะไม่ถูกขยาย
+var MediaWidths = []int{320, 640, 1024, 1600}
+
+// ความกว้างสูงสุดของรูปหลัก (ค่า URL ของ Media) ไม่เก็บไฟล์ต้นฉบับไว้ เพื่อไม่ให้ EXIF/GPS หลุดออกไป
+const mediaMaxWidth = 2048
+
+const mediaPrefix = "media/"
+
+var (
+	ErrMediaTooLarge = errors.New("Image is too large")
+	ErrMediaInvalid  = errors.New("Only JPEG, PNG, GIF and WebP images are allowed")
+)
+
+// key ของไฟล์ media ทั้งหมดของผู้ใช้
+func userMediaPrefix(userID uint) string {
+	return fmt.Sprintf("%suser_%d/", mediaPrefix, userID)
+}
+
+// StoreMedia ตรวจรูป สร้างรูปหลายขนาด และเก็บลง storage คืน Media ที่ยังไม่ได้บันทึกลงฐานข้อมูล
+func StoreMedia(ctx context.Context, userID uint, filename string, data []byte) (*models.Media, error) {
+	img, err := imaging.Load(data)
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return nil, ErrMediaTooLarge
+	}
+	if err != nil {
+		return nil, ErrMediaInvalid
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("%s%d_%s", userMediaPrefix(userID), time.Now().UnixMilli(), hex.EncodeToString(suffix))
+
+	// รูปที่มีพื้นโปร่งใสเก็บเป็น PNG ที่เหลือเป็น JPEG
+	ext, contentType, encode := ".jpg", "image/jpeg", imaging.EncodeJPEG
+	if img.HasAlpha() {
+		ext, contentType, encode = ".png", "image/png", imaging.EncodePNG
+	}
+
+	width, _ := img.Bounds()
+	widths := []int{}
+	for _, w := range MediaWidths {
+		if w < width && w < mediaMaxWidth {
+			widths = append(widths, w)
+		}
+	}
+	widths = append(widths, min(width, mediaMaxWidth))
+
+	media := &models.Media{
+		UserID:      userID,
+		StorageKey:  base,
+		Filename:    Truncate(filename, 255),
+		ContentType: contentType,
+	}
+	store := storage.Default()
+	for _, w := range widths {
+		resized := img.FitWidth(w)
+		encoded, err := encode(resized)
+		if err != nil {
+			RemoveMediaFiles(base)
+			return nil, err
+		}
+		key := fmt.Sprintf("%s_%d%s", base, w, ext)
+		if err := store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), contentType); err != nil {
+			RemoveMediaFiles(base)
+			return nil, err
+		}
+		b := resized.Bounds()
+		media.Variants = append(media.Variants, models.MediaVariant{Width: b.Dx(), Height: b.Dy(), URL: storage.PublicURL(key)})
+		media.Size += int64(len(encoded))
+	}
+
+	// รูปหลักคือขนาดใหญ่สุด
+	largest := media.Variants[len(media.Variants)-1]
+	media.URL, media.Width, media.Height = largest.URL, largest.Width, largest.Height
+	return media, nil
+}
+
+// RemoveMediaFiles ลบไฟล์ทุกขนาดของ media หนึ่งรายการ
+func RemoveMediaFiles(key string) {
+	deleteObjects(key + "_")
+}
+
+// รูปในเนื้อหาบทความ เช่น ![alt](/uploads/media/user_1/1700000000000_ab12cd34_640.jpg)
+var mediaURLPattern = regexp.MustCompile(`/uploads/(media/user_\d+/\d+_[0-9a-f]+)_\d+\.(?:jpg|png)`)
+
+// MediaKeysInContent หา key ของ media ทั้งหมดที่เนื้อหาอ้างถึง
+func MediaKeysInContent(content string) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, m := range mediaURLPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			keys = append(keys, m[1])
+		}
+	}
+	return keys
+}
+
+// SyncArticleMedia บันทึกว่าบทความใช้ media ใดบ้างจากเนื้อหาปัจจุบัน
+// media ที่ถูกใช้อยู่ลบไม่ได้ และ media ที่ไม่มีบทความใดใช้แล้วจะเริ่มนับเวลาก่อนถูกเก็บกวาด
+func SyncArticleMedia(tx *gorm.DB, article *models.Article) error {
+	var previous []uint
+	if err := tx.Table("article_media").Where("article_id = ?", article.ID).Pluck("media_id", &previous).Error; err != nil {
+		return err
+	}
+
+	media := []models.Media{}
+	keys := MediaKeysInContent(article.Content)
+	if len(keys) > 0 || article.CoverMediaID != nil {
+		// รูปปกนับเป็นรูปที่บทความใช้ด้วย
+		used := tx.Session(&gorm.Session{NewDB: true}).Where("storage_key IN ?", keys)
+		if article.CoverMediaID != nil {
+			used = used.Or("id = ?", *article.CoverMediaID)
+		}
+		// นับเฉพาะรูปในคลังของผู้เขียน URL รูปของคนอื่นที่แปะไว้ในเนื้อหาต้องไม่กันเจ้าของลบรูปนั้น
+		if err := tx.Where("user_id = ?", article.AuthorID).Where(used).Find(&media).Error; err != nil {
+			return err
+		}
+	}
+	// เขียนตาราง article_media เอง เพราะ Association().Replace บันทึกบทความซ้ำด้วย updated_at ใหม่
+	if err := tx.Exec("DELETE FROM article_media WHERE article_id = ?", article.ID).Error; err != nil {
+		return err
+	}
+	if len(media) > 0 {
+		ids := make([]uint, len(media))
+		rows := make([]map[string]interface{}, len(media))
+		for i, m := range media {
+			ids[i] = m.ID
+			rows[i] = map[string]interface{}{"article_id": article.ID, "media_id": m.ID}
+		}
+		if err := tx.Table("article_media").Create(rows).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Media{}).Where("id IN ?", ids).Update("detached_at", nil).Error; err != nil {
+			return err
+		}
+	}
+	return MarkDetachedMedia(tx, previous)
+}
+
+// MarkDetachedMedia บันทึกเวลาให้ media ในรายการที่ไม่มีบทความใดใช้แล้ว
+func MarkDetachedMedia(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Media{}).
+		Where("id IN ? AND detached_at IS NULL", ids).
+		Where("id NOT IN (?)", tx.Table("article_media").Select("media_id")).
+		Update("detached_at", time.Now()).Error
+}
+
+// DetachArticleMedia ลบการอ้างถึง media ของบทความที่กำลังจะถูกลบ
+func DetachArticleMedia(tx *gorm.DB, articleIDs interface{}) error {
+	var ids []uint
+	if err := tx.Table("article_media").Where("article_id IN (?)", articleIDs).Pluck("media_id", &ids).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM article_media WHERE article_id IN (?)", articleIDs).Error; err != nil {
+		return err
+	}
+	return MarkDetachedMedia(tx, ids)
+}
+
+// MediaInUse บอกว่ามีบทความใช้ media นี้อยู่หรือไม่
+func MediaInUse(tx *gorm.DB, mediaID uint) (bool, error) {
+	var count int64
+	err := tx.Table("article_media").Where("media_id = ?", mediaID).Count(&count).Error
+	return count > 0, err
+}
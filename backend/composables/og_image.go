@@ -0,0 +1,120 @@
+package composables
+
+import (
+	"backend/imaging"
+	"backend/models"
+	"backend/ogimage"
+	"backend/storage"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+)
+
+// เปลี่ยนค่านี้เมื่อแก้หน้าตาการ์ด เพื่อให้รูปเดิมใน cache ถูกสร้างใหม่ทั้งหมด
+const ogImageLayoutVersion = "1"
+
+// เก็บเป็น private เพราะให้บริการผ่าน /articles/:slug/og.png เท่านั้น
+const ogImagePrefix = "private/og/articles/"
+
+func articleOGImagePrefix(articleID uint) string {
+	return fmt.Sprintf("%s%d-", ogImagePrefix, articleID)
+}
+
+// OGImageRevision คือ hash ของทุกอย่างที่แสดงบนรูป ใช้เป็นชื่อไฟล์ cache และ ETag
+// การแก้เนื้อหาบทความที่ไม่ได้แสดงบนรูปจึงไม่ทำให้ต้องวาดใหม่
+func OGImageRevision(article *models.Article, siteName string) string {
+	avatar := ""
+	if article.Author.Image != nil {
+		avatar = *article.Author.Image
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		ogImageLayoutVersion,
+		article.Title,
+		ArticleAuthorName(article),
+		avatar,
+		article.Category.Name,
+		siteName,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// ArticleAuthorName คือชื่อที่แสดงของผู้เขียน ใช้ nickname ถ้ามี
+func ArticleAuthorName(article *models.Article) string {
+	if article.Author.Nickname != "" {
+		return article.Author.Nickname
+	}
+	return article.Author.Username
+}
+
+// ArticleOGImage คืนรูปแชร์ของบทความตาม revision ปัจจุบัน ถ้ายังไม่มีใน storage จะวาดใหม่และเก็บไว้
+// article ต้อง preload Author และ Category มาแล้ว
+func ArticleOGImage(ctx context.Context, article *models.Article, siteName string) ([]byte, error) {
+	revision := OGImageRevision(article, siteName)
+	key := articleOGImagePrefix(article.ID) + revision + ".png"
+	store := storage.Default()
+
+	if r, _, err := store.Get(ctx, key); err == nil {
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := ogimage.Render(ogimage.Card{
+		Title:    article.Title,
+		Author:   ArticleAuthorName(article),
+		Category: article.Category.Name,
+		SiteName: siteName,
+		Avatar:   loadAvatarImage(ctx, article.Author.Image),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// รูปของ revision ก่อนหน้าไม่ถูกใช้แล้ว
+	RemoveArticleOGImages(article.ID)
+	if err := store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		slog.WarnContext(ctx, "failed to cache og image", "article_id", article.ID, "error", err)
+	}
+	return data, nil
+}
+
+// RemoveArticleOGImages ลบรูปแชร์ทุก revision ของบทความ
+func RemoveArticleOGImages(articleID uint) {
+	deleteObjects(articleOGImagePrefix(articleID))
+}
+
+// โหลด avatar จาก storage ถ้าไม่มีหรืออ่านไม่ได้การ์ดจะแสดงวงกลมว่างแทน
+func loadAvatarImage(ctx context.Context, url *string) image.Image {
+	if url == nil {
+		return nil
+	}
+	key, ok := storage.KeyFromURL(*url)
+	if !ok {
+		return nil
+	}
+	r, _, err := storage.Default().Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, imaging.MaxUploadBytes))
+	if err != nil {
+		return nil
+	}
+	img, err := imaging.Load(data)
+	if err != nil {
+		return nil
+	}
+	return img.Square(ogimage.AvatarSize)
+}
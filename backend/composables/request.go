@@ -0,0 +1,66 @@
+package composables
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIP คืน IP ของผู้ใช้ ถ้ามาจาก proxy ที่เชื่อถือได้แต่ไม่มี X-Real-IP ให้ใช้ IP ของการเชื่อมต่อแทน
+func ClientIP(c *fiber.Ctx) string {
+	if ip := c.IP(); ip != "" {
+		return ip
+	}
+	return c.Context().RemoteIP().String()
+}
+
+// NotModified ตรวจ If-None-Match และ If-Modified-Since ของ request กับ ETag และเวลาแก้ไขล่าสุดของ response
+// ถ้ามี If-None-Match จะไม่ดู If-Modified-Since ตาม RFC 9110
+func NotModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if since := c.Get(fiber.HeaderIfModifiedSince); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// VersionETag คือ ETag ของบทความหรือคอมเมนต์ใน version นี้
+func VersionETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// IfMatchVersion อ่าน version จาก If-Match ที่ได้มาจาก VersionETag ok เป็น false ถ้าไม่มี header นี้หรือเป็น *
+// ค่าอื่นที่ไม่ใช่ ETag ของเรา เช่น weak ETag ได้ version 0 ซึ่งไม่ตรงกับ version ใดเลย
+func IfMatchVersion(c *fiber.Ctx) (version uint, ok bool) {
+	match := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if match == "" || IfMatchAny(c) {
+		return 0, false
+	}
+	tag, _, _ := strings.Cut(match, ",")
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, true
+	}
+	n, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 0)
+	if err != nil {
+		return 0, true
+	}
+	return uint(n), true
+}
+
+// IfMatchAny บอกว่า If-Match เป็น * คือแก้ได้ไม่ว่าข้อมูลจะอยู่ใน version ไหน
+func IfMatchAny(c *fiber.Ctx) bool {
+	return strings.TrimSpace(c.Get(fiber.HeaderIfMatch)) == "*"
+}
@@ -0,0 +1,81 @@
+package composables
+
+import (
+	"backend/config"
+	"backend/models"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SiteURL คือ URL หน้าเว็บจริงที่ผู้ใช้เปิด (ไม่มี / ท้าย) ใช้สร้างลิงก์แบบเต็มให้ Open Graph, feed และ sitemap
+func SiteURL() string {
+	return config.Get().Site.URL
+}
+
+// AbsoluteURL เติม SiteURL หน้า path ที่ยังไม่ใช่ URL เต็ม
+func AbsoluteURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return SiteURL() + path
+}
+
+// ArticleURL คือ URL ของหน้าบทความบนเว็บ
+func ArticleURL(article *models.Article) string {
+	if article.CanonicalURL != "" {
+		return article.CanonicalURL
+	}
+	return SiteURL() + "/articles/" + article.Slug
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// PlainText เอา HTML tag ออกและรวมช่องว่าง
+func PlainText(content string) string {
+	text := htmlTagPattern.ReplaceAllString(content, " ")
+	text = html.UnescapeString(text)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// Summarize ตัดเนื้อหาเป็นข้อความสั้นไม่เกิน max ตัวอักษร โดยพยายามตัดที่ช่องว่าง
+func Summarize(content string, max int) string {
+	text := PlainText(content)
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)[:max]
+	cut := string(runes)
+	// ภาษาไทยไม่เว้นวรรคระหว่างคำ ตัดที่ช่องว่างเฉพาะเมื่อไม่เสียข้อความไปมากเกินไป
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
+
+// ArticleDescription คือคำอธิบายบทความ เรียงตาม SEO description, excerpt แล้วจึงตัดจากเนื้อหา
+func ArticleDescription(article *models.Article) string {
+	switch {
+	case article.SEODescription != "":
+		return article.SEODescription
+	case article.Excerpt != "":
+		return article.Excerpt
+	default:
+		return Summarize(article.Content, 160)
+	}
+}
+
+// ลิงก์และรูปใน HTML ที่เป็น path ของเว็บเอง เช่น src="/uploads/..." (ไม่รวม //host)
+var relativeURLPattern = regexp.MustCompile(`\b(src|href)="(/[^/"][^"]*|/)"`)
+
+// AbsoluteHTML เปลี่ยนลิงก์และรูปในเนื้อหาให้เป็น URL เต็ม สำหรับเนื้อหาที่ถูกอ่านนอกเว็บ เช่น feed reader
+func AbsoluteHTML(content string) string {
+	return relativeURLPattern.ReplaceAllString(content, `$1="`+SiteURL()+`$2"`)
+}
@@ -0,0 +1,74 @@
+package composables
+
+import (
+	"backend/database"
+	"backend/keys"
+	"backend/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// บันทึก last_seen_at ไม่ถี่กว่านี้
+const sessionTouchInterval = time.Minute
+
+// สร้าง session ใหม่สำหรับการ login ครั้งนี้
+func CreateSession(c *fiber.Ctx, userID uint) (*models.Session, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := models.Session{
+		UserID:     userID,
+		TokenID:    hex.EncodeToString(buf),
+		UserAgent:  Truncate(c.Get(fiber.HeaderUserAgent), 255),
+		IP:         ClientIP(c),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(keys.TokenTTL()),
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ตรวจว่า session ยังไม่ถูกยกเลิกหรือหมดอายุ และอัปเดตเวลาที่เห็นล่าสุด
+func TouchSession(tokenID string) (*models.Session, error) {
+	var session models.Session
+	if err := database.DB.Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return nil, errors.New("session not found")
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return nil, errors.New("session is no longer active")
+	}
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		database.DB.Model(&session).UpdateColumn("last_seen_at", now)
+		session.LastSeenAt = now
+	}
+	return &session, nil
+}
+
+// PruneSessions ลบ session ที่ถูกยกเลิกหรือหมดอายุแล้ว คืนจำนวนแถวที่ลบ
+// token ของ session ที่ถูกลบยังใช้ไม่ได้เหมือนเดิมเพราะ TouchSession หา session ไม่เจอ
+func PruneSessions(now time.Time) (int64, error) {
+	result := database.DB.Where("revoked_at IS NOT NULL OR expires_at < ?", now).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
+
+// ตัดข้อความให้ไม่เกินความยาวคอลัมน์ นับเป็น byte แต่ไม่ตัดกลางตัวอักษร เช่นภาษาไทยที่ใช้ 3 byte ต่อตัว
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
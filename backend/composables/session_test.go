@@ -0,0 +1,56 @@
+package composables
+
+import (
+	"backend/database"
+	"backend/models"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"Mozilla/5.0", 255, "Mozilla/5.0"},
+		{"Mozilla/5.0", 7, "Mozilla"},
+		// ตัวอักษรไทยใช้ 3 byte ตัดที่ 7 byte ต้องได้สองตัวแรก ไม่ใช่ครึ่งตัวที่สาม
+		{"สวัสดี.jpg", 7, "สว"},
+		{"สวัสดี.jpg", 6, "สว"},
+		{"สวัสดี.jpg", 2, ""},
+	} {
+		got := Truncate(tt.in, tt.max)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestPruneSessions(t *testing.T) {
+	setupTestDB(t)
+	user := models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "x"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	sessions := []models.Session{
+		{UserID: user.ID, TokenID: "active", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: user.ID, TokenID: "revoked", LastSeenAt: now, ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+		{UserID: user.ID, TokenID: "expired", LastSeenAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	}
+	if err := database.DB.Create(&sessions).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned, err := PruneSessions(now); err != nil || pruned != 2 {
+		t.Fatalf("prune: %d, %v", pruned, err)
+	}
+	if _, err := TouchSession("active"); err != nil {
+		t.Fatalf("active session: %v", err)
+	}
+	if _, err := TouchSession("revoked"); err == nil {
+		t.Fatal("revoked session: want an error")
+	}
+}
@@ -0,0 +1,133 @@
+package composables
+
+import (
+	"backend/models"
+	"backend/repository"
+	"log/slog"
+	"net/url"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SitemapPages คือหน้าของ frontend ที่อยู่ใน sitemap เมื่อมีบทความอย่างน้อยหนึ่งบทความ
+var SitemapPages = []string{"/", "/articles"}
+
+// SitemapLoc คือ URL เต็มของรายการใน sitemap หน้ารายการใช้ query เดียวกับที่หน้า /articles อ่าน
+func SitemapLoc(entry models.SitemapEntry) string {
+	switch entry.Kind {
+	case models.SitemapKindArticle:
+		return SiteURL() + "/articles/" + url.PathEscape(entry.Identifier)
+	case models.SitemapKindCategory:
+		return SiteURL() + "/articles?category_id=" + url.QueryEscape(entry.Identifier)
+	case models.SitemapKindTag:
+		return SiteURL() + "/articles?tag=" + url.QueryEscape(entry.Identifier)
+	case models.SitemapKindAuthor:
+		return SiteURL() + "/articles?author=" + url.QueryEscape(entry.Identifier)
+	}
+	return SiteURL() + entry.Identifier
+}
+
+// ArticleInSitemap บอกว่าบทความควรอยู่ใน sitemap หรือไม่ บทความที่ canonical ชี้ไปเว็บอื่นไม่ต้องใส่
+func ArticleInSitemap(article *models.Article) bool {
+	return article.CanonicalURL == "" || article.CanonicalURL == SiteURL()+"/articles/"+article.Slug
+}
+
+// ArticleSitemapGroups คือหน้ารายการทั้งหมดที่บทความปรากฏ (หน้าหลัก หมวดหมู่ แท็ก และผู้เขียน)
+// article ต้อง preload Tags มาแล้ว ส่วน username ของผู้เขียนจะโหลดให้ถ้ายังไม่มี
+func ArticleSitemapGroups(db *gorm.DB, article *models.Article) []models.SitemapEntry {
+	groups := []models.SitemapEntry{}
+	for _, page := range SitemapPages {
+		groups = append(groups, models.SitemapEntry{Kind: models.SitemapKindPage, Identifier: page})
+	}
+	groups = append(groups, models.SitemapEntry{Kind: models.SitemapKindCategory, Identifier: strconv.FormatUint(uint64(article.CategoryID), 10)})
+	for _, tag := range article.Tags {
+		groups = append(groups, models.SitemapEntry{Kind: models.SitemapKindTag, Identifier: tag.Name})
+	}
+	author := article.Author
+	if author.ID != article.AuthorID {
+		db.Select("id", "username").First(&author, article.AuthorID)
+	}
+	if author.Username != "" {
+		groups = append(groups, models.SitemapEntry{Kind: models.SitemapKindAuthor, Identifier: author.Username})
+	}
+	return groups
+}
+
+// RefreshArticleSitemap อัปเดตรายการของบทความหนึ่งบทความและหน้ารายการที่เกี่ยวข้อง โดยไม่สร้าง sitemap ใหม่ทั้งหมด
+// before คือหน้ารายการที่บทความเคยอยู่ก่อนแก้ไข (เช่นแท็กที่ถูกเอาออก) ส่ง nil ถ้าเป็นบทความใหม่
+func RefreshArticleSitemap(db *gorm.DB, articleID uint, before []models.SitemapEntry) {
+	var article models.Article
+	if err := db.Preload("Tags").Preload("Author").First(&article, articleID).Error; err != nil {
+		slog.Error("failed to refresh sitemap", "error", err)
+		return
+	}
+	entry := models.SitemapEntry{Kind: models.SitemapKindArticle, Identifier: article.Slug, LastMod: article.UpdatedAt}
+	var err error
+	if ArticleInSitemap(&article) {
+		err = upsertSitemapEntry(db, entry)
+	} else {
+		err = deleteSitemapEntry(db, entry)
+	}
+	if err != nil {
+		slog.Error("failed to refresh sitemap", "error", err)
+	}
+	refreshSitemapGroups(db, append(before, ArticleSitemapGroups(db, &article)...))
+}
+
+// RemoveArticleSitemap ลบรายการของบทความที่ถูกลบ และอัปเดตหน้ารายการที่บทความเคยอยู่
+func RemoveArticleSitemap(db *gorm.DB, slug string, groups []models.SitemapEntry) {
+	if err := deleteSitemapEntry(db, models.SitemapEntry{Kind: models.SitemapKindArticle, Identifier: slug}); err != nil {
+		slog.Error("failed to refresh sitemap", "error", err)
+	}
+	refreshSitemapGroups(db, groups)
+}
+
+// lastmod ของหน้ารายการคือเวลาแก้ไขล่าสุดของบทความในหน้านั้น หน้าที่ไม่มีบทความแล้วจะถูกเอาออก
+func refreshSitemapGroups(db *gorm.DB, groups []models.SitemapEntry) {
+	seen := map[models.SitemapEntry]bool{}
+	for _, group := range groups {
+		if seen[group] {
+			continue
+		}
+		seen[group] = true
+
+		var filter repository.ArticleFilter
+		switch group.Kind {
+		case models.SitemapKindCategory:
+			filter.CategoryID = group.Identifier
+		case models.SitemapKindTag:
+			filter.Tag = group.Identifier
+		case models.SitemapKindAuthor:
+			filter.Author = group.Identifier
+		}
+		var latest models.Article
+		result := repository.FilterArticles(db.Model(&models.Article{}), filter).
+			Select("articles.updated_at").
+			Order("articles.updated_at DESC").
+			Limit(1).
+			Find(&latest)
+		err := result.Error
+		if err == nil && result.RowsAffected == 0 {
+			err = deleteSitemapEntry(db, group)
+		} else if err == nil {
+			group.LastMod = latest.UpdatedAt
+			err = upsertSitemapEntry(db, group)
+		}
+		if err != nil {
+			slog.Error("failed to refresh sitemap", "error", err)
+		}
+	}
+}
+
+func upsertSitemapEntry(db *gorm.DB, entry models.SitemapEntry) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "identifier"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_mod"}),
+	}).Create(&entry).Error
+}
+
+func deleteSitemapEntry(db *gorm.DB, entry models.SitemapEntry) error {
+	return db.Where("kind = ? AND identifier = ?", entry.Kind, entry.Identifier).Delete(&models.SitemapEntry{}).Error
+}
@@ -0,0 +1,57 @@
+package composables
+
+import (
+	"backend/database"
+	"backend/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ทุก personal access token ขึ้นต้นด้วย prefix นี้ middleware ใช้แยกออกจาก JWT
+const PersonalAccessTokenPrefix = "bob_pat_"
+
+// บันทึก last_used_at ไม่ถี่กว่านี้ เพื่อไม่ให้ทุก request ต้องเขียนฐานข้อมูล
+const tokenTouchInterval = time.Minute
+
+// สร้าง token ใหม่ คืนค่า token จริง (แสดงให้ผู้ใช้ครั้งเดียว) และ hash ที่ใช้เก็บ
+func GeneratePersonalAccessToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw := PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashPersonalAccessToken(raw), nil
+}
+
+// token สุ่มมี entropy สูงพอ จึงใช้ SHA-256 ได้โดยไม่ต้องใช้ bcrypt
+func HashPersonalAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// ค้นหา token ที่ยังใช้งานได้ และอัปเดตเวลาที่ใช้ล่าสุด
+func FindPersonalAccessToken(raw string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(raw, PersonalAccessTokenPrefix) {
+		return nil, errors.New("not a personal access token")
+	}
+
+	var token models.PersonalAccessToken
+	if err := database.DB.Where("token_hash = ?", HashPersonalAccessToken(raw)).First(&token).Error; err != nil {
+		return nil, errors.New("token not found")
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, errors.New("token expired")
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > tokenTouchInterval {
+		database.DB.Model(&token).UpdateColumn("last_used_at", now)
+		token.LastUsedAt = &now
+	}
+	return &token, nil
+}
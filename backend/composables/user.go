@@ -2,34 +2,39 @@ package composables
 
 import (
 	"backend/database"
+	"backend/imaging"
 	"backend/models"
+	"backend/storage"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
-// check if email exists
-func IsEmailExists(email string) (bool, error) {
-	var user models.User
-	if err := database.DB.Where("email = ?", email).First(&user).Error; err == nil {
-		return true, nil
-	}
-	return false, nil
+// หา username ที่ยังไม่มีคนใช้และไม่ได้สงวนไว้ โดยเติมตัวเลขต่อท้ายถ้าซ้ำ
+func UniqueUsername(db *gorm.DB, base string) string {
+	return uniqueUsername(db, base, models.ReservedUsername)
 }
 
-// check if username exists
-func IsUsernameExists(username string) (bool, error) {
-	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err == nil {
-		return true, nil
+func uniqueUsername(db *gorm.DB, base string, reserved func(string) bool) string {
+	base = strings.ToLower(strings.TrimSpace(base))
+	candidate := base
+	for i := 2; ; i++ {
+		var count int64
+		db.Model(&models.User{}).Where("username = ?", candidate).Count(&count)
+		if count == 0 && !reserved(candidate) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
 	}
-	return false, nil
 }
 
 // get user by id
@@ -43,6 +48,9 @@ func GetUserByID(id uint) (*models.User, error) {
 
 // get user id from jwt
 func ExtractUserIDFromJWT(c *fiber.Ctx) (uint, error) {
+	if id, ok := c.Locals("userID").(uint); ok {
+		return id, nil
+	}
 	userToken := c.Locals("user")
 	token, ok := userToken.(*jwt.Token)
 	if !ok {
@@ -56,24 +64,98 @@ func ExtractUserIDFromJWT(c *fiber.Ctx) (uint, error) {
 	return uint(idFloat), nil
 }
 
+// ขนาดรูป avatar ที่สร้าง (px) ทุกขนาดมีทั้ง .jpg และ .webp
+var AvatarSizes = []int{512, 256, 96}
+
+// avatar ทั้งหมดเก็บใน storage ภายใต้ key นี้
+const avatarPrefix = "avatars/"
+
+// ขนาดที่เก็บใน User.Image ขนาดอื่นหาได้จากการเปลี่ยน _256.jpg เป็น _<size>.<jpg|webp>
+const defaultAvatarSize = 256
+
 // upload avatar
 func HandleAvatarUpload(c *fiber.Ctx, userID uint) (*string, error) {
 	file, err := c.FormFile("avatar")
 	if err != nil || file == nil {
 		return nil, nil
 	}
-	if !strings.HasPrefix(file.Header.Get("Content-Type"), "image/") {
-		return nil, errors.New("Invalid image format")
+	if file.Size > imaging.MaxUploadBytes {
+		return nil, errors.New("Image is too large")
+	}
+	f, err := file.Open()
+	if err != nil {
+		return nil, errors.New("Failed to read image")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, imaging.MaxUploadBytes+1))
+	if err != nil {
+		return nil, errors.New("Failed to read image")
 	}
-	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("user_%d_%d%s", userID, time.Now().Unix(), ext)
-	savePath := fmt.Sprintf("./uploads/avatars/%s", filename)
-	if err := os.MkdirAll("./uploads/avatars", os.ModePerm); err != nil {
-		return nil, errors.New("Failed to prepare upload directory")
+
+	img, err := imaging.Load(data)
+	if errors.Is(err, imaging.ErrTooLarge) {
+		return nil, errors.New("Image is too large")
 	}
-	if err := c.SaveFile(file, savePath); err != nil {
-		return nil, errors.New("Failed to save image")
+	if err != nil {
+		return nil, errors.New("Invalid image format")
 	}
-	imagePath := "/uploads/avatars/" + filename
+
+	store := storage.Default()
+	ctx := c.Context()
+
+	// ใช้ชื่อไฟล์และนามสกุลที่เราสร้างเอง ไม่ใช้ชื่อไฟล์จาก client
+	base := fmt.Sprintf("user_%d_%d", userID, time.Now().UnixMilli())
+	for _, size := range AvatarSizes {
+		thumb := img.Square(size)
+		jpg, err := imaging.EncodeJPEG(thumb)
+		if err != nil {
+			RemoveAvatarSet(base)
+			return nil, errors.New("Failed to process image")
+		}
+		webp, err := imaging.EncodeWebP(thumb)
+		if err != nil {
+			RemoveAvatarSet(base)
+			return nil, errors.New("Failed to process image")
+		}
+		key := fmt.Sprintf("%s%s_%d", avatarPrefix, base, size)
+		if err := store.Put(ctx, key+".jpg", bytes.NewReader(jpg), int64(len(jpg)), "image/jpeg"); err != nil {
+			RemoveAvatarSet(base)
+			return nil, errors.New("Failed to save image")
+		}
+		if err := store.Put(ctx, key+".webp", bytes.NewReader(webp), int64(len(webp)), "image/webp"); err != nil {
+			RemoveAvatarSet(base)
+			return nil, errors.New("Failed to save image")
+		}
+	}
+
+	imagePath := storage.PublicURL(fmt.Sprintf("%s%s_%d.jpg", avatarPrefix, base, defaultAvatarSize))
 	return &imagePath, nil
 }
+
+// ลบไฟล์ทุกขนาดของ avatar ชุดเดียวกัน (base คือ user_<id>_<timestamp>)
+func RemoveAvatarSet(base string) {
+	deleteObjects(avatarPrefix + base + "_")
+}
+
+// ลบ avatar เก่าของผู้ใช้ทั้งหมด ยกเว้นชุดที่ current (ค่าใน User.Image) อ้างถึง
+func RemoveOldAvatars(userID uint, current string) {
+	keep := avatarPrefix + AvatarSetBase(current) + "_"
+	objects, err := storage.Default().List(context.Background(), userAvatarPrefix(userID))
+	if err != nil {
+		return
+	}
+	for _, obj := range objects {
+		if !strings.HasPrefix(obj.Key, keep) {
+			storage.Default().Delete(context.Background(), obj.Key)
+		}
+	}
+}
+
+// หาชื่อชุดของ avatar เช่น "/uploads/avatars/user_1_1700000000000_256.jpg" -> "user_1_1700000000000"
+func AvatarSetBase(imagePath string) string {
+	name := strings.TrimSuffix(filepath.Base(imagePath), filepath.Ext(imagePath))
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
@@ -0,0 +1,195 @@
+// Package config รวมค่าตั้งค่าทั้งหมดของ backend ไว้ที่เดียว
+//
+// ค่าถูกอ่านตามลำดับนี้ ค่าที่อ่านทีหลังทับค่าก่อนหน้า
+//
+//  1. ค่าเริ่มต้นใน Default
+//  2. ไฟล์ YAML จาก CONFIG_FILE หรือ config.yaml ในโฟลเดอร์ที่รัน (ถ้ามี)
+//  3. ไฟล์ .env (ถ้ามี) ค่าในไฟล์ไม่ทับตัวแปรที่มีอยู่แล้วใน environment
+//  4. environment เช่น JWT_SECRET และ DB_HOST
+//
+// ดูชื่อคีย์ของ YAML และตัวแปร environment ทั้งหมดได้ที่ config.example.yaml
+//
+// ส่วนที่มี Init ของตัวเอง (database, storage และ keys) รับค่าของส่วนนั้นตอนเริ่มโปรแกรม
+// ส่วนอื่นอ่านค่าผ่าน Get
+package config
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Config คือค่าตั้งค่าทั้งหมดของ backend
+type Config struct {
+	Server   Server   `yaml:"server"`
+	Log      Log      `yaml:"log"`
+	Database Database `yaml:"database"`
+	JWT      JWT      `yaml:"jwt"`
+	Storage  Storage  `yaml:"storage"`
+	Site     Site     `yaml:"site"`
+	OIDC     OIDC     `yaml:"oidc"`
+
+	// รูปที่ไม่มีบทความใช้จะถูกเก็บไว้นานเท่านี้ก่อนลบ เผื่อผู้เขียนยังไม่ได้บันทึกบทความ
+	MediaOrphanGrace time.Duration `yaml:"media_orphan_grace"`
+	// ระยะเวลาผ่อนผันก่อนลบบัญชีจริง ผู้ใช้ยกเลิกได้ภายในช่วงนี้ 0 คือลบในรอบถัดไปเลย
+	AccountDeletionGrace time.Duration `yaml:"account_deletion_grace"`
+	// ฟอนต์ของรูป Open Graph เรียงตามลำดับ fallback ว่างคือใช้ฟอนต์ของระบบ
+	OGFonts []string `yaml:"og_fonts"`
+}
+
+// Server คือค่าของ HTTP server
+type Server struct {
+	Port int `yaml:"port"`
+	// port ของ /metrics แยกจาก API ให้ Prometheus ในเครือข่ายภายในเท่านั้น อย่าเปิดออกนอกเครื่อง 0 คือไม่เปิด
+	MetricsPort int `yaml:"metrics_port"`
+	// เวลาที่รอ request ที่ค้างอยู่และงานเบื้องหลังให้จบหลังได้ SIGTERM ก่อนปิดทิ้ง
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Addr คือ address ที่ server รอรับการเชื่อมต่อ
+func (s Server) Addr() string {
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+// MetricsAddr คือ address ของ /metrics
+func (s Server) MetricsAddr() string {
+	return fmt.Sprintf(":%d", s.MetricsPort)
+}
+
+// Log คือรูปแบบและระดับของ log
+type Log struct {
+	// debug, info, warn หรือ error
+	Level string `yaml:"level"`
+	// json สำหรับส่งเข้าระบบเก็บ log หรือ text สำหรับอ่านเองบนเครื่อง
+	Format string `yaml:"format"`
+}
+
+// Database คือค่าการเชื่อมต่อฐานข้อมูล สำหรับ sqlite ค่า Name คือ path ของไฟล์ฐานข้อมูล
+type Database struct {
+	Driver   string `yaml:"driver"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+	// รัน migration ที่ยังไม่ได้รันตอนเริ่ม server ปิดได้ถ้าต้องการรันเองด้วย cmd/migrate
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// สร้างหมวดหมู่ แท็ก ผู้ใช้ admin และบทความตัวอย่างตอนเริ่ม server สำหรับเครื่องนักพัฒนาเท่านั้น
+	Seed bool `yaml:"seed"`
+	// query ที่ใช้เวลานานกว่านี้ถูกบันทึกเป็น warning 0 คือไม่บันทึก
+	SlowQuery time.Duration `yaml:"slow_query"`
+}
+
+// JWT คือค่าของ token ที่ออกตอน login และ key ที่ใช้เซ็น
+type JWT struct {
+	// Secret ใช้เซ็น token แบบ HS256 และตรวจ token HS256 เดิมหลังเปลี่ยนไปใช้ key แบบ asymmetric
+	Secret string `yaml:"secret"`
+	Alg    string `yaml:"alg"`
+	// อายุของ token และ session
+	TokenTTL time.Duration `yaml:"token_ttl"`
+	// สร้าง key ใหม่เมื่อ key ปัจจุบันเก่ากว่านี้ (RS256 และ EdDSA)
+	KeyRotation time.Duration `yaml:"key_rotation"`
+	// key ที่ retire แล้วยังตรวจ token ได้อีกนานเท่านี้ ต้องไม่น้อยกว่า TokenTTL
+	KeyRetention time.Duration `yaml:"key_retention"`
+}
+
+// Storage คือที่เก็บไฟล์อัปโหลด
+type Storage struct {
+	Driver   string    `yaml:"driver"`
+	LocalDir string    `yaml:"local_dir"`
+	S3       StorageS3 `yaml:"s3"`
+}
+
+// StorageS3 คือค่าของ S3 หรือบริการที่เข้ากันได้อย่าง MinIO
+type StorageS3 struct {
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	Region    string `yaml:"region"`
+	UseSSL    bool   `yaml:"use_ssl"`
+}
+
+// Site คือข้อมูลของหน้าเว็บที่ใช้ใน Open Graph, feed, sitemap และ robots.txt
+type Site struct {
+	// URL หน้าเว็บจริงที่ผู้ใช้เปิด ไม่มี / ท้าย
+	URL  string `yaml:"url"`
+	Name string `yaml:"name"`
+	// เนื้อหาของ feed ถ้าไม่ได้ระบุ ?content= (full หรือ summary)
+	FeedContent    string   `yaml:"feed_content"`
+	RobotsDisallow []string `yaml:"robots_disallow"`
+	// ปิดทั้งเว็บไม่ให้ crawler เข้า เช่นบนเครื่อง staging
+	RobotsNoindex bool `yaml:"robots_noindex"`
+}
+
+// OIDC คือผู้ให้บริการ login ภายนอก
+type OIDC struct {
+	// ถ้าตั้งไว้ ส่งผู้ใช้กลับหน้าเว็บพร้อม token ใน fragment หลัง login สำเร็จ
+	SuccessRedirect string                  `yaml:"success_redirect"`
+	Providers       map[string]OIDCProvider `yaml:"providers"`
+}
+
+// OIDCProvider คือค่าของผู้ให้บริการหนึ่งราย
+type OIDCProvider struct {
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// Default คือค่าเริ่มต้นก่อนอ่านไฟล์และ environment
+func Default() *Config {
+	return &Config{
+		Server: Server{Port: 8080, MetricsPort: 9090, ShutdownTimeout: 15 * time.Second},
+		Log:    Log{Level: "info", Format: "text"},
+		Database: Database{
+			Driver:      "mysql",
+			SSLMode:     "disable",
+			AutoMigrate: true,
+			SlowQuery:   200 * time.Millisecond,
+		},
+		JWT: JWT{
+			Alg:          "HS256",
+			TokenTTL:     72 * time.Hour,
+			KeyRotation:  30 * 24 * time.Hour,
+			KeyRetention: 7 * 24 * time.Hour,
+		},
+		Storage: Storage{
+			Driver:   "local",
+			LocalDir: "./uploads",
+			S3:       StorageS3{UseSSL: true},
+		},
+		Site: Site{
+			URL:            "http://localhost",
+			Name:           "BoBlog",
+			FeedContent:    "full",
+			RobotsDisallow: []string{"/api/", "/profile", "/articles/create", "/articles/my-articles"},
+		},
+		MediaOrphanGrace:     7 * 24 * time.Hour,
+		AccountDeletionGrace: 14 * 24 * time.Hour,
+	}
+}
+
+// ค่าที่โหลดด้วย MustLoad ก่อนโหลดคือค่าเริ่มต้น
+var current = Default()
+
+// Get คืนค่าที่โหลดด้วย MustLoad
+func Get() *Config {
+	return current
+}
+
+// MustLoad โหลดค่าด้วย Load แล้วตรวจด้วย check ถ้าไม่ผ่านจะจบโปรแกรม
+// server ตรวจทั้งหมดด้วย (*Config).Validate ส่วนเครื่องมือ command line ตรวจเฉพาะส่วนที่ใช้
+func MustLoad(check func(*Config) error) *Config {
+	cfg, err := Load()
+	if err == nil && check != nil {
+		err = check(cfg)
+	}
+	if err != nil {
+		log.Fatal("❌ Invalid configuration: ", err)
+	}
+	current = cfg
+	return cfg
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// ไฟล์ YAML ที่อ่านถ้าไม่ได้ตั้ง CONFIG_FILE
+const defaultConfigFile = "config.yaml"
+
+// Load อ่านค่าตามลำดับที่อธิบายไว้ในหัว package ยังไม่ตรวจค่าที่จำเป็น ใช้ Validate ตรวจต่อ
+// ไฟล์ .env และ config.yaml ไม่มีก็ได้ แต่ถ้าตั้ง CONFIG_FILE ไว้ไฟล์นั้นต้องมีอยู่
+func Load() (*Config, error) {
+	// .env ไม่ทับตัวแปรที่มีอยู่แล้ว environment จริงจึงมาก่อนเสมอ
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+
+	cfg := Default()
+	path, required := os.LookupEnv("CONFIG_FILE")
+	if !required {
+		path = defaultConfigFile
+	}
+	if err := cfg.readFile(path); err != nil {
+		if required || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if err := cfg.readEnv(); err != nil {
+		return nil, err
+	}
+
+	cfg.Site.URL = strings.TrimSuffix(cfg.Site.URL, "/")
+	for name, p := range cfg.OIDC.Providers {
+		p.Issuer = strings.TrimSuffix(p.Issuer, "/")
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		}
+		cfg.OIDC.Providers[name] = p
+	}
+	return cfg, nil
+}
+
+// คีย์ที่ไม่รู้จักถือเป็น error เพื่อให้เห็นคีย์ที่พิมพ์ผิดตั้งแต่เริ่มโปรแกรม
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	// ชื่อผู้ให้บริการใช้ใน URL เป็นตัวพิมพ์เล็กเสมอ
+	providers := make(map[string]OIDCProvider, len(c.OIDC.Providers))
+	for name, p := range c.OIDC.Providers {
+		providers[strings.ToLower(name)] = p
+	}
+	c.OIDC.Providers = providers
+	return nil
+}
+
+// ตัวแปร environment ที่ตั้งไว้ทับค่าจากไฟล์ ค่าว่างถือว่าไม่ได้ตั้ง ยกเว้นรายการซึ่งค่าว่างคือรายการว่าง
+func (c *Config) readEnv() error {
+	vars := []struct {
+		name string
+		set  func(string) error
+	}{
+		{"PORT", setInt(&c.Server.Port)},
+		{"METRICS_PORT", setInt(&c.Server.MetricsPort)},
+		{"SHUTDOWN_TIMEOUT", setDuration(&c.Server.ShutdownTimeout)},
+
+		{"LOG_LEVEL", setString(&c.Log.Level)},
+		{"LOG_FORMAT", setString(&c.Log.Format)},
+
+		{"DB_DRIVER", setString(&c.Database.Driver)},
+		{"DB_HOST", setString(&c.Database.Host)},
+		{"DB_PORT", setString(&c.Database.Port)},
+		{"DB_USER", setString(&c.Database.User)},
+		{"DB_PASSWORD", setString(&c.Database.Password)},
+		{"DB_NAME", setString(&c.Database.Name)},
+		{"DB_SSLMODE", setString(&c.Database.SSLMode)},
+		{"DB_AUTO_MIGRATE", setBool(&c.Database.AutoMigrate)},
+		{"DB_SEED", setBool(&c.Database.Seed)},
+		{"DB_SLOW_QUERY", setDuration(&c.Database.SlowQuery)},
+
+		{"JWT_SECRET", setString(&c.JWT.Secret)},
+		{"JWT_ALG", setString(&c.JWT.Alg)},
+		{"JWT_TOKEN_TTL", setDuration(&c.JWT.TokenTTL)},
+		{"JWT_KEY_ROTATION", setDuration(&c.JWT.KeyRotation)},
+		{"JWT_KEY_RETENTION", setDuration(&c.JWT.KeyRetention)},
+
+		{"STORAGE_DRIVER", setString(&c.Storage.Driver)},
+		{"STORAGE_LOCAL_DIR", setString(&c.Storage.LocalDir)},
+		{"STORAGE_S3_ENDPOINT", setString(&c.Storage.S3.Endpoint)},
+		{"STORAGE_S3_BUCKET", setString(&c.Storage.S3.Bucket)},
+		{"STORAGE_S3_ACCESS_KEY", setString(&c.Storage.S3.AccessKey)},
+		{"STORAGE_S3_SECRET_KEY", setString(&c.Storage.S3.SecretKey)},
+		{"STORAGE_S3_REGION", setString(&c.Storage.S3.Region)},
+		{"STORAGE_S3_USE_SSL", setBool(&c.Storage.S3.UseSSL)},
+
+		{"SITE_URL", setString(&c.Site.URL)},
+		{"SITE_NAME", setString(&c.Site.Name)},
+		{"FEED_CONTENT", setString(&c.Site.FeedContent)},
+		{"ROBOTS_DISALLOW", setList(&c.Site.RobotsDisallow)},
+		{"ROBOTS_NOINDEX", setBool(&c.Site.RobotsNoindex)},
+
+		{"OIDC_SUCCESS_REDIRECT", setString(&c.OIDC.SuccessRedirect)},
+
+		{"MEDIA_ORPHAN_GRACE", setDuration(&c.MediaOrphanGrace)},
+		{"ACCOUNT_DELETION_GRACE", setDuration(&c.AccountDeletionGrace)},
+		{"OG_FONTS", setList(&c.OGFonts)},
+	}
+
+	var errs []error
+	for _, v := range vars {
+		value, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := v.set(strings.TrimSpace(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.name, err))
+		}
+	}
+	c.readOIDCEnv()
+	return errors.Join(errs...)
+}
+
+// OIDC_PROVIDERS (คั่นด้วย comma) เลือกผู้ให้บริการที่เปิดใช้ เช่น OIDC_PROVIDERS=company
+// แล้วตั้ง OIDC_COMPANY_ISSUER, OIDC_COMPANY_CLIENT_ID, OIDC_COMPANY_CLIENT_SECRET,
+// OIDC_COMPANY_REDIRECT_URL และ OIDC_COMPANY_SCOPES (ไม่บังคับ) ค่าที่ไม่ได้ตั้งใช้ของผู้ให้บริการชื่อเดียวกันในไฟล์ YAML
+func (c *Config) readOIDCEnv() {
+	names, ok := os.LookupEnv("OIDC_PROVIDERS")
+	if !ok {
+		return
+	}
+	providers := map[string]OIDCProvider{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		p := c.OIDC.Providers[name]
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		for key, field := range map[string]*string{
+			"ISSUER":        &p.Issuer,
+			"CLIENT_ID":     &p.ClientID,
+			"CLIENT_SECRET": &p.ClientSecret,
+			"REDIRECT_URL":  &p.RedirectURL,
+		} {
+			if value, ok := os.LookupEnv(prefix + key); ok {
+				*field = strings.TrimSpace(value)
+			}
+		}
+		if scopes, ok := os.LookupEnv(prefix + "SCOPES"); ok {
+			p.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+		}
+		providers[name] = p
+	}
+	c.OIDC.Providers = providers
+}
+
+func setString(field *string) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		*field = value
+		return nil
+	}
+}
+
+func setInt(field *int) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*field = n
+		return nil
+	}
+}
+
+func setBool(field *bool) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		*field = b
+		return nil
+	}
+}
+
+func setDuration(field *time.Duration) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 72h or 30m", value)
+		}
+		*field = d
+		return nil
+	}
+}
+
+// รายการคั่นด้วย comma ค่าว่างคือรายการว่าง
+func setList(field *[]string) func(string) error {
+	return func(value string) error {
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field = list
+		return nil
+	}
+}
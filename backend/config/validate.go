@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+)
+
+// Validate ตรวจค่าทั้งหมดที่ server ต้องใช้ คืนทุกข้อที่ผิดพร้อมกัน
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("PORT must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.MetricsPort < 0 || c.Server.MetricsPort > 65535 {
+		errs = append(errs, fmt.Errorf("METRICS_PORT must be between 0 and 65535, got %d", c.Server.MetricsPort))
+	} else if c.Server.MetricsPort == c.Server.Port {
+		errs = append(errs, errors.New("METRICS_PORT must differ from PORT"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	errs = append(errs, c.Log.Validate(), c.Database.Validate(), c.JWT.Validate(), c.Storage.Validate())
+
+	if u, err := url.Parse(c.Site.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("SITE_URL must be an http or https URL, got %q", c.Site.URL))
+	}
+	if c.Site.FeedContent != "full" && c.Site.FeedContent != "summary" {
+		errs = append(errs, fmt.Errorf("FEED_CONTENT must be full or summary, got %q", c.Site.FeedContent))
+	}
+	names := make([]string, 0, len(c.OIDC.Providers))
+	for name := range c.OIDC.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := c.OIDC.Providers[name]
+		if p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			errs = append(errs, fmt.Errorf("OIDC provider %s needs issuer, client_id and redirect_url", name))
+		}
+	}
+	if c.MediaOrphanGrace <= 0 {
+		errs = append(errs, errors.New("MEDIA_ORPHAN_GRACE must be positive"))
+	}
+	if c.AccountDeletionGrace < 0 {
+		errs = append(errs, errors.New("ACCOUNT_DELETION_GRACE must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate ตรวจระดับและรูปแบบของ log
+func (l Log) Validate() error {
+	var errs []error
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", l.Level))
+	}
+	if l.Format != "json" && l.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be json or text, got %q", l.Format))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate ตรวจค่าการเชื่อมต่อฐานข้อมูล
+func (d Database) Validate() error {
+	if d.SlowQuery < 0 {
+		return errors.New("DB_SLOW_QUERY must not be negative")
+	}
+	switch d.Driver {
+	case "mysql", "postgres":
+		if d.Host == "" || d.Name == "" {
+			return fmt.Errorf("DB_HOST and DB_NAME are required for DB_DRIVER=%s", d.Driver)
+		}
+	case "sqlite":
+		if d.Name == "" {
+			return errors.New("DB_NAME must be the database file path when DB_DRIVER=sqlite")
+		}
+	default:
+		return fmt.Errorf("unknown DB_DRIVER %q, use mysql, postgres or sqlite", d.Driver)
+	}
+	return nil
+}
+
+// Validate ตรวจค่าของ token ไม่รับ JWT_SECRET ว่างเมื่อใช้ HS256
+func (j JWT) Validate() error {
+	var errs []error
+	switch j.Alg {
+	case "HS256":
+		if j.Secret == "" {
+			errs = append(errs, errors.New("JWT_SECRET must not be empty when JWT_ALG=HS256"))
+		}
+	case "RS256", "EdDSA":
+	default:
+		errs = append(errs, fmt.Errorf("unknown JWT_ALG %q, use HS256, RS256 or EdDSA", j.Alg))
+	}
+	if j.TokenTTL <= 0 || j.KeyRotation <= 0 {
+		errs = append(errs, errors.New("JWT_TOKEN_TTL and JWT_KEY_ROTATION must be positive"))
+	}
+	// key ที่ retire แล้วต้องตรวจ token ที่เซ็นไว้ก่อนหน้าได้จนกว่า token จะหมดอายุ
+	if j.KeyRetention < j.TokenTTL {
+		errs = append(errs, fmt.Errorf("JWT_KEY_RETENTION (%s) must not be shorter than JWT_TOKEN_TTL (%s)", j.KeyRetention, j.TokenTTL))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate ตรวจค่าของที่เก็บไฟล์
+func (s Storage) Validate() error {
+	var errs []error
+	switch s.Driver {
+	case "local":
+		if s.LocalDir == "" {
+			errs = append(errs, errors.New("STORAGE_LOCAL_DIR must not be empty"))
+		}
+	case "s3":
+		if s.S3.Endpoint == "" || s.S3.Bucket == "" || s.S3.AccessKey == "" || s.S3.SecretKey == "" {
+			errs = append(errs, errors.New("STORAGE_S3_ENDPOINT, STORAGE_S3_BUCKET, STORAGE_S3_ACCESS_KEY and STORAGE_S3_SECRET_KEY are required for STORAGE_DRIVER=s3"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown STORAGE_DRIVER %q, use local or s3", s.Driver))
+	}
+	return errors.Join(errs...)
+}
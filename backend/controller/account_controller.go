@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะส่งออกข้อมูลส่วนตัวทั้งหมดของผู้ใช้เป็นไฟล์ ZIP
+func ExportAccount(c *fiber.Ctx) error {
+	return service.HandleExportAccount(c)
+}
+
+// คือฟังก์ชันที่จะขอลบบัญชี (ลบจริงหลังช่วงเวลาผ่อนผัน)
+func RequestAccountDeletion(c *fiber.Ctx) error {
+	return service.HandleRequestAccountDeletion(c)
+}
+
+// คือฟังก์ชันที่จะยกเลิกการขอลบบัญชี
+func CancelAccountDeletion(c *fiber.Ctx) error {
+	return service.HandleCancelAccountDeletion(c)
+}
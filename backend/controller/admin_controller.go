@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะดึงประวัติการเข้าสู่ระบบสำหรับแอดมิน
+func GetLoginAttempts(c *fiber.Ctx) error {
+	return service.HandleGetLoginAttempts(c)
+}
+
+// คือฟังก์ชันที่จะนำเข้าบทความจากไฟล์ export ของ WordPress
+func ImportWordPress(c *fiber.Ctx) error {
+	return service.HandleImportBlog(c, "wordpress")
+}
+
+// คือฟังก์ชันที่จะนำเข้าบทความจากไฟล์ export ของ Medium
+func ImportMedium(c *fiber.Ctx) error {
+	return service.HandleImportBlog(c, "medium")
+}
@@ -21,6 +21,16 @@ func GetArticleBySlug(c *fiber.Ctx) error {
 	return service.HandleGetArticleBySlug(c)
 }
 
+// คือฟังก์ชันที่จะดึงข้อมูล Open Graph / Twitter card ของบทความ
+func GetArticleMeta(c *fiber.Ctx) error {
+	return service.HandleGetArticleMeta(c)
+}
+
+// คือฟังก์ชันที่จะสร้างรูปการ์ดสำหรับแชร์บทความ
+func GetArticleOGImage(c *fiber.Ctx) error {
+	return service.HandleGetArticleOGImage(c)
+}
+
 // CRUD Write Article
 // คือฟังก์ชันที่จะดึงข้อมูลบทความของผู้ใช้งานปัจจุบัน
 func GetMyArticles(c *fiber.Ctx) error {
@@ -41,3 +51,13 @@ func UpdateArticle(c *fiber.Ctx) error {
 func DeleteArticle(c *fiber.Ctx) error {
 	return service.HandleDeleteArticle(c)
 }
+
+// คือฟังก์ชันที่จะนำเข้าบทความจากไฟล์ Markdown หรือ ZIP
+func ImportArticles(c *fiber.Ctx) error {
+	return service.HandleImportArticles(c)
+}
+
+// คือฟังก์ชันที่จะส่งออกบทความเป็น ZIP ของไฟล์ Markdown
+func ExportArticles(c *fiber.Ctx) error {
+	return service.HandleExportArticles(c)
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"backend/feed"
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะส่ง feed แบบ RSS 2.0
+func GetRSSFeed(c *fiber.Ctx) error {
+	return service.HandleGetFeed(c, feed.FormatRSS)
+}
+
+// คือฟังก์ชันที่จะส่ง feed แบบ Atom
+func GetAtomFeed(c *fiber.Ctx) error {
+	return service.HandleGetFeed(c, feed.FormatAtom)
+}
+
+// คือฟังก์ชันที่จะส่ง feed แบบ JSON Feed
+func GetJSONFeed(c *fiber.Ctx) error {
+	return service.HandleGetFeed(c, feed.FormatJSON)
+}
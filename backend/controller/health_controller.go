@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะบอกว่า process ยังทำงานอยู่
+func Healthz(c *fiber.Ctx) error {
+	return service.HandleHealthz(c)
+}
+
+// คือฟังก์ชันที่จะบอกว่าพร้อมรับ request (ฐานข้อมูลและ migration)
+func Readyz(c *fiber.Ctx) error {
+	return service.HandleReadyz(c)
+}
+
+// คือฟังก์ชันที่จะส่ง metric ให้ Prometheus
+func GetMetrics(c *fiber.Ctx) error {
+	return service.HandleMetrics(c)
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะส่ง public key สำหรับตรวจ JWT ให้ระบบอื่น
+func GetJWKS(c *fiber.Ctx) error {
+	return service.HandleGetJWKS(c)
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะดึงรูปทั้งหมดในคลังของผู้ใช้
+func ListMedia(c *fiber.Ctx) error {
+	return service.HandleListMedia(c)
+}
+
+// คือฟังก์ชันที่จะอัปโหลดรูปสำหรับใช้ในบทความ
+func UploadMedia(c *fiber.Ctx) error {
+	return service.HandleUploadMedia(c)
+}
+
+// คือฟังก์ชันที่จะดึงข้อมูลรูปตาม id
+func GetMedia(c *fiber.Ctx) error {
+	return service.HandleGetMedia(c)
+}
+
+// คือฟังก์ชันที่จะแก้ไข alt text และ caption ของรูป
+func UpdateMedia(c *fiber.Ctx) error {
+	return service.HandleUpdateMedia(c)
+}
+
+// คือฟังก์ชันที่จะลบรูปที่ไม่มีบทความใช้อยู่
+func DeleteMedia(c *fiber.Ctx) error {
+	return service.HandleDeleteMedia(c)
+}
@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะดึงรายชื่อผู้ให้บริการ login ภายนอก
+func OIDCProviders(c *fiber.Ctx) error {
+	return service.HandleOIDCProviders(c)
+}
+
+// คือฟังก์ชันที่จะพาผู้ใช้ไป login กับผู้ให้บริการ OIDC
+func OIDCLogin(c *fiber.Ctx) error {
+	return service.HandleOIDCLogin(c)
+}
+
+// คือฟังก์ชันที่จะรับผลการ login กลับจากผู้ให้บริการ OIDC
+func OIDCCallback(c *fiber.Ctx) error {
+	return service.HandleOIDCCallback(c)
+}
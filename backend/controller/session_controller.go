@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะดึงรายการอุปกรณ์ที่ login อยู่
+func ListSessions(c *fiber.Ctx) error {
+	return service.HandleListSessions(c)
+}
+
+// คือฟังก์ชันที่จะออกจากระบบจากอุปกรณ์ที่เลือก
+func RevokeSession(c *fiber.Ctx) error {
+	return service.HandleRevokeSession(c)
+}
+
+// คือฟังก์ชันที่จะออกจากระบบจากทุกอุปกรณ์ยกเว้นเครื่องนี้
+func RevokeOtherSessions(c *fiber.Ctx) error {
+	return service.HandleRevokeOtherSessions(c)
+}
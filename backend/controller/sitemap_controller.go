@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะส่ง sitemap.xml หรือ sitemap index ถ้ามี URL มากเกินหนึ่งไฟล์
+func GetSitemap(c *fiber.Ctx) error {
+	return service.HandleGetSitemap(c)
+}
+
+// คือฟังก์ชันที่จะส่งไฟล์ย่อยของ sitemap index
+func GetSitemapPage(c *fiber.Ctx) error {
+	return service.HandleGetSitemapPage(c)
+}
+
+// คือฟังก์ชันที่จะส่ง robots.txt
+func GetRobots(c *fiber.Ctx) error {
+	return service.HandleGetRobots(c)
+}
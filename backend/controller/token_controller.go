@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะดึง personal access token ทั้งหมดของผู้ใช้
+func ListTokens(c *fiber.Ctx) error {
+	return service.HandleListTokens(c)
+}
+
+// คือฟังก์ชันที่จะสร้าง personal access token ใหม่
+func CreateToken(c *fiber.Ctx) error {
+	return service.HandleCreateToken(c)
+}
+
+// คือฟังก์ชันที่จะยกเลิก personal access token
+func RevokeToken(c *fiber.Ctx) error {
+	return service.HandleRevokeToken(c)
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"backend/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// คือฟังก์ชันที่จะส่งไฟล์ที่ผู้ใช้อัปโหลดจากที่เก็บไฟล์
+func ServeUpload(c *fiber.Ctx) error {
+	return service.HandleServeUpload(c)
+}
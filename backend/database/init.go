@@ -1,46 +1,94 @@
 package database
 
 import (
-	"backend/models"
+	"backend/config"
+	"backend/logging"
+	"database/sql"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"net/url"
 
-	"github.com/joho/godotenv"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// ฐานข้อมูลที่รองรับ เลือกด้วย DB_DRIVER ค่าเริ่มต้นคือ mysql
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 var DB *gorm.DB
 
-func Init() {
-	err := godotenv.Load()
+// Connect เชื่อมต่อฐานข้อมูลและตั้ง DB โดยไม่รัน migration ใช้ MigrateUp รันต่อ
+func Connect(cfg config.Database) error {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true, Logger: logging.Gorm(cfg.SlowQuery)})
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		return err
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"))
+	DB = db
+	slog.Info("connected to database", "driver", cfg.Driver)
+	return nil
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// Close ปิด connection ทั้งหมดใน pool ของ DB
+func Close() error {
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return err
 	}
+	return sqlDB.Close()
+}
 
-	DB = db
-	fmt.Println("✅ Connected to database")
-
-	if err := DB.AutoMigrate(
-		&models.User{},
-		&models.Article{},
-		&models.Comment{},
-		&models.Category{},
-		&models.Tags{},
-	); err != nil {
-		log.Fatal("❌ AutoMigrate failed:", err)
+// Dialector สร้างตัวเชื่อมต่อของ driver ตามค่าใน cfg
+// สำหรับ sqlite ค่า Name คือ path ของไฟล์ฐานข้อมูล
+func Dialector(cfg config.Database) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "", DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.User,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.Name)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.User, cfg.Password),
+			Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+			Path:     "/" + cfg.Name,
+			RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+		}
+		return postgres.Open(dsn.String()), nil
+	case DriverSQLite:
+		path := cfg.Name
+		if path == "" {
+			return nil, fmt.Errorf("DB_NAME must be the database file path when DB_DRIVER=sqlite")
+		}
+		// foreign key ต้องเปิดเองทุก connection ส่วน transaction จองสิทธิ์เขียนตั้งแต่เริ่มด้วย sqlitePool
+		params := url.Values{
+			"_pragma":      {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"},
+			"_time_format": {"sqlite"},
+		}
+		conn, err := sql.Open(sqlite.DriverName, path+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		return &sqlite.Dialector{DriverName: sqlite.DriverName, Conn: sqlitePool{conn}}, nil
 	}
+	return nil, fmt.Errorf("unknown DB_DRIVER %q, use mysql, postgres or sqlite", cfg.Driver)
 }
@@ -0,0 +1,553 @@
+package database
+
+import (
+	"backend/database/migrations"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration คือ migration หนึ่งรายการจากไฟล์ใน database/migrations/<driver>
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// สถานะของ migration ที่ status แสดง
+const (
+	MigrationApplied = "applied"
+	MigrationPending = "pending"
+	// รันไปแล้วแต่ไฟล์ถูกแก้ภายหลัง ต้องเขียน migration ใหม่แทนการแก้ไฟล์เดิม
+	MigrationChanged = "changed"
+	// มีในฐานข้อมูลแต่ไม่มีไฟล์ เช่นฐานข้อมูลถูก migrate ด้วยเวอร์ชันที่ใหม่กว่า
+	MigrationMissing = "missing"
+)
+
+// MigrationStatus คือสถานะของ migration หนึ่งรายการ
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+// ชื่อ migration แรกที่สร้างตารางทั้งหมด ฐานข้อมูลเดิมที่สร้างด้วย AutoMigrate จะถูกบันทึกว่าผ่านแล้ว
+const baselineMigration = "baseline"
+
+// ตารางของ baseline ถ้ามีครบแปลว่าฐานข้อมูลถูกสร้างด้วย AutoMigrate ก่อนมี migration
+// ตารางและคอลัมน์ที่เพิ่มทีหลังอยู่ใน migration ของตัวเอง จึงถูกสร้างต่อจาก baseline ตามปกติ
+var baselineTables = []string{"users", "categories", "articles", "tags", "article_tags", "comments"}
+
+// รอ migration ของ instance อื่นได้นานเท่านี้ก่อนยกเลิก
+const migrationLockTimeout = 5 * time.Minute
+
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// คำสั่งที่ต่างกันในแต่ละ driver
+type migrationDialect struct {
+	createTable string
+	// postgres ใช้ $1, $2 แทน ?
+	numbered bool
+	lock     func(ctx context.Context, conn *sql.Conn) error
+	unlock   func(ctx context.Context, conn *sql.Conn) error
+}
+
+// key ของ advisory lock บน postgres ล็อกแยกตามฐานข้อมูลอยู่แล้ว จึงใช้ค่าคงที่ได้
+const postgresMigrationLock = 7238457001
+
+var migrationDialects = map[string]migrationDialect{
+	"mysql": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, " +
+			"checksum CHAR(64) NOT NULL, applied_at DATETIME(3) NOT NULL)",
+		// GET_LOCK ใช้ได้ทั้ง server จึงต่อท้ายด้วยชื่อฐานข้อมูล ล็อกหลุดเองถ้า connection หลุด
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			var ok sql.NullInt64
+			err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(CONCAT(DATABASE(), '.schema_migrations'), ?)",
+				int(migrationLockTimeout.Seconds())).Scan(&ok)
+			if err == nil && ok.Int64 != 1 {
+				err = errors.New("timed out waiting for another instance to finish migrating")
+			}
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.schema_migrations'))")
+			return err
+		},
+	},
+	"postgres": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, " +
+			"checksum CHAR(64) NOT NULL, applied_at TIMESTAMPTZ NOT NULL)",
+		numbered: true,
+		// pg_advisory_lock รอได้ไม่มีกำหนด จึงลองซ้ำเองจนถึง migrationLockTimeout
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			deadline := time.Now().Add(migrationLockTimeout)
+			for {
+				var ok bool
+				if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", postgresMigrationLock).Scan(&ok); err != nil || ok {
+					return err
+				}
+				if time.Now().After(deadline) {
+					return errors.New("timed out waiting for another instance to finish migrating")
+				}
+				time.Sleep(time.Second)
+			}
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", postgresMigrationLock)
+			return err
+		},
+	},
+	"sqlite": {
+		createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+			"version INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, " +
+			"checksum TEXT NOT NULL, applied_at DATETIME NOT NULL)",
+		// ไฟล์ SQLite ใช้กับ instance เดียว และ DDL อยู่ใน transaction ถ้าสองตัวรันพร้อมกันตัวหลังจะล้มและย้อนทั้งหมด
+		lock:   func(context.Context, *sql.Conn) error { return nil },
+		unlock: func(context.Context, *sql.Conn) error { return nil },
+	},
+}
+
+// bind แปลง ? เป็น $n สำหรับ driver ที่ใช้ placeholder แบบมีหมายเลข
+func (d migrationDialect) bind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, ch := range query {
+		if ch == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// LoadMigrations อ่าน migration ของ driver เรียงตาม version
+func LoadMigrations(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrations.FS, driver)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driver)
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		m := migrationFile.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file name %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		data, err := fs.ReadFile(migrations.FS, driver+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, m[2])
+		}
+		// แปลงบรรทัดเป็น \n ก่อนทำ checksum ไฟล์ที่ git checkout เป็น CRLF จะได้ค่าเดียวกัน
+		content := strings.ReplaceAll(string(data), "\r\n", "\n")
+		if m[3] == "up" {
+			migration.Up = content
+			sum := sha256.Sum256([]byte(content))
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = content
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", migration.Version, migration.Name)
+		}
+		list = append(list, *migration)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// migrator ทำงานบน connection เดียวที่ถือล็อกอยู่
+type migrator struct {
+	conn       *sql.Conn
+	dialect    migrationDialect
+	migrations []Migration
+	applied    map[int64]appliedMigration
+}
+
+// ล็อกก่อนอ่านสถานะ instance ที่เริ่มพร้อมกันจะรอจนอีกตัว migrate เสร็จ แล้วเห็นว่าไม่มีอะไรต้องทำ
+func withMigrator(db *gorm.DB, fn func(ctx context.Context, m *migrator) error) error {
+	driver := db.Dialector.Name()
+	dialect, ok := migrationDialects[driver]
+	if !ok {
+		return fmt.Errorf("migrations are not supported for database driver %q", driver)
+	}
+	list, err := LoadMigrations(driver)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	// ดูก่อนล็อก เพราะ Migrator ใช้ connection อื่นจาก pool ถ้าอีก instance สร้างตารางระหว่างนี้ schema_migrations จะไม่ว่างแล้ว
+	var missing []string
+	for _, table := range baselineTables {
+		if !db.Migrator().HasTable(table) {
+			missing = append(missing, table)
+		}
+	}
+	existing := len(missing) == 0
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := dialect.lock(ctx, conn); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer dialect.unlock(ctx, conn)
+
+	if _, err := conn.ExecContext(ctx, dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	m := &migrator{conn: conn, dialect: dialect, migrations: list, applied: map[int64]appliedMigration{}}
+	if err := m.loadApplied(ctx); err != nil {
+		return err
+	}
+	// มีแค่บางตารางแปลว่าไม่ได้สร้างด้วยแอปนี้หรือเสียหาย รันต่อไม่ได้ทั้งบันทึกว่าผ่านแล้วและสร้างตารางซ้ำ
+	if len(m.applied) == 0 && len(missing) > 0 && len(missing) < len(baselineTables) {
+		return fmt.Errorf("database has only part of the baseline schema, missing tables: %s", strings.Join(missing, ", "))
+	}
+	if err := m.adoptExistingSchema(ctx, existing); err != nil {
+		return err
+	}
+	return fn(ctx, m)
+}
+
+func (m *migrator) loadApplied(ctx context.Context) error {
+	rows, err := m.conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return err
+		}
+		m.applied[version] = row
+	}
+	return rows.Err()
+}
+
+// ฐานข้อมูลที่สร้างด้วย AutoMigrate มีตารางครบแล้ว บันทึก baseline ว่าผ่านแล้วแทนการสร้างตารางซ้ำ
+func (m *migrator) adoptExistingSchema(ctx context.Context, existing bool) error {
+	if !existing || len(m.applied) > 0 || len(m.migrations) == 0 || m.migrations[0].Name != baselineMigration {
+		return nil
+	}
+	baseline := m.migrations[0]
+	now := time.Now().UTC()
+	if _, err := m.conn.ExecContext(ctx, m.dialect.bind("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)"),
+		baseline.Version, baseline.Name, baseline.Checksum, now); err != nil {
+		return err
+	}
+	m.applied[baseline.Version] = appliedMigration{name: baseline.Name, checksum: baseline.Checksum, appliedAt: now}
+	slog.Info("existing schema recorded as migration", "version", baseline.Version, "name", baseline.Name)
+	return nil
+}
+
+// ตรวจว่าไฟล์ของ migration ที่รันแล้วไม่ถูกแก้ และฐานข้อมูลไม่ได้ใหม่กว่าโค้ด
+func (m *migrator) verify() error {
+	known := map[int64]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		if row, ok := m.applied[migration.Version]; ok && row.checksum != migration.Checksum {
+			return fmt.Errorf("migration %04d_%s was changed after it was applied, add a new migration instead", migration.Version, migration.Name)
+		}
+	}
+	for version, row := range m.applied {
+		if !known[version] {
+			return fmt.Errorf("database has migration %04d_%s that this build does not know, it was migrated by a newer version", version, row.name)
+		}
+	}
+	return nil
+}
+
+// ส่วนที่รันแล้วถูกบันทึกใน transaction เดียวกัน บน MySQL คำสั่ง DDL commit ทันที
+// ถ้า migration ล้มกลางไฟล์ต้องแก้ฐานข้อมูลให้ตรงกับก่อนรันเองก่อนรันใหม่
+func (m *migrator) run(ctx context.Context, migration Migration, script string, record string, args ...interface{}) error {
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %04d_%s failed: %w\n%s", migration.Version, migration.Name, err, stmt)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, m.dialect.bind(record), args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateUp รัน migration ที่ยังไม่ได้รันทั้งหมดตามลำดับ คืนรายการที่รัน
+func MigrateUp(db *gorm.DB) ([]Migration, error) {
+	var done []Migration
+	err := withMigrator(db, func(ctx context.Context, m *migrator) error {
+		if err := m.verify(); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := m.applied[migration.Version]; ok {
+				continue
+			}
+			err := m.run(ctx, migration, migration.Up,
+				"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+				migration.Version, migration.Name, migration.Checksum, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown ย้อน migration ล่าสุดตามจำนวน steps คืนรายการที่ย้อน
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	var done []Migration
+	err := withMigrator(db, func(ctx context.Context, m *migrator) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := m.applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %04d_%s has no down file", migration.Version, migration.Name)
+			}
+			if err := m.run(ctx, migration, migration.Down, "DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrationsStatus คืนสถานะของทุก migration ทั้งที่มีไฟล์และที่มีเฉพาะในฐานข้อมูล
+func MigrationsStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	var list []MigrationStatus
+	err := withMigrator(db, func(ctx context.Context, m *migrator) error {
+		known := map[int64]bool{}
+		for _, migration := range m.migrations {
+			known[migration.Version] = true
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: MigrationPending}
+			if row, ok := m.applied[migration.Version]; ok {
+				status.State, status.AppliedAt = MigrationApplied, &row.appliedAt
+				if row.checksum != migration.Checksum {
+					status.State = MigrationChanged
+				}
+			}
+			list = append(list, status)
+		}
+		for version, row := range m.applied {
+			if !known[version] {
+				appliedAt := row.appliedAt
+				list = append(list, MigrationStatus{Version: version, Name: row.name, State: MigrationMissing, AppliedAt: &appliedAt})
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+		return nil
+	})
+	return list, err
+}
+
+// CheckMigrations ตรวจว่าทุก migration ของ driver นี้ถูกรันแล้วและไฟล์ไม่ถูกแก้
+// อ่านอย่างเดียวโดยไม่ล็อกและไม่สร้างตาราง จึงเรียกบ่อยจาก /readyz ได้
+// migration ที่มีในฐานข้อมูลแต่ไม่มีไฟล์ไม่นับว่าผิด เพราะ instance เวอร์ชันเก่ายังทำงานได้ระหว่าง deploy
+func CheckMigrations(ctx context.Context, db *gorm.DB) error {
+	list, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	rows, err := db.WithContext(ctx).Raw("SELECT version, checksum FROM schema_migrations").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	applied := map[int64]string{}
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return err
+		}
+		applied[version] = checksum
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var pending, changed []string
+	for _, migration := range list {
+		checksum, ok := applied[migration.Version]
+		switch {
+		case !ok:
+			pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		case checksum != migration.Checksum:
+			changed = append(changed, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+	var errs []error
+	if len(pending) > 0 {
+		errs = append(errs, fmt.Errorf("migrations not applied: %s", strings.Join(pending, ", ")))
+	}
+	if len(changed) > 0 {
+		errs = append(errs, fmt.Errorf("migrations changed after being applied: %s", strings.Join(changed, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+// NewMigration สร้างไฟล์ up/down ว่างของ version ถัดไปในทุกโฟลเดอร์ driver ใต้ dir คืน path ของไฟล์ที่สร้าง
+func NewMigration(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, errors.New("migration name may only contain letters, digits and underscores")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var drivers []string
+	var next int64 = 1
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		drivers = append(drivers, entry.Name())
+		files, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if m := migrationFile.FindStringSubmatch(file.Name()); m != nil {
+				if version, _ := strconv.ParseInt(m[1], 10, 64); version >= next {
+					next = version + 1
+				}
+			}
+		}
+	}
+	if len(drivers) == 0 {
+		return nil, fmt.Errorf("no driver folders in %s", dir)
+	}
+
+	var created []string
+	for _, driver := range drivers {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+			content := fmt.Sprintf("-- %s (%s)\n\n", name, direction)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, path)
+		}
+	}
+	return created, nil
+}
+
+// splitStatements แยกไฟล์ SQL เป็นคำสั่งตาม ; ที่ไม่ได้อยู่ใน string หรือ comment
+// driver ส่วนใหญ่รับทีละคำสั่งถ้าไม่เปิด multiStatements
+func splitStatements(script string) []string {
+	var stmts []string
+	var cur strings.Builder
+	var quote byte
+	flush := func() {
+		if stmt := strings.TrimSpace(cur.String()); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		cur.Reset()
+	}
+	for i := 0; i < len(script); i++ {
+		ch := script[i]
+		switch {
+		case quote != 0:
+			cur.WriteByte(ch)
+			if ch == '\\' && quote != '`' && i+1 < len(script) {
+				i++
+				cur.WriteByte(script[i])
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+			cur.WriteByte(ch)
+		case ch == '$' && strings.HasPrefix(script[i:], "$$"):
+			// body ของ function บน postgres
+			end := strings.Index(script[i+2:], "$$")
+			if end < 0 {
+				end = len(script) - i - 4
+			}
+			cur.WriteString(script[i : i+end+4])
+			i += end + 3
+		case ch == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end
+			cur.WriteByte('\n')
+		case ch == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				end = len(script) - i - 2
+			}
+			i += end + 3
+		case ch == ';':
+			flush()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	flush()
+	return stmts
+}
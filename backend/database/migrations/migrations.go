@@ -0,0 +1,8 @@
+// Package migrations เก็บไฟล์ SQL ของ migration แยกโฟลเดอร์ตาม driver ของฐานข้อมูล
+// ชื่อไฟล์เป็น <version>_<name>.up.sql และ <version>_<name>.down.sql สร้างไฟล์ใหม่ด้วย go run ./cmd/migrate new <name>
+package migrations
+
+import "embed"
+
+//go:embed */*.sql
+var FS embed.FS
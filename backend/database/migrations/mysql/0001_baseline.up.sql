@@ -0,0 +1,70 @@
+-- สคีมาเริ่มต้น ตรงกับตารางที่ GORM AutoMigrate สร้างก่อนเปลี่ยนมาใช้ migration
+-- ฐานข้อมูลที่มีตารางอยู่แล้วจะถูกบันทึกว่าผ่าน migration นี้โดยไม่รันซ้ำ
+
+CREATE TABLE `users` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `username` varchar(191),
+    `first_name` longtext NOT NULL,
+    `last_name` longtext NOT NULL,
+    `nickname` longtext NOT NULL,
+    `email` varchar(191),
+    `password_hash` longtext NOT NULL,
+    `bio` longtext,
+    `image` longtext,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_users_username` UNIQUE (`username`),
+    CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE `categories` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(191) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_categories_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `articles` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `title` varchar(255) NOT NULL,
+    `slug` varchar(191) NOT NULL,
+    `content` text NOT NULL,
+    `author_id` bigint unsigned NOT NULL,
+    `category_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_articles_title` (`title`),
+    CONSTRAINT `fk_users_articles` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_categories_articles` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+    CONSTRAINT `uni_articles_slug` UNIQUE (`slug`)
+);
+
+CREATE TABLE `tags` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(191) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_tags_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `article_tags` (
+    `article_id` bigint unsigned,
+    `tags_id` bigint unsigned,
+    PRIMARY KEY (`article_id`,`tags_id`),
+    CONSTRAINT `fk_article_tags_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+    CONSTRAINT `fk_article_tags_tags` FOREIGN KEY (`tags_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `comments` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `content` text NOT NULL,
+    `article_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_comments_article_id` (`article_id`),
+    CONSTRAINT `fk_articles_comments` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_users_comments` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
@@ -0,0 +1,4 @@
+-- เอาบันทึกการเข้าสู่ระบบและบทบาทของผู้ใช้ออก
+
+DROP TABLE IF EXISTS `login_attempts`;
+ALTER TABLE `users` DROP COLUMN `role`;
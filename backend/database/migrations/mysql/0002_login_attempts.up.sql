@@ -0,0 +1,19 @@
+-- บทบาทของผู้ใช้ และบันทึกการเข้าสู่ระบบที่ใช้ล็อกบัญชีเมื่อใส่รหัสผ่านผิดซ้ำ
+
+ALTER TABLE `users` ADD COLUMN `role` varchar(20) NOT NULL DEFAULT 'user';
+
+CREATE TABLE `login_attempts` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `identifier` varchar(255) NOT NULL,
+    `user_id` bigint unsigned,
+    `ip` varchar(64) NOT NULL,
+    `user_agent` varchar(255),
+    `success` boolean NOT NULL DEFAULT false,
+    `blocked` boolean NOT NULL DEFAULT false,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_login_attempts_identifier` (`identifier`),
+    INDEX `idx_login_attempts_user_id` (`user_id`),
+    INDEX `idx_login_attempts_ip` (`ip`),
+    INDEX `idx_login_attempts_created_at` (`created_at`)
+);
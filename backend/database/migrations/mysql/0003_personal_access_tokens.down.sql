@@ -0,0 +1,3 @@
+-- เอา token ส่วนตัวออก
+
+DROP TABLE IF EXISTS `personal_access_tokens`;
@@ -0,0 +1,17 @@
+-- token ส่วนตัวสำหรับเรียก API แทนการเข้าสู่ระบบ
+
+CREATE TABLE `personal_access_tokens` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `token_hash` char(64) NOT NULL,
+    `prefix` varchar(16) NOT NULL,
+    `scopes` text,
+    `last_used_at` datetime(3) NULL,
+    `expires_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_personal_access_tokens_user_id` (`user_id`),
+    UNIQUE INDEX `idx_personal_access_tokens_token_hash` (`token_hash`),
+    CONSTRAINT `fk_personal_access_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,13 @@
+-- กุญแจที่ใช้เซ็น JWT หมุนเวียนตามอายุ
+
+CREATE TABLE `signing_keys` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `kid` varchar(64) NOT NULL,
+    `algorithm` varchar(16) NOT NULL,
+    `private_key` text NOT NULL,
+    `created_at` datetime(3) NULL,
+    `retired_at` datetime(3) NULL,
+    `expires_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_signing_keys_kid` (`kid`)
+);
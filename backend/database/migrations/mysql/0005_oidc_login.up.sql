@@ -0,0 +1,25 @@
+-- บัญชีจากผู้ให้บริการ OIDC และ state ของการเข้าสู่ระบบที่ยังไม่จบ
+
+CREATE TABLE `user_identities` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `provider` varchar(50) NOT NULL,
+    `subject` varchar(255) NOT NULL,
+    `email` varchar(255),
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_user_identities_user_id` (`user_id`),
+    UNIQUE INDEX `idx_identity_provider_subject` (`provider`,`subject`),
+    CONSTRAINT `fk_user_identities_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE `o_id_c_login_states` (
+    `state` varchar(64),
+    `provider` varchar(50) NOT NULL,
+    `code_verifier` varchar(128) NOT NULL,
+    `nonce` varchar(64) NOT NULL,
+    `created_at` datetime(3) NULL,
+    `expires_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`state`),
+    INDEX `idx_o_id_c_login_states_expires_at` (`expires_at`)
+);
@@ -0,0 +1,18 @@
+-- session ของการเข้าสู่ระบบแต่ละครั้ง
+
+CREATE TABLE `sessions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `token_id` varchar(64) NOT NULL,
+    `user_agent` varchar(255),
+    `ip` varchar(64),
+    `created_at` datetime(3) NULL,
+    `last_seen_at` datetime(3) NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `revoked_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_sessions_user_id` (`user_id`),
+    UNIQUE INDEX `idx_sessions_token_id` (`token_id`),
+    INDEX `idx_sessions_expires_at` (`expires_at`),
+    CONSTRAINT `fk_sessions_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,5 @@
+-- เอาคอลัมน์ของการขอลบบัญชีออก
+
+DROP INDEX `idx_users_deletion_scheduled_at` ON `users`;
+ALTER TABLE `users` DROP COLUMN `deletion_scheduled_at`;
+ALTER TABLE `users` DROP COLUMN `deletion_mode`;
@@ -0,0 +1,5 @@
+-- การขอลบบัญชีที่รอครบกำหนด
+
+ALTER TABLE `users` ADD COLUMN `deletion_mode` varchar(20);
+ALTER TABLE `users` ADD COLUMN `deletion_scheduled_at` datetime(3) NULL;
+CREATE INDEX `idx_users_deletion_scheduled_at` ON `users` (`deletion_scheduled_at`);
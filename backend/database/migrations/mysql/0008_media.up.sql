@@ -0,0 +1,32 @@
+-- คลังรูปของผู้ใช้ และรูปที่แต่ละบทความอ้างถึง
+
+CREATE TABLE `media` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `filename` varchar(255),
+    `content_type` varchar(50) NOT NULL,
+    `size` bigint,
+    `width` bigint,
+    `height` bigint,
+    `url` varchar(255) NOT NULL,
+    `variants` longtext,
+    `alt_text` varchar(500),
+    `caption` text,
+    `detached_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_media_user_id` (`user_id`),
+    UNIQUE INDEX `idx_media_storage_key` (`storage_key`),
+    INDEX `idx_media_detached_at` (`detached_at`),
+    CONSTRAINT `fk_media_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE `article_media` (
+    `media_id` bigint unsigned,
+    `article_id` bigint unsigned,
+    PRIMARY KEY (`media_id`,`article_id`),
+    CONSTRAINT `fk_article_media_media` FOREIGN KEY (`media_id`) REFERENCES `media`(`id`),
+    CONSTRAINT `fk_article_media_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`)
+);
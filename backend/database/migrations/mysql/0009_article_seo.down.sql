@@ -0,0 +1,8 @@
+-- เอารูปปกและข้อมูล SEO ของบทความออก
+
+ALTER TABLE `articles` DROP COLUMN `canonical_url`;
+ALTER TABLE `articles` DROP COLUMN `seo_description`;
+ALTER TABLE `articles` DROP COLUMN `seo_title`;
+ALTER TABLE `articles` DROP COLUMN `excerpt`;
+ALTER TABLE `articles` DROP FOREIGN KEY `fk_articles_cover`;
+ALTER TABLE `articles` DROP COLUMN `cover_media_id`;
@@ -0,0 +1,8 @@
+-- รูปปก คำโปรย และข้อมูล SEO ของบทความ
+
+ALTER TABLE `articles` ADD COLUMN `cover_media_id` bigint unsigned;
+ALTER TABLE `articles` ADD CONSTRAINT `fk_articles_cover` FOREIGN KEY (`cover_media_id`) REFERENCES `media`(`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE `articles` ADD COLUMN `excerpt` varchar(500);
+ALTER TABLE `articles` ADD COLUMN `seo_title` varchar(255);
+ALTER TABLE `articles` ADD COLUMN `seo_description` varchar(500);
+ALTER TABLE `articles` ADD COLUMN `canonical_url` varchar(500);
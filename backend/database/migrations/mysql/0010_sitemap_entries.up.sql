@@ -0,0 +1,10 @@
+-- วันที่แก้ไขล่าสุดของแต่ละหน้าใน sitemap
+
+CREATE TABLE `sitemap_entries` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `kind` varchar(16) NOT NULL,
+    `identifier` varchar(255) NOT NULL,
+    `last_mod` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_sitemap_entry` (`kind`,`identifier`)
+);
@@ -0,0 +1,4 @@
+-- เลข version ของบทความและคอมเมนต์ เพิ่มขึ้นทุกครั้งที่แก้ไข ใช้กันการบันทึกทับกันของผู้แก้ไขสองคน
+
+ALTER TABLE `articles` ADD COLUMN `version` bigint unsigned NOT NULL DEFAULT 1;
+ALTER TABLE `comments` ADD COLUMN `version` bigint unsigned NOT NULL DEFAULT 1;
@@ -0,0 +1,70 @@
+-- สคีมาเริ่มต้น ตรงกับตารางที่ GORM AutoMigrate สร้างก่อนเปลี่ยนมาใช้ migration
+-- ฐานข้อมูลที่มีตารางอยู่แล้วจะถูกบันทึกว่าผ่าน migration นี้โดยไม่รันซ้ำ
+
+CREATE TABLE "users" (
+    "id" bigserial,
+    "username" text,
+    "first_name" text NOT NULL,
+    "last_name" text NOT NULL,
+    "nickname" text NOT NULL,
+    "email" text,
+    "password_hash" text NOT NULL,
+    "bio" text,
+    "image" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+
+CREATE TABLE "categories" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_categories_name" UNIQUE ("name")
+);
+
+CREATE TABLE "articles" (
+    "id" bigserial,
+    "title" varchar(255) NOT NULL,
+    "slug" text NOT NULL,
+    "content" text NOT NULL,
+    "author_id" bigint NOT NULL,
+    "category_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_articles" FOREIGN KEY ("author_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_categories_articles" FOREIGN KEY ("category_id") REFERENCES "categories"("id"),
+    CONSTRAINT "uni_articles_slug" UNIQUE ("slug")
+);
+CREATE UNIQUE INDEX "idx_articles_title" ON "articles" ("title");
+
+CREATE TABLE "tags" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_tags_name" UNIQUE ("name")
+);
+
+CREATE TABLE "article_tags" (
+    "article_id" bigint,
+    "tags_id" bigint,
+    PRIMARY KEY ("article_id","tags_id"),
+    CONSTRAINT "fk_article_tags_article" FOREIGN KEY ("article_id") REFERENCES "articles"("id"),
+    CONSTRAINT "fk_article_tags_tags" FOREIGN KEY ("tags_id") REFERENCES "tags"("id")
+);
+
+CREATE TABLE "comments" (
+    "id" bigserial,
+    "content" text NOT NULL,
+    "article_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_articles_comments" FOREIGN KEY ("article_id") REFERENCES "articles"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT "fk_users_comments" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_comments_article_id" ON "comments" ("article_id");
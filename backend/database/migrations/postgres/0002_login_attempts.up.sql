@@ -0,0 +1,19 @@
+-- บทบาทของผู้ใช้ และบันทึกการเข้าสู่ระบบที่ใช้ล็อกบัญชีเมื่อใส่รหัสผ่านผิดซ้ำ
+
+ALTER TABLE "users" ADD COLUMN "role" varchar(20) NOT NULL DEFAULT 'user';
+
+CREATE TABLE "login_attempts" (
+    "id" bigserial,
+    "identifier" varchar(255) NOT NULL,
+    "user_id" bigint,
+    "ip" varchar(64) NOT NULL,
+    "user_agent" varchar(255),
+    "success" boolean NOT NULL DEFAULT false,
+    "blocked" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_login_attempts_created_at" ON "login_attempts" ("created_at");
+CREATE INDEX "idx_login_attempts_ip" ON "login_attempts" ("ip");
+CREATE INDEX "idx_login_attempts_user_id" ON "login_attempts" ("user_id");
+CREATE INDEX "idx_login_attempts_identifier" ON "login_attempts" ("identifier");
@@ -0,0 +1,17 @@
+-- token ส่วนตัวสำหรับเรียก API แทนการเข้าสู่ระบบ
+
+CREATE TABLE "personal_access_tokens" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "token_hash" char(64) NOT NULL,
+    "prefix" varchar(16) NOT NULL,
+    "scopes" text,
+    "last_used_at" timestamptz,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_personal_access_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX "idx_personal_access_tokens_token_hash" ON "personal_access_tokens" ("token_hash");
+CREATE INDEX "idx_personal_access_tokens_user_id" ON "personal_access_tokens" ("user_id");
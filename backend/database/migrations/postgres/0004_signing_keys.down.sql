@@ -0,0 +1,3 @@
+-- เอากุญแจเซ็น JWT ออก
+
+DROP TABLE IF EXISTS "signing_keys";
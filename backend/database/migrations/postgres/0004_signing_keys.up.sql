@@ -0,0 +1,13 @@
+-- กุญแจที่ใช้เซ็น JWT หมุนเวียนตามอายุ
+
+CREATE TABLE "signing_keys" (
+    "id" bigserial,
+    "kid" varchar(64) NOT NULL,
+    "algorithm" varchar(16) NOT NULL,
+    "private_key" text NOT NULL,
+    "created_at" timestamptz,
+    "retired_at" timestamptz,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_signing_keys_kid" ON "signing_keys" ("kid");
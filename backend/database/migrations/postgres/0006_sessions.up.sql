@@ -0,0 +1,18 @@
+-- session ของการเข้าสู่ระบบแต่ละครั้ง
+
+CREATE TABLE "sessions" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_id" varchar(64) NOT NULL,
+    "user_agent" varchar(255),
+    "ip" varchar(64),
+    "created_at" timestamptz,
+    "last_seen_at" timestamptz,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_sessions_expires_at" ON "sessions" ("expires_at");
+CREATE UNIQUE INDEX "idx_sessions_token_id" ON "sessions" ("token_id");
+CREATE INDEX "idx_sessions_user_id" ON "sessions" ("user_id");
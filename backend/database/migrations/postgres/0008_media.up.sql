@@ -0,0 +1,32 @@
+-- คลังรูปของผู้ใช้ และรูปที่แต่ละบทความอ้างถึง
+
+CREATE TABLE "media" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "storage_key" varchar(255) NOT NULL,
+    "filename" varchar(255),
+    "content_type" varchar(50) NOT NULL,
+    "size" bigint,
+    "width" bigint,
+    "height" bigint,
+    "url" varchar(255) NOT NULL,
+    "variants" text,
+    "alt_text" varchar(500),
+    "caption" text,
+    "detached_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_media_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX "idx_media_detached_at" ON "media" ("detached_at");
+CREATE UNIQUE INDEX "idx_media_storage_key" ON "media" ("storage_key");
+CREATE INDEX "idx_media_user_id" ON "media" ("user_id");
+
+CREATE TABLE "article_media" (
+    "media_id" bigint,
+    "article_id" bigint,
+    PRIMARY KEY ("media_id","article_id"),
+    CONSTRAINT "fk_article_media_media" FOREIGN KEY ("media_id") REFERENCES "media"("id"),
+    CONSTRAINT "fk_article_media_article" FOREIGN KEY ("article_id") REFERENCES "articles"("id")
+);
@@ -0,0 +1,3 @@
+-- เอาข้อมูลของ sitemap ออก
+
+DROP TABLE IF EXISTS "sitemap_entries";
@@ -0,0 +1,10 @@
+-- วันที่แก้ไขล่าสุดของแต่ละหน้าใน sitemap
+
+CREATE TABLE "sitemap_entries" (
+    "id" bigserial,
+    "kind" varchar(16) NOT NULL,
+    "identifier" varchar(255) NOT NULL,
+    "last_mod" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_sitemap_entry" ON "sitemap_entries" ("kind","identifier");
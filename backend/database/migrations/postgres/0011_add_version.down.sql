@@ -0,0 +1,4 @@
+-- เอาคอลัมน์ version ของบทความและคอมเมนต์ออก
+
+ALTER TABLE "comments" DROP COLUMN "version";
+ALTER TABLE "articles" DROP COLUMN "version";
@@ -0,0 +1,65 @@
+-- สคีมาเริ่มต้น ตรงกับตารางที่ GORM AutoMigrate สร้างก่อนเปลี่ยนมาใช้ migration
+-- ฐานข้อมูลที่มีตารางอยู่แล้วจะถูกบันทึกว่าผ่าน migration นี้โดยไม่รันซ้ำ
+
+CREATE TABLE `users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `username` text,
+    `first_name` text NOT NULL,
+    `last_name` text NOT NULL,
+    `nickname` text NOT NULL,
+    `email` text,
+    `password_hash` text NOT NULL,
+    `bio` text,
+    `image` text,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `uni_users_email` UNIQUE (`email`),
+    CONSTRAINT `uni_users_username` UNIQUE (`username`)
+);
+
+CREATE TABLE `categories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    CONSTRAINT `uni_categories_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `articles` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `title` varchar(255) NOT NULL,
+    `slug` text NOT NULL,
+    `content` text NOT NULL,
+    `author_id` integer NOT NULL,
+    `category_id` integer NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_users_articles` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`),
+    CONSTRAINT `fk_categories_articles` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`),
+    CONSTRAINT `uni_articles_slug` UNIQUE (`slug`)
+);
+CREATE UNIQUE INDEX `idx_articles_title` ON `articles`(`title`);
+
+CREATE TABLE `tags` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `name` text NOT NULL,
+    CONSTRAINT `uni_tags_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `article_tags` (
+    `article_id` integer,
+    `tags_id` integer,
+    PRIMARY KEY (`article_id`,`tags_id`),
+    CONSTRAINT `fk_article_tags_article` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`),
+    CONSTRAINT `fk_article_tags_tags` FOREIGN KEY (`tags_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE `comments` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `content` text NOT NULL,
+    `article_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    CONSTRAINT `fk_articles_comments` FOREIGN KEY (`article_id`) REFERENCES `articles`(`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT `fk_users_comments` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_comments_article_id` ON `comments`(`article_id`);
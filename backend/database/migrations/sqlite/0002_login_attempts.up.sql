@@ -0,0 +1,18 @@
+-- บทบาทของผู้ใช้ และบันทึกการเข้าสู่ระบบที่ใช้ล็อกบัญชีเมื่อใส่รหัสผ่านผิดซ้ำ
+
+ALTER TABLE `users` ADD COLUMN `role` varchar(20) NOT NULL DEFAULT 'user';
+
+CREATE TABLE `login_attempts` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `identifier` varchar(255) NOT NULL,
+    `user_id` integer,
+    `ip` varchar(64) NOT NULL,
+    `user_agent` varchar(255),
+    `success` numeric NOT NULL DEFAULT false,
+    `blocked` numeric NOT NULL DEFAULT false,
+    `created_at` datetime
+);
+CREATE INDEX `idx_login_attempts_created_at` ON `login_attempts`(`created_at`);
+CREATE INDEX `idx_login_attempts_ip` ON `login_attempts`(`ip`);
+CREATE INDEX `idx_login_attempts_user_id` ON `login_attempts`(`user_id`);
+CREATE INDEX `idx_login_attempts_identifier` ON `login_attempts`(`identifier`);
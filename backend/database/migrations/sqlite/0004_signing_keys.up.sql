@@ -0,0 +1,12 @@
+-- กุญแจที่ใช้เซ็น JWT หมุนเวียนตามอายุ
+
+CREATE TABLE `signing_keys` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `kid` varchar(64) NOT NULL,
+    `algorithm` varchar(16) NOT NULL,
+    `private_key` text NOT NULL,
+    `created_at` datetime,
+    `retired_at` datetime,
+    `expires_at` datetime
+);
+CREATE UNIQUE INDEX `idx_signing_keys_kid` ON `signing_keys`(`kid`);
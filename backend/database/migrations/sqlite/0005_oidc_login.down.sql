@@ -0,0 +1,4 @@
+-- เอาข้อมูลการเข้าสู่ระบบด้วย OIDC ออก
+
+DROP TABLE IF EXISTS `o_id_c_login_states`;
+DROP TABLE IF EXISTS `user_identities`;
@@ -0,0 +1,24 @@
+-- บัญชีจากผู้ให้บริการ OIDC และ state ของการเข้าสู่ระบบที่ยังไม่จบ
+
+CREATE TABLE `user_identities` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer NOT NULL,
+    `provider` varchar(50) NOT NULL,
+    `subject` varchar(255) NOT NULL,
+    `email` varchar(255),
+    `created_at` datetime,
+    CONSTRAINT `fk_user_identities_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX `idx_identity_provider_subject` ON `user_identities`(`provider`,`subject`);
+CREATE INDEX `idx_user_identities_user_id` ON `user_identities`(`user_id`);
+
+CREATE TABLE `o_id_c_login_states` (
+    `state` varchar(64),
+    `provider` varchar(50) NOT NULL,
+    `code_verifier` varchar(128) NOT NULL,
+    `nonce` varchar(64) NOT NULL,
+    `created_at` datetime,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`state`)
+);
+CREATE INDEX `idx_o_id_c_login_states_expires_at` ON `o_id_c_login_states`(`expires_at`);
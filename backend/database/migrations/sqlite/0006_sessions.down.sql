@@ -0,0 +1,3 @@
+-- เอา session ออก
+
+DROP TABLE IF EXISTS `sessions`;
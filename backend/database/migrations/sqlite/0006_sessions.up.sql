@@ -0,0 +1,17 @@
+-- session ของการเข้าสู่ระบบแต่ละครั้ง
+
+CREATE TABLE `sessions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer NOT NULL,
+    `token_id` varchar(64) NOT NULL,
+    `user_agent` varchar(255),
+    `ip` varchar(64),
+    `created_at` datetime,
+    `last_seen_at` datetime,
+    `expires_at` datetime NOT NULL,
+    `revoked_at` datetime,
+    CONSTRAINT `fk_sessions_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX `idx_sessions_expires_at` ON `sessions`(`expires_at`);
+CREATE UNIQUE INDEX `idx_sessions_token_id` ON `sessions`(`token_id`);
+CREATE INDEX `idx_sessions_user_id` ON `sessions`(`user_id`);
@@ -0,0 +1,4 @@
+-- เอาคลังรูปออก ตารางที่อ้างถึงตารางอื่นถูกลบก่อน
+
+DROP TABLE IF EXISTS `article_media`;
+DROP TABLE IF EXISTS `media`;
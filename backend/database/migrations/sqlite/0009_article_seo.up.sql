@@ -0,0 +1,7 @@
+-- รูปปก คำโปรย และข้อมูล SEO ของบทความ
+
+ALTER TABLE `articles` ADD COLUMN `cover_media_id` integer CONSTRAINT `fk_articles_cover` REFERENCES `media`(`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE `articles` ADD COLUMN `excerpt` varchar(500);
+ALTER TABLE `articles` ADD COLUMN `seo_title` varchar(255);
+ALTER TABLE `articles` ADD COLUMN `seo_description` varchar(500);
+ALTER TABLE `articles` ADD COLUMN `canonical_url` varchar(500);
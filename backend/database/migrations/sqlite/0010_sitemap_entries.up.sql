@@ -0,0 +1,9 @@
+-- วันที่แก้ไขล่าสุดของแต่ละหน้าใน sitemap
+
+CREATE TABLE `sitemap_entries` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `kind` varchar(16) NOT NULL,
+    `identifier` varchar(255) NOT NULL,
+    `last_mod` datetime NOT NULL
+);
+CREATE UNIQUE INDEX `idx_sitemap_entry` ON `sitemap_entries`(`kind`,`identifier`);
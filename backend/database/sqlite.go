@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// sqlitePool ให้ทุก transaction ของ GORM เริ่มด้วย BEGIN IMMEDIATE เพื่อจองสิทธิ์เขียนตั้งแต่ต้น
+// transaction ที่อ่านแล้วค่อยเขียนพร้อมกันจึงรอกันตาม busy_timeout แทนการได้ database is locked ทันที
+// ตั้งผ่าน DSN ด้วย _txlock ไม่ได้ เพราะ driver ข้าม _txlock เมื่อมี _time_format อยู่ใน DSN ด้วย
+type sqlitePool struct {
+	*sql.DB
+}
+
+func (p sqlitePool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	conn, err := p.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	begin := "BEGIN IMMEDIATE"
+	if opts != nil && opts.ReadOnly {
+		begin = "BEGIN"
+	}
+	if _, err := conn.ExecContext(ctx, begin); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sqliteTx{conn: conn}, nil
+}
+
+// GetDBConn ให้ gorm.DB.DB() คืน *sql.DB ตัวจริงได้
+func (p sqlitePool) GetDBConn() (*sql.DB, error) {
+	return p.DB, nil
+}
+
+// sqliteTx คือ transaction บน connection เดียวที่ถูกกันไว้จนกว่าจะ commit หรือ rollback
+// ไม่ embed *sql.Conn ตรงๆ เพราะ GORM ดูว่าอยู่ใน transaction แล้วจากการที่ ConnPool ไม่มี BeginTx
+type sqliteTx struct {
+	conn *sql.Conn
+}
+
+func (t *sqliteTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.conn.PrepareContext(ctx, query)
+}
+
+func (t *sqliteTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.conn.ExecContext(ctx, query, args...)
+}
+
+func (t *sqliteTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.conn.QueryContext(ctx, query, args...)
+}
+
+func (t *sqliteTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.conn.QueryRowContext(ctx, query, args...)
+}
+
+func (t *sqliteTx) Commit() error {
+	if _, err := t.ExecContext(context.Background(), "COMMIT"); err != nil {
+		// ไม่ให้ connection ที่ยังค้างอยู่ใน transaction กลับเข้า pool
+		_, rollbackErr := t.ExecContext(context.Background(), "ROLLBACK")
+		return errors.Join(err, rollbackErr, t.conn.Close())
+	}
+	return t.conn.Close()
+}
+
+func (t *sqliteTx) Rollback() error {
+	_, err := t.ExecContext(context.Background(), "ROLLBACK")
+	return errors.Join(err, t.conn.Close())
+}
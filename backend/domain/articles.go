@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository"
+	"backend/validation"
+	"context"
+	"errors"
+	"strings"
+)
+
+// บทความอื่นที่บันทึกพร้อมกันอาจได้ชื่อเรื่องหรือ slug เดียวกันไปหลังตรวจแล้ว unique index จึงเป็นตัวตัดสินสุดท้าย
+var errTitleTaken = &ValidationError{Fields: map[string]string{"title": "title or slug already exists"}}
+
+func duplicateTitle(err error) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return errTitleTaken
+	}
+	return err
+}
+
+// ArticleService คือการอ่าน สร้าง แก้ไข และลบบทความ
+type ArticleService struct {
+	Articles   repository.ArticleRepository
+	Tags       repository.TagRepository
+	Categories repository.CategoryRepository
+	Tx         repository.Transactor
+}
+
+// All คืนทุกบทความพร้อมความสัมพันธ์
+func (s *ArticleService) All(ctx context.Context) ([]models.Article, error) {
+	return s.Articles.All(ctx)
+}
+
+// List คืนบทความตาม filter
+func (s *ArticleService) List(ctx context.Context, filter repository.ArticleFilter) ([]models.Article, error) {
+	return s.Articles.List(ctx, filter)
+}
+
+// ListByAuthor คืนบทความของผู้เขียน
+func (s *ArticleService) ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error) {
+	return s.Articles.ListByAuthor(ctx, authorID)
+}
+
+// Get คืนบทความพร้อมข้อมูลสำหรับหน้าบทความ
+func (s *ArticleService) Get(ctx context.Context, slug string) (*models.Article, error) {
+	article, err := s.Articles.FindDetailBySlug(ctx, slug)
+	return article, notFound(err)
+}
+
+// Create สร้างบทความของ authorID หมวดหมู่และแท็กที่ยังไม่มีจะถูกสร้างให้
+func (s *ArticleService) Create(ctx context.Context, authorID uint, input validation.CreateArticleInput) (*models.Article, error) {
+	if errs := validation.ValidateStructArticle(input); errs != nil {
+		return nil, &ValidationError{Fields: errs}
+	}
+	taken, err := s.Articles.TitleOrSlugTaken(ctx, input.Title, input.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errTitleTaken
+	}
+	article := models.Article{
+		Title:    input.Title,
+		Slug:     input.Slug,
+		Content:  input.Content,
+		AuthorID: authorID,
+
+		Excerpt:        strings.TrimSpace(input.Excerpt),
+		SEOTitle:       strings.TrimSpace(input.SEOTitle),
+		SEODescription: strings.TrimSpace(input.SEODescription),
+		CanonicalURL:   strings.TrimSpace(input.CanonicalURL),
+	}
+	if input.CoverMediaID != nil && *input.CoverMediaID != 0 {
+		cover, err := s.Articles.FindCover(ctx, authorID, *input.CoverMediaID)
+		if err != nil {
+			return nil, invalid("cover_media_id", err.Error())
+		}
+		article.CoverMediaID, article.Cover = &cover.ID, cover
+	}
+	// หมวดหมู่และแท็กที่สร้างใหม่ถูก rollback ไปด้วยถ้าสร้างบทความไม่สำเร็จ
+	err = s.Tx.Transaction(ctx, func(ctx context.Context) error {
+		category, err := s.Categories.FindOrCreate(ctx, input.CategoryName)
+		if err != nil {
+			return err
+		}
+		if article.Tags, err = s.Tags.FindOrCreate(ctx, input.TagNames); err != nil {
+			return err
+		}
+		article.CategoryID = category.ID
+		return s.Articles.Create(ctx, &article)
+	})
+	if err != nil {
+		return nil, duplicateTitle(err)
+	}
+	return &article, nil
+}
+
+// Update แก้ไขบทความที่ userID เป็นเจ้าของ ฟิลด์ที่เป็น nil ใน input จะไม่ถูกแก้
+// แท็กถูกแทนทั้งชุดเมื่อส่ง tag_ids มา หรือเมื่อ new_tags มีชื่อที่ยังไม่เคยมี
+// input.Version ต้องตรงกับ version ปัจจุบัน ไม่อย่างนั้นคืน ConflictError พร้อมบทความล่าสุด
+func (s *ArticleService) Update(ctx context.Context, userID uint, slug string, input validation.UpdateArticleInput) (*models.Article, error) {
+	article, err := s.owned(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+	// ข้อมูลที่ผิดได้ 400 เสมอ แม้จะส่ง version เก่ามาด้วย แก้ข้อมูลแล้วค่อยเจอ conflict
+	if errs := validation.ValidateStructArticle(input); errs != nil {
+		return nil, &ValidationError{Fields: errs}
+	}
+	if ok, err := versionMatches(input.Version, article.Version); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, s.conflict(ctx, slug)
+	}
+	if input.Title != nil {
+		article.Title = *input.Title
+	}
+	if input.Content != nil {
+		article.Content = *input.Content
+	}
+	if input.CategoryID != nil {
+		article.CategoryID = *input.CategoryID
+	}
+	if input.Excerpt != nil {
+		article.Excerpt = strings.TrimSpace(*input.Excerpt)
+	}
+	if input.SEOTitle != nil {
+		article.SEOTitle = strings.TrimSpace(*input.SEOTitle)
+	}
+	if input.SEODescription != nil {
+		article.SEODescription = strings.TrimSpace(*input.SEODescription)
+	}
+	if input.CanonicalURL != nil {
+		article.CanonicalURL = strings.TrimSpace(*input.CanonicalURL)
+	}
+	if input.CoverMediaID != nil {
+		if *input.CoverMediaID == 0 {
+			article.CoverMediaID, article.Cover = nil, nil
+		} else {
+			cover, err := s.Articles.FindCover(ctx, userID, *input.CoverMediaID)
+			if err != nil {
+				return nil, invalid("cover_media_id", err.Error())
+			}
+			article.CoverMediaID, article.Cover = &cover.ID, cover
+		}
+	}
+
+	err = s.Tx.Transaction(ctx, func(ctx context.Context) error {
+		// สร้างเฉพาะ tag ใหม่ที่ยังไม่มี ชื่อที่มีอยู่แล้วถูกข้าม
+		var newNames []string
+		for _, name := range input.NewTags {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, err := s.Tags.FindByName(ctx, name); errors.Is(err, repository.ErrNotFound) {
+				newNames = append(newNames, name)
+			} else if err != nil {
+				return err
+			}
+		}
+		createdTags, err := s.Tags.FindOrCreate(ctx, newNames)
+		if err != nil {
+			return err
+		}
+		// รวมค่า tag ใหม่กับ tag เดิม
+		replaceTags := input.TagIDs != nil || len(createdTags) > 0
+		if replaceTags {
+			allTagIDs := append([]uint{}, input.TagIDs...)
+			for _, tag := range createdTags {
+				allTagIDs = append(allTagIDs, tag.ID)
+			}
+			if article.Tags, err = s.Tags.FindByIDs(ctx, allTagIDs); err != nil {
+				return err
+			}
+		}
+		return s.Articles.Update(ctx, article, replaceTags)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, s.conflict(ctx, slug)
+	}
+	if err != nil {
+		return nil, duplicateTitle(notFound(err))
+	}
+	return article, nil
+}
+
+// คืน ConflictError พร้อมบทความล่าสุดที่อ่านใหม่จากฐานข้อมูล
+func (s *ArticleService) conflict(ctx context.Context, slug string) error {
+	current, err := s.Articles.FindDetailBySlug(ctx, slug)
+	if err != nil {
+		return notFound(err)
+	}
+	return &ConflictError{Version: current.Version, Current: current}
+}
+
+// Delete ลบบทความที่ userID เป็นเจ้าของ
+func (s *ArticleService) Delete(ctx context.Context, userID uint, slug string) error {
+	article, err := s.owned(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+	return s.Articles.Delete(ctx, article)
+}
+
+// คืนบทความตาม slug ถ้า userID เป็นผู้เขียน
+func (s *ArticleService) owned(ctx context.Context, userID uint, slug string) (*models.Article, error) {
+	article, err := s.Articles.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if article.AuthorID != userID {
+		return nil, ErrForbidden
+	}
+	return article, nil
+}
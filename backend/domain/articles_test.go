@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository"
+	"backend/repository/memory"
+	"backend/validation"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newArticleService(store *memory.Store) *ArticleService {
+	return &ArticleService{Articles: store.Articles(), Tags: store.Tags(), Categories: store.Categories(), Tx: store}
+}
+
+func addUser(t *testing.T, store *memory.Store, username string) *models.User {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com"}
+	if err := store.Users().Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+func createArticle(t *testing.T, s *ArticleService, authorID uint, title string) *models.Article {
+	t.Helper()
+	article, err := s.Create(context.Background(), authorID, validation.CreateArticleInput{
+		Title:        title,
+		Slug:         title,
+		Content:      "content of " + title,
+		CategoryName: "Go",
+		TagNames:     []string{"testing", " "},
+	})
+	if err != nil {
+		t.Fatalf("create %s: %v", title, err)
+	}
+	return article
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestArticleCreate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	author := addUser(t, store, "alice")
+
+	article := createArticle(t, s, author.ID, "hello")
+	if article.Version != 1 || article.CategoryID == 0 {
+		t.Fatalf("created %+v", article)
+	}
+	// ชื่อแท็กที่ว่างถูกข้าม
+	if len(article.Tags) != 1 || article.Tags[0].Name != "testing" {
+		t.Fatalf("tags %+v", article.Tags)
+	}
+
+	var verr *ValidationError
+	if _, err := s.Create(ctx, author.ID, validation.CreateArticleInput{Title: "x"}); !errors.As(err, &verr) || verr.Fields["content"] == "" {
+		t.Fatalf("missing fields: %v", err)
+	}
+	_, err := s.Create(ctx, author.ID, validation.CreateArticleInput{Title: "hello", Slug: "other", Content: "c", CategoryName: "Go"})
+	if !errors.As(err, &verr) || verr.Fields["title"] == "" {
+		t.Fatalf("duplicate title: %v", err)
+	}
+
+	got, err := s.Get(ctx, "hello")
+	if err != nil || got.Author.Username != "alice" || got.Category.Name != "Go" {
+		t.Fatalf("get: %+v, %v", got, err)
+	}
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get missing: %v", err)
+	}
+}
+
+func TestArticleCover(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	alice := addUser(t, store, "alice")
+	bob := addUser(t, store, "bob")
+	cover := store.AddMedia(models.Media{UserID: alice.ID})
+	other := store.AddMedia(models.Media{UserID: bob.ID})
+
+	input := validation.CreateArticleInput{Title: "a", Slug: "a", Content: "c", CategoryName: "Go", CoverMediaID: &other.ID}
+	var verr *ValidationError
+	if _, err := s.Create(ctx, alice.ID, input); !errors.As(err, &verr) || verr.Fields["cover_media_id"] == "" {
+		t.Fatalf("cover of another user: %v", err)
+	}
+	input.CoverMediaID = &cover.ID
+	article, err := s.Create(ctx, alice.ID, input)
+	if err != nil || article.Cover == nil || article.Cover.ID != cover.ID {
+		t.Fatalf("create with cover: %+v, %v", article, err)
+	}
+
+	// 0 คือเอารูปปกออก
+	article, err = s.Update(ctx, alice.ID, "a", validation.UpdateArticleInput{CoverMediaID: ptr(uint(0)), Version: ptr(article.Version)})
+	if err != nil || article.CoverMediaID != nil {
+		t.Fatalf("remove cover: %+v, %v", article, err)
+	}
+}
+
+func TestArticleUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	author := addUser(t, store, "alice")
+	article := createArticle(t, s, author.ID, "hello")
+
+	if _, err := s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{Title: ptr("new")}); !errors.Is(err, ErrVersionRequired) {
+		t.Fatalf("without version: %v", err)
+	}
+
+	updated, err := s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{Title: ptr("first edit"), Version: ptr(article.Version)})
+	if err != nil || updated.Version != 2 || updated.Title != "first edit" {
+		t.Fatalf("update: %+v, %v", updated, err)
+	}
+	// แท็กเดิมยังอยู่เมื่อไม่ได้ส่งแท็กมา
+	if len(updated.Tags) != 1 {
+		t.Fatalf("tags after update: %+v", updated.Tags)
+	}
+
+	// แก้จาก version เก่าได้ ConflictError พร้อมบทความล่าสุด
+	_, err = s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{Title: ptr("stale edit"), Version: ptr(article.Version)})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 2 || conflict.Current.(*models.Article).Title != "first edit" {
+		t.Fatalf("stale update: %v", err)
+	}
+	// ข้อมูลผิดพร้อม version เก่าเป็น ValidationError ไม่ใช่ conflict
+	var verr *ValidationError
+	_, err = s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{SEOTitle: ptr(strings.Repeat("x", 256)), Version: ptr(article.Version)})
+	if !errors.As(err, &verr) || verr.Fields["seotitle"] == "" {
+		t.Fatalf("invalid stale update: %v", err)
+	}
+
+	updated, err = s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{Title: ptr("forced"), Version: ptr(AnyVersion)})
+	if err != nil || updated.Version != 3 || updated.Title != "forced" {
+		t.Fatalf("update any version: %+v, %v", updated, err)
+	}
+
+	if _, err := s.Update(ctx, author.ID+1, "hello", validation.UpdateArticleInput{Version: ptr(AnyVersion)}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("update by another user: %v", err)
+	}
+}
+
+func TestArticleUpdateTags(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	author := addUser(t, store, "alice")
+	article := createArticle(t, s, author.ID, "hello")
+
+	// ชื่อที่มีอยู่แล้วใน new_tags ไม่ทำให้แท็กถูกแทน
+	updated, err := s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{NewTags: []string{"TESTING"}, Version: ptr(article.Version)})
+	if err != nil || len(updated.Tags) != 1 {
+		t.Fatalf("existing new tag: %+v, %v", updated, err)
+	}
+	updated, err = s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{NewTags: []string{"memory"}, Version: ptr(updated.Version)})
+	if err != nil || len(updated.Tags) != 1 || updated.Tags[0].Name != "memory" {
+		t.Fatalf("new tag: %+v, %v", updated, err)
+	}
+	updated, err = s.Update(ctx, author.ID, "hello", validation.UpdateArticleInput{TagIDs: []uint{}, Version: ptr(updated.Version)})
+	if err != nil || len(updated.Tags) != 0 {
+		t.Fatalf("clear tags: %+v, %v", updated, err)
+	}
+}
+
+// failingArticles บันทึกบทความไม่สำเร็จเสมอ ใช้ทดสอบการ rollback
+type failingArticles struct {
+	*memory.Articles
+}
+
+var errSave = errors.New("save failed")
+
+func (failingArticles) Create(context.Context, *models.Article) error { return errSave }
+
+func TestArticleCreateRollback(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	s.Articles = failingArticles{store.Articles()}
+
+	_, err := s.Create(ctx, 1, validation.CreateArticleInput{Title: "a", Slug: "a", Content: "c", CategoryName: "New", TagNames: []string{"new"}})
+	if !errors.Is(err, errSave) {
+		t.Fatalf("create: %v", err)
+	}
+	// หมวดหมู่และแท็กที่สร้างใน transaction ต้องหายไปด้วย
+	if categories, _ := store.Categories().All(ctx); len(categories) != 0 {
+		t.Fatalf("categories after rollback: %+v", categories)
+	}
+	if _, err := store.Tags().FindByName(ctx, "new"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("tag after rollback: %v", err)
+	}
+}
+
+func TestArticleDelete(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	comments := &CommentService{Articles: store.Articles(), Comments: store.Comments()}
+	author := addUser(t, store, "alice")
+	createArticle(t, s, author.ID, "hello")
+	if _, err := comments.Create(ctx, author.ID, "hello", "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(ctx, author.ID+1, "hello"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("delete by another user: %v", err)
+	}
+	if err := s.Delete(ctx, author.ID, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "hello"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get deleted: %v", err)
+	}
+	if err := s.Delete(ctx, author.ID, "hello"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete twice: %v", err)
+	}
+}
+
+func TestArticleList(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := newArticleService(store)
+	alice := addUser(t, store, "alice")
+	bob := addUser(t, store, "bob")
+	createArticle(t, s, alice.ID, "hello")
+	createArticle(t, s, bob.ID, "world")
+
+	for _, tc := range []struct {
+		filter repository.ArticleFilter
+		want   int
+	}{
+		{repository.ArticleFilter{}, 2},
+		{repository.ArticleFilter{Search: "content world"}, 1},
+		{repository.ArticleFilter{Tag: "TESTING"}, 2},
+		{repository.ArticleFilter{Tag: "other"}, 0},
+		{repository.ArticleFilter{Author: "bob"}, 1},
+	} {
+		articles, err := s.List(ctx, tc.filter)
+		if err != nil || len(articles) != tc.want {
+			t.Errorf("list %+v: %d articles, %v, want %d", tc.filter, len(articles), err, tc.want)
+		}
+	}
+}
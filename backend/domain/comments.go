@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"errors"
+	"strings"
+)
+
+// CommentService คือการอ่านและเขียนคอมเมนต์ใต้บทความ
+type CommentService struct {
+	Articles repository.ArticleRepository
+	Comments repository.CommentRepository
+}
+
+// List คืนคอมเมนต์ของบทความ slug จากใหม่ไปเก่า
+func (s *CommentService) List(ctx context.Context, slug string) ([]models.Comment, error) {
+	article, err := s.Articles.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return s.Comments.ListByArticle(ctx, article.ID)
+}
+
+// Create เพิ่มคอมเมนต์ของ userID ใต้บทความ slug
+func (s *CommentService) Create(ctx context.Context, userID uint, slug, content string) (*models.Comment, error) {
+	article, err := s.Articles.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyContent
+	}
+	comment := models.Comment{
+		Content:   content,
+		ArticleID: article.ID,
+		UserID:    userID,
+	}
+	if err := s.Comments.Create(ctx, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Update แก้เนื้อหาคอมเมนต์ที่ userID เป็นเจ้าของ คืนคอมเมนต์พร้อมผู้เขียน
+// version ต้องตรงกับ version ปัจจุบัน ไม่อย่างนั้นคืน ConflictError พร้อมคอมเมนต์ล่าสุด
+func (s *CommentService) Update(ctx context.Context, userID, commentID uint, content string, version *uint) (*models.Comment, error) {
+	comment, err := s.owned(ctx, userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrEmptyContent
+	}
+	if ok, err := versionMatches(version, comment.Version); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, &ConflictError{Version: comment.Version, Current: comment}
+	}
+	comment.Content = content
+	err = s.Comments.Update(ctx, comment)
+	if errors.Is(err, repository.ErrConflict) {
+		current, err := s.Comments.FindByID(ctx, commentID)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return nil, &ConflictError{Version: current.Version, Current: current}
+	}
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return comment, nil
+}
+
+// Delete ลบคอมเมนต์ที่ userID เป็นเจ้าของ
+func (s *CommentService) Delete(ctx context.Context, userID, commentID uint) error {
+	comment, err := s.owned(ctx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	return s.Comments.Delete(ctx, comment)
+}
+
+func (s *CommentService) owned(ctx context.Context, userID, commentID uint) (*models.Comment, error) {
+	comment, err := s.Comments.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if comment.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return comment, nil
+}
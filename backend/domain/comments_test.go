@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository/memory"
+	"backend/validation"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	articles := newArticleService(store)
+	s := &CommentService{Articles: store.Articles(), Comments: store.Comments()}
+	alice := addUser(t, store, "alice")
+	bob := addUser(t, store, "bob")
+	createArticle(t, articles, alice.ID, "hello")
+
+	if _, err := s.Create(ctx, bob.ID, "missing", "hi"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("comment on missing article: %v", err)
+	}
+	if _, err := s.Create(ctx, bob.ID, "hello", "  "); !errors.Is(err, ErrEmptyContent) {
+		t.Fatalf("empty comment: %v", err)
+	}
+	comment, err := s.Create(ctx, bob.ID, "hello", "hi")
+	if err != nil || comment.Version != 1 {
+		t.Fatalf("create: %+v, %v", comment, err)
+	}
+
+	if _, err := s.Update(ctx, alice.ID, comment.ID, "edit", ptr(comment.Version)); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("update by another user: %v", err)
+	}
+	if _, err := s.Update(ctx, bob.ID, comment.ID, " ", ptr(comment.Version+1)); !errors.Is(err, ErrEmptyContent) {
+		t.Fatalf("empty edit with a stale version: %v", err)
+	}
+	if _, err := s.Update(ctx, bob.ID, comment.ID, "edit", nil); !errors.Is(err, ErrVersionRequired) {
+		t.Fatalf("update without version: %v", err)
+	}
+	updated, err := s.Update(ctx, bob.ID, comment.ID, "edit", ptr(comment.Version))
+	if err != nil || updated.Version != 2 || updated.User.Username != "bob" {
+		t.Fatalf("update: %+v, %v", updated, err)
+	}
+	_, err = s.Update(ctx, bob.ID, comment.ID, "stale", ptr(comment.Version))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Version != 2 || conflict.Current.(*models.Comment).Content != "edit" {
+		t.Fatalf("stale update: %v", err)
+	}
+	if updated, err = s.Update(ctx, bob.ID, comment.ID, "forced", ptr(AnyVersion)); err != nil || updated.Version != 3 {
+		t.Fatalf("update any version: %+v, %v", updated, err)
+	}
+
+	list, err := s.List(ctx, "hello")
+	if err != nil || len(list) != 1 || list[0].Content != "forced" {
+		t.Fatalf("list: %+v, %v", list, err)
+	}
+	if err := s.Delete(ctx, alice.ID, comment.ID); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("delete by another user: %v", err)
+	}
+	if err := s.Delete(ctx, bob.ID, comment.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, bob.ID, comment.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete twice: %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	s := &UserService{Users: store.Users()}
+	input := validation.RegisterInput{
+		Username:        "alice",
+		Email:           "alice@example.com",
+		Password:        "secret1",
+		ConfirmPassword: "secret1",
+		FirstName:       "Alice",
+		LastName:        "Liddell",
+		Nickname:        "al",
+	}
+
+	user, err := s.Register(ctx, input)
+	if err != nil || user.PasswordHash != "" || user.Role != models.RoleUser {
+		t.Fatalf("register: %+v, %v", user, err)
+	}
+	saved, err := store.Users().FindByID(ctx, user.ID)
+	if err != nil || saved.PasswordHash == "" || saved.PasswordHash == input.Password {
+		t.Fatalf("saved password hash: %+v, %v", saved, err)
+	}
+
+	var verr *ValidationError
+	input.Username = "alice2"
+	input.Email = "ALICE@example.com"
+	if _, err := s.Register(ctx, input); !errors.As(err, &verr) || verr.Fields["email"] == "" {
+		t.Fatalf("duplicate email: %v", err)
+	}
+	input.Email = "other@example.com"
+	input.ConfirmPassword = "secret2"
+	if _, err := s.Register(ctx, input); !errors.As(err, &verr) || verr.Fields["confirm_password"] == "" {
+		t.Fatalf("password mismatch: %v", err)
+	}
+
+	profile, err := s.Profile(ctx, user.ID)
+	if err != nil || profile.PasswordHash != "" || profile.Username != "alice" {
+		t.Fatalf("profile: %+v, %v", profile, err)
+	}
+	if _, err := s.Profile(ctx, user.ID+100); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("profile of missing user: %v", err)
+	}
+}
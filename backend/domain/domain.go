@@ -0,0 +1,72 @@
+// Package domain คือกฎของบล็อกที่ไม่ผูกกับ HTTP
+// service รับค่าธรรมดา คืนข้อมูลหรือ error ที่มีชนิดชัดเจน และอ่านเขียนข้อมูลผ่าน repository เท่านั้น
+// จึงทดสอบได้ด้วย repository/memory โดยไม่ต้องมี Fiber หรือฐานข้อมูล
+package domain
+
+import (
+	"backend/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotFound คือข้อมูลที่อ้างถึงไม่มีอยู่
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden คือผู้ใช้ไม่ใช่เจ้าของข้อมูลที่จะแก้ไขหรือลบ
+	ErrForbidden = errors.New("forbidden")
+	// ErrEmptyContent คือเนื้อหาคอมเมนต์ว่าง
+	ErrEmptyContent = errors.New("content is required")
+	// ErrVersionRequired คือการแก้ไขที่ไม่ได้บอก version ที่อ่านไป
+	ErrVersionRequired = errors.New("version is required")
+)
+
+// AnyVersion คือ version ที่แก้ทับได้ไม่ว่าข้อมูลจะอยู่ใน version ไหน ใช้กับ If-Match: *
+// ถ้ามีคนบันทึกระหว่างที่อ่านกับเขียนก็ยังได้ ConflictError เหมือน version อื่น
+const AnyVersion = ^uint(0)
+
+// ตรวจ version ที่ผู้แก้ไขส่งมากับ version ปัจจุบัน ok เป็น false คือต้องตอบ ConflictError
+func versionMatches(version *uint, current uint) (ok bool, err error) {
+	if version == nil {
+		return false, ErrVersionRequired
+	}
+	return *version == AnyVersion || *version == current, nil
+}
+
+// ConflictError คือข้อมูลถูกแก้ไปแล้วหลังจากที่ผู้แก้ไขอ่านไป
+// Current คือข้อมูลล่าสุดใน version Version ให้ผู้แก้ไขรวมการแก้ของตัวเองแล้วส่งใหม่
+type ConflictError struct {
+	Version uint
+	Current interface{}
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("version conflict: current version is %d", e.Version)
+}
+
+// ValidationError คือข้อมูลที่ส่งมาไม่ผ่านการตรวจ Fields เก็บข้อความแยกตามชื่อฟิลด์
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field, message := range e.Fields {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return "invalid input: " + strings.Join(fields, ", ")
+}
+
+func invalid(field, message string) error {
+	return &ValidationError{Fields: map[string]string{field: message}}
+}
+
+// แปลง ErrNotFound ของ repository เป็นของ domain ส่วน error อื่นส่งต่อตามเดิม
+func notFound(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+)
+
+// TagService คืนรายการแท็ก
+type TagService struct {
+	Tags repository.TagRepository
+}
+
+// List คืนทุกแท็ก
+func (s *TagService) List(ctx context.Context) ([]models.Tags, error) {
+	return s.Tags.All(ctx)
+}
+
+// CategoryService คืนรายการหมวดหมู่
+type CategoryService struct {
+	Categories repository.CategoryRepository
+}
+
+// List คืนทุกหมวดหมู่
+func (s *CategoryService) List(ctx context.Context) ([]models.Category, error) {
+	return s.Categories.All(ctx)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/passwords"
+	"backend/repository"
+	"backend/validation"
+	"context"
+)
+
+// UserService คือการสมัครสมาชิกและอ่านข้อมูลบัญชี
+// ผู้ใช้ที่คืนจาก service นี้ไม่มี hash ของรหัสผ่าน
+type UserService struct {
+	Users repository.UserRepository
+}
+
+// Register สร้างบัญชีใหม่ อีเมลและ username ต้องไม่ซ้ำกับบัญชีที่มีอยู่
+func (s *UserService) Register(ctx context.Context, input validation.RegisterInput) (*models.User, error) {
+	if errs := validation.ValidateStructRegister(input); errs != nil {
+		return nil, &ValidationError{Fields: errs}
+	}
+	if input.Password != input.ConfirmPassword {
+		return nil, invalid("confirm_password", "Password does not match")
+	}
+	// อีเมลและ username ของบัญชี "deleted user" ถือว่ามีคนใช้แล้วเสมอ
+	if taken, err := s.Users.EmailTaken(ctx, input.Email); err != nil {
+		return nil, err
+	} else if taken || models.ReservedEmail(input.Email) {
+		return nil, invalid("email", "Email already exists")
+	}
+	if taken, err := s.Users.UsernameTaken(ctx, input.Username); err != nil {
+		return nil, err
+	} else if taken || models.ReservedUsername(input.Username) {
+		return nil, invalid("username", "Username already exists")
+	}
+	user := models.User{
+		Username:     input.Username,
+		Email:        input.Email,
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		Nickname:     input.Nickname,
+		PasswordHash: passwords.Hash(input.Password),
+	}
+	if err := s.Users.Create(ctx, &user); err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+	return &user, nil
+}
+
+// Profile คืนข้อมูลบัญชีของ id
+func (s *UserService) Profile(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.Users.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	user.PasswordHash = ""
+	return user, nil
+}
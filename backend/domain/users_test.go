@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"backend/models"
+	"backend/repository/memory"
+	"backend/validation"
+	"context"
+	"errors"
+	"testing"
+)
+
+// อีเมลและ username ของบัญชี "deleted user" สมัครใช้ไม่ได้แม้บัญชีนั้นยังไม่ถูกสร้าง
+func TestRegisterReservedNames(t *testing.T) {
+	ctx := context.Background()
+	s := &UserService{Users: memory.New().Users()}
+	input := validation.RegisterInput{
+		Username:        "alice",
+		Email:           "alice@example.com",
+		Password:        "secret1",
+		ConfirmPassword: "secret1",
+		FirstName:       "Alice",
+		LastName:        "Liddell",
+		Nickname:        "alice",
+	}
+
+	reserved := input
+	reserved.Username = "Deleted-User"
+	var invalid *ValidationError
+	if _, err := s.Register(ctx, reserved); !errors.As(err, &invalid) || invalid.Fields["username"] == "" {
+		t.Fatalf("reserved username: %v", err)
+	}
+	reserved = input
+	reserved.Email = models.DeletedUserEmail
+	if _, err := s.Register(ctx, reserved); !errors.As(err, &invalid) || invalid.Fields["email"] == "" {
+		t.Fatalf("reserved email: %v", err)
+	}
+	if user, err := s.Register(ctx, input); err != nil || user.PasswordHash != "" {
+		t.Fatalf("register: %+v, %v", user, err)
+	}
+}
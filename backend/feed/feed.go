@@ -0,0 +1,58 @@
+// Package feed สร้าง RSS 2.0, Atom 1.0 และ JSON Feed 1.1 จากข้อมูลชุดเดียวกัน
+package feed
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+const generator = "BoBlog"
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// Feed คือข้อมูลของ feed หนึ่งชุด Link คือหน้าเว็บ ส่วน FeedURL คือ URL ของ feed เอง
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	Language    string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item คือบทความหนึ่งรายการ Content เป็น HTML และว่างได้ถ้าส่งแค่ Summary
+type Item struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Content    string
+	Author     string
+	Categories []string
+	Image      string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// Encode แปลง feed เป็นรูปแบบที่ต้องการ คืนข้อมูลพร้อม Content-Type
+func Encode(f *Feed, format string) ([]byte, string, error) {
+	switch format {
+	case FormatRSS:
+		data, err := encodeRSS(f)
+		return data, "application/rss+xml; charset=utf-8", err
+	case FormatAtom:
+		data, err := encodeAtom(f)
+		return data, "application/atom+xml; charset=utf-8", err
+	case FormatJSON:
+		data, err := encodeJSON(f)
+		return data, "application/feed+json; charset=utf-8", err
+	}
+	return nil, "", ErrUnknownFormat
+}
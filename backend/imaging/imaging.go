@@ -0,0 +1,165 @@
+// Package imaging ตรวจชนิดไฟล์รูปจากเนื้อไฟล์จริง ป้องกันรูปที่ขยายแล้วใหญ่ผิดปกติ
+// และสร้างรูปย่อใหม่ทั้งหมด (การ encode ใหม่ทำให้ EXIF/GPS ของไฟล์ต้นฉบับหายไปด้วย)
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// ขนาดไฟล์สูงสุดที่รับ (nginx จำกัด body ไว้ที่ 10M)
+	MaxUploadBytes = 10 << 20
+	// จำนวน pixel สูงสุดหลัง decode กันรูปที่ไฟล์เล็กแต่ขยายแล้วกินหน่วยความจำมหาศาล
+	MaxPixels    = 40_000_000
+	MaxDimension = 12_000
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooLarge          = errors.New("image is too large")
+)
+
+// content type ที่รับได้ จับคู่กับชื่อ format ของ image.Decode
+var formats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Image คือรูปที่ decode แล้ว พร้อม orientation จาก EXIF ที่ยังไม่ได้หมุน
+type Image struct {
+	Src         image.Image
+	Format      string
+	Orientation int
+}
+
+// Sniff ตรวจชนิดรูปจากเนื้อไฟล์ ไม่เชื่อ Content-Type หรือนามสกุลที่ client ส่งมา
+func Sniff(data []byte) (string, error) {
+	format, ok := formats[http.DetectContentType(data)]
+	if !ok {
+		return "", ErrUnsupportedFormat
+	}
+	return format, nil
+}
+
+// Load ตรวจชนิดและขนาดก่อน แล้วจึง decode จริง
+func Load(data []byte) (*Image, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	format, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, cfgFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfgFormat != format {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxDimension || cfg.Height > MaxDimension ||
+		int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return &Image{Src: src, Format: format, Orientation: orientation}, nil
+}
+
+// Bounds คือขนาดรูปหลังหมุนตาม EXIF แล้ว
+func (img *Image) Bounds() (width, height int) {
+	b := img.Src.Bounds()
+	if swapsAxes(img.Orientation) {
+		return b.Dy(), b.Dx()
+	}
+	return b.Dx(), b.Dy()
+}
+
+// HasAlpha บอกว่ารูปมีส่วนโปร่งใสหรือไม่ ถ้ามีควร encode เป็น PNG แทน JPEG
+func (img *Image) HasAlpha() bool {
+	if o, ok := img.Src.(interface{ Opaque() bool }); ok {
+		return !o.Opaque()
+	}
+	return false
+}
+
+// Square ตัดตรงกลางให้เป็นสี่เหลี่ยมจัตุรัสแล้วย่อเป็น size x size
+func (img *Image) Square(size int) image.Image {
+	b := img.Src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img.Src, crop, draw.Src, nil)
+	// ตัดตรงกลางแบบจัตุรัสแล้วค่อยหมุนได้ผลเหมือนหมุนก่อนตัด และหมุนรูปเล็กเร็วกว่ามาก
+	return orient(dst, img.Orientation)
+}
+
+// FitWidth ย่อรูปให้กว้างไม่เกิน maxWidth โดยคงสัดส่วนไว้ ไม่ขยายรูปที่เล็กกว่า
+func (img *Image) FitWidth(maxWidth int) image.Image {
+	width, height := img.Bounds()
+	if width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	// ย่อในแกนของรูปต้นฉบับก่อน แล้วจึงหมุน
+	dw, dh := width, height
+	if swapsAxes(img.Orientation) {
+		dw, dh = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img.Src, img.Src.Bounds(), draw.Src, nil)
+	return orient(dst, img.Orientation)
+}
+
+// EncodeJPEG encode เป็น JPEG โดยไม่มี metadata ใดๆ
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodePNG encode เป็น PNG ใช้กับรูปที่มีพื้นโปร่งใส
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeWebP encode เป็น WebP แบบ lossless
+func EncodeWebP(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := nativewebp.Encode(&buf, img, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// แหล่งของไฟล์ที่นำเข้าได้ ใช้เลือกตัวอ่านใน ReadFile
+const (
+	SourceMarkdown  = "markdown"
+	SourceWordPress = "wordpress"
+	SourceMedium    = "medium"
+)
+
+// UnsupportedFileError คือไฟล์ที่นามสกุลไม่ตรงกับที่แหล่งนั้นอ่านได้
+type UnsupportedFileError struct {
+	Message string
+}
+
+func (e *UnsupportedFileError) Error() string {
+	return e.Message
+}
+
+// ReadFile อ่านไฟล์หนึ่งไฟล์ของแหล่ง source เลือกตัวอ่านตามนามสกุลของ filename
+// unread คือรายการที่อ่านไม่ได้หรือถูกข้ามสำหรับ Report.AddUnread
+// archive ไม่เป็น nil เมื่อไฟล์ WordPress หรือ Medium เป็น ZIP ใช้กับ NewImageSource เพื่อย้ายรูปที่แนบมา
+func ReadFile(source, filename string, data []byte) (posts []Post, unread []Result, archive *zip.Reader, err error) {
+	ext := strings.ToLower(path.Ext(filename))
+	if source == SourceMarkdown {
+		switch ext {
+		case ".md", ".markdown":
+			post, err := ParseMarkdown(filename, data)
+			if err != nil {
+				return nil, []Result{{Source: filename, Status: StatusInvalid, Errors: map[string]string{"file": err.Error()}}}, nil, nil
+			}
+			return []Post{post}, nil, nil, nil
+		case ".zip":
+			posts, unread, err = ReadMarkdownZip(data)
+			return posts, unread, nil, err
+		}
+		return nil, nil, nil, &UnsupportedFileError{Message: "Only .md and .zip files are supported"}
+	}
+
+	if ext == ".zip" {
+		if archive, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, nil, nil, errors.New("invalid zip file")
+		}
+	}
+	switch {
+	case source == SourceMedium && archive != nil:
+		posts, unread, err = ReadMediumZip(archive)
+	case source == SourceWordPress && archive != nil:
+		posts, unread, err = ReadWordPressZip(archive)
+	case source == SourceWordPress && ext == ".xml":
+		posts, unread, err = ReadWXR(data)
+	case source == SourceWordPress:
+		return nil, nil, nil, &UnsupportedFileError{Message: "Only .xml and .zip files are supported"}
+	case source == SourceMedium:
+		return nil, nil, nil, &UnsupportedFileError{Message: "Only .zip files are supported"}
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown import source %q", source)
+	}
+	return posts, unread, archive, err
+}
+
+// Export เขียนบทความเป็น ZIP ของไฟล์ Markdown รูปแบบเดียวกับที่ ReadFile อ่านกลับได้
+// articles ต้องโหลด Author, Category และ Tags มาแล้ว เช่นจาก composables.ArticleListQuery
+func Export(w io.Writer, articles []models.Article) error {
+	zw := zip.NewWriter(w)
+	for i := range articles {
+		article := &articles[i]
+		tags := make([]string, len(article.Tags))
+		for j, tag := range article.Tags {
+			tags[j] = tag.Name
+		}
+		// แยกโฟลเดอร์ตามผู้เขียน ไฟล์ของทั้งเว็บจะไม่ชนกันและนำเข้ากลับทีละคนได้
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     exportPathPart(article.Author.Username) + "/" + exportPathPart(article.Slug) + ".md",
+			Method:   zip.Deflate,
+			Modified: article.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		err = WriteMarkdown(fw, Post{
+			Title:          article.Title,
+			Slug:           article.Slug,
+			Content:        article.Content,
+			Category:       article.Category.Name,
+			Tags:           tags,
+			Date:           article.CreatedAt,
+			Excerpt:        article.Excerpt,
+			SEOTitle:       article.SEOTitle,
+			SEODescription: article.SEODescription,
+			CanonicalURL:   article.CanonicalURL,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ชื่อโฟลเดอร์และไฟล์ใน ZIP ต้องไม่มีตัวคั่น path ไม่อย่างนั้นแตกไฟล์แล้วจะออกนอกโฟลเดอร์
+func exportPathPart(s string) string {
+	return strings.NewReplacer("/", "-", "\\", "-").Replace(s)
+}
@@ -0,0 +1,234 @@
+package importer
+
+import (
+	"archive/zip"
+	"backend/composables"
+	"backend/database"
+	"backend/imaging"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ImageSource หาไฟล์รูปที่เนื้อหาอ้างถึง จากไฟล์ใน ZIP ที่อัปโหลดมาก่อน แล้วจึงดาวน์โหลดถ้าอนุญาต
+type ImageSource struct {
+	files    map[string]*zip.File
+	download bool
+	client   *http.Client
+	// รูปเดียวกันใช้หลายที่ เก็บครั้งเดียวต่อผู้เขียน
+	stored map[string]string
+}
+
+// NewImageSource สร้างตัวย้ายรูป archive เป็น nil ได้ถ้าไม่มีไฟล์รูปแนบมา
+func NewImageSource(archive *zip.Reader, download bool) *ImageSource {
+	s := &ImageSource{files: map[string]*zip.File{}, download: download, stored: map[string]string{}}
+	if archive != nil {
+		// เก็บทุกส่วนท้ายของ path เช่น backup/wp-content/uploads/a.jpg ค้นได้ทั้ง wp-content/uploads/a.jpg และ a.jpg
+		for _, file := range archive.File {
+			if file.FileInfo().IsDir() {
+				continue
+			}
+			parts := strings.Split(file.Name, "/")
+			for i := range parts {
+				key := strings.Join(parts[i:], "/")
+				if _, ok := s.files[key]; !ok {
+					s.files[key] = file
+				}
+			}
+		}
+	}
+	if download {
+		dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicAddressOnly}
+		s.client = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 10 * time.Second},
+		}
+	}
+	return s
+}
+
+// ไฟล์นำเข้ามาจากผู้ใช้ ห้ามใช้ URL ในไฟล์เรียกเข้าเครือข่ายภายในของเซิร์ฟเวอร์
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("address %s is not allowed", host)
+	}
+	return nil
+}
+
+var (
+	imgTagPattern  = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+	imgSrcPattern  = regexp.MustCompile(`(?is)(\ssrc\s*=\s*)(?:"([^"]*)"|'([^']*)')`)
+	srcsetPattern  = regexp.MustCompile(`(?is)\s(?:srcset|sizes)\s*=\s*(?:"[^"]*"|'[^']*')`)
+	linkTagPattern = regexp.MustCompile(`(?is)<a\b[^>]*>`)
+	hrefPattern    = regexp.MustCompile(`(?is)(\shref\s*=\s*)(?:"([^"]*)"|'([^']*)')`)
+	imageLinkPath  = regexp.MustCompile(`(?i)\.(?:jpe?g|png|gif|webp)$`)
+	// WordPress สร้างรูปย่อชื่อ photo-300x200.jpg จาก photo.jpg
+	wpResizedImage = regexp.MustCompile(`-\d+x\d+(\.[A-Za-z]+)$`)
+)
+
+// Rewrite เก็บรูปทุกรูปในเนื้อหาเป็น media ของผู้เขียนและเปลี่ยน src ให้ชี้มาที่ระบบ รวมถึงลิงก์ที่ชี้ไปไฟล์รูป
+// srcset ของระบบต้นทางถูกเอาออกเพราะชี้ไปรูปขนาดอื่นที่ไม่ได้ย้ายมา รูปที่ย้ายไม่ได้จะคงเดิมและอยู่ในคำเตือน
+func (s *ImageSource) Rewrite(ctx context.Context, content string, authorID uint) (string, []string) {
+	var warnings []string
+	warned := map[string]bool{}
+	replace := func(tag string, attr *regexp.Regexp) (string, bool) {
+		m := attr.FindStringSubmatchIndex(tag)
+		if m == nil {
+			return tag, false
+		}
+		// ค่าอยู่ในกลุ่มที่ 2 ถ้าใช้ "" และกลุ่มที่ 3 ถ้าใช้ ''
+		src := ""
+		if m[4] >= 0 {
+			src = tag[m[4]:m[5]]
+		} else {
+			src = tag[m[6]:m[7]]
+		}
+		src = html.UnescapeString(src)
+		if strings.HasPrefix(src, "/uploads/") || strings.HasPrefix(src, "data:") {
+			return tag, false
+		}
+		if attr == hrefPattern {
+			if u, err := url.Parse(src); err != nil || !imageLinkPath.MatchString(u.Path) {
+				return tag, false
+			}
+		}
+
+		stored, err := s.store(ctx, src, authorID)
+		if err != nil {
+			if !warned[src] {
+				warned[src] = true
+				warnings = append(warnings, fmt.Sprintf("image %s kept as is: %v", src, err))
+			}
+			return tag, false
+		}
+		return tag[:m[0]] + tag[m[2]:m[3]] + `"` + html.EscapeString(stored) + `"` + tag[m[1]:], true
+	}
+
+	content = imgTagPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		if tag, ok := replace(tag, imgSrcPattern); ok {
+			return srcsetPattern.ReplaceAllString(tag, "")
+		}
+		return tag
+	})
+	content = linkTagPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		tag, _ = replace(tag, hrefPattern)
+		return tag
+	})
+	return content, warnings
+}
+
+func (s *ImageSource) store(ctx context.Context, src string, authorID uint) (string, error) {
+	key := fmt.Sprintf("%d %s", authorID, src)
+	if stored, ok := s.stored[key]; ok {
+		return stored, nil
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", errors.New("invalid URL")
+	}
+
+	var data []byte
+	file := s.fromArchive(u)
+	switch {
+	case file != nil:
+		// รูปย่อหลายขนาดของรูปเดียวกันใช้ไฟล์ต้นฉบับเดียวกัน เก็บครั้งเดียว
+		key = fmt.Sprintf("%d zip:%s", authorID, file.Name)
+		if stored, ok := s.stored[key]; ok {
+			s.stored[fmt.Sprintf("%d %s", authorID, src)] = stored
+			return stored, nil
+		}
+		data, err = readZipImage(file)
+	case !s.download:
+		err = errors.New("not in the uploaded archive and downloading is turned off")
+	case u.Scheme == "http" || u.Scheme == "https":
+		data, err = s.fetch(ctx, u)
+	default:
+		err = errors.New("not in the uploaded archive")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	media, err := composables.StoreMedia(ctx, authorID, path.Base(u.Path), data)
+	if err != nil {
+		return "", err
+	}
+	if err := database.DB.Create(media).Error; err != nil {
+		composables.RemoveMediaFiles(media.StorageKey)
+		return "", err
+	}
+	s.stored[key] = media.URL
+	s.stored[fmt.Sprintf("%d %s", authorID, src)] = media.URL
+	return media.URL, nil
+}
+
+// ถ้าไม่มีรูปย่อใน ZIP ใช้รูปต้นฉบับแทน ระบบจะย่อรูปเองอยู่แล้ว
+func (s *ImageSource) fromArchive(u *url.URL) *zip.File {
+	name := strings.Trim(u.Path, "/")
+	if file := s.lookup(name); file != nil {
+		return file
+	}
+	return s.lookup(wpResizedImage.ReplaceAllString(name, "$1"))
+}
+
+func readZipImage(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readImage(rc)
+}
+
+func (s *ImageSource) lookup(name string) *zip.File {
+	parts := strings.Split(name, "/")
+	for i := range parts {
+		if file, ok := s.files[strings.Join(parts[i:], "/")]; ok {
+			return file
+		}
+	}
+	return nil
+}
+
+func (s *ImageSource) fetch(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	return readImage(resp.Body)
+}
+
+func readImage(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, imaging.MaxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > imaging.MaxUploadBytes {
+		return nil, composables.ErrMediaTooLarge
+	}
+	return data, nil
+}
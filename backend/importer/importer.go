@@ -0,0 +1,296 @@
+// Package importer นำบทความจากไฟล์ภายนอกเข้าสู่ระบบ ทุกรูปแบบไฟล์ถูกแปลงเป็น Post ก่อน
+// แล้วบันทึกด้วย Import ซึ่งตรวจข้อมูล หาความซ้ำ และรายงานผลของทุกรายการ
+package importer
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/metrics"
+	"backend/models"
+	"backend/repository"
+	"backend/validation"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Post คือบทความหนึ่งรายการที่อ่านได้จากไฟล์ Content เป็น HTML
+// Source คือชื่อไฟล์หรือตำแหน่งในไฟล์ต้นทาง ใช้บอกในรายงานว่ารายการไหนมีปัญหา
+type Post struct {
+	Source         string
+	Title          string
+	Slug           string
+	Content        string
+	Category       string
+	Tags           []string
+	Date           time.Time
+	Excerpt        string
+	SEOTitle       string
+	SEODescription string
+	CanonicalURL   string
+
+	// Author คือผู้เขียนในระบบต้นทาง nil คือผู้นำเข้าเป็นผู้เขียน
+	Author   *Author
+	Comments []Comment
+	// Warnings คือสิ่งที่ตัวอ่านไฟล์แปลงไม่ได้ครบ จะแสดงในรายงานของบทความนี้
+	Warnings []string
+}
+
+// Options คือการตั้งค่าการนำเข้า
+// Overwrite อนุญาตให้แทนที่บทความเดิมที่ slug ตรงกันถ้าเป็นของผู้เขียนคนเดียวกัน
+type Options struct {
+	AuthorID  uint
+	DryRun    bool
+	Overwrite bool
+
+	// CreateAuthors สร้างบัญชีให้ผู้เขียนที่ไม่พบในระบบ ถ้าไม่ตั้งบทความจะเป็นของผู้นำเข้า
+	CreateAuthors bool
+	// DefaultCategory ใช้กับบทความที่ไม่มีหมวดหมู่ เช่นบทความจาก Medium
+	DefaultCategory string
+	// Images ย้ายรูปในเนื้อหามาเก็บในระบบ nil คือไม่แตะรูป
+	Images *ImageSource
+}
+
+// สถานะของแต่ละรายการในรายงาน ตอน dry run คือสิ่งที่จะเกิดขึ้นถ้านำเข้าจริง
+const (
+	StatusCreate   = "create"
+	StatusUpdate   = "update"
+	StatusConflict = "conflict"
+	StatusInvalid  = "invalid"
+	StatusFailed   = "failed"
+	StatusSkipped  = "skipped"
+)
+
+// Result คือผลของบทความหนึ่งรายการ
+type Result struct {
+	Source    string            `json:"source"`
+	Title     string            `json:"title,omitempty"`
+	Slug      string            `json:"slug,omitempty"`
+	Status    string            `json:"status"`
+	ArticleID uint              `json:"article_id,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	Conflict  string            `json:"conflict,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// Report คือผลการนำเข้าทั้งหมด
+type Report struct {
+	DryRun    bool     `json:"dry_run"`
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Conflicts int      `json:"conflicts"`
+	Invalid   int      `json:"invalid"`
+	Failed    int      `json:"failed"`
+	Skipped   int      `json:"skipped"`
+	Items     []Result `json:"items"`
+
+	Authors []AuthorResult `json:"authors,omitempty"`
+}
+
+func (r *Report) add(result Result) {
+	switch result.Status {
+	case StatusCreate:
+		r.Created++
+	case StatusUpdate:
+		r.Updated++
+	case StatusConflict:
+		r.Conflicts++
+	case StatusInvalid:
+		r.Invalid++
+	case StatusFailed:
+		r.Failed++
+	case StatusSkipped:
+		r.Skipped++
+	}
+	r.Items = append(r.Items, result)
+}
+
+// AddUnread ใส่รายการที่ตัวอ่านไฟล์ข้ามหรืออ่านไม่ได้ไว้หน้ารายการที่นำเข้า
+func (r *Report) AddUnread(results []Result) {
+	for _, result := range results {
+		switch result.Status {
+		case StatusInvalid:
+			r.Invalid++
+		case StatusSkipped:
+			r.Skipped++
+		}
+	}
+	r.Items = append(results, r.Items...)
+}
+
+// ชื่อ field จาก validation ที่ต่างจากชื่อใน front matter
+var fieldNames = map[string]string{
+	"categoryname":   "category",
+	"tagnames":       "tags",
+	"seotitle":       "seo_title",
+	"seodescription": "seo_description",
+	"canonicalurl":   "canonical_url",
+}
+
+// Import ตรวจและบันทึกบทความทีละรายการ รายการที่มีปัญหาจะถูกข้ามและบอกไว้ในรายงาน ไม่ทำให้รายการอื่นล้มเหลว
+func Import(ctx context.Context, posts []Post, opts Options) *Report {
+	report := &Report{DryRun: opts.DryRun, Items: []Result{}}
+	authors := newAuthorResolver(opts, report)
+	seen := map[string]string{}
+
+	for _, post := range posts {
+		post.Title = strings.TrimSpace(post.Title)
+		post.Slug = strings.TrimSpace(post.Slug)
+		post.Category = strings.TrimSpace(post.Category)
+		if post.Category == "" {
+			post.Category = opts.DefaultCategory
+		}
+		result := Result{Source: post.Source, Title: post.Title, Slug: post.Slug, Warnings: post.Warnings}
+
+		input := validation.CreateArticleInput{
+			Title:          post.Title,
+			Slug:           post.Slug,
+			Content:        post.Content,
+			CategoryName:   post.Category,
+			TagNames:       post.Tags,
+			Excerpt:        post.Excerpt,
+			SEOTitle:       post.SEOTitle,
+			SEODescription: post.SEODescription,
+			CanonicalURL:   post.CanonicalURL,
+		}
+		if errs := validation.ValidateStructArticle(input); errs != nil {
+			result.Status, result.Errors = StatusInvalid, map[string]string{}
+			for field, msg := range errs {
+				if name, ok := fieldNames[field]; ok {
+					field = name
+				}
+				result.Errors[field] = msg
+			}
+			report.add(result)
+			continue
+		}
+
+		// slug ซ้ำกันเองในไฟล์ที่นำเข้าครั้งนี้
+		if other, ok := seen[strings.ToLower(post.Slug)]; ok {
+			result.Status, result.Conflict = StatusConflict, "same slug as "+other+" in this import"
+			report.add(result)
+			continue
+		}
+		seen[strings.ToLower(post.Slug)] = post.Source
+
+		authorID, err := authors.resolve(post.Author)
+		if err != nil {
+			result.Status, result.Errors = StatusFailed, map[string]string{"author": err.Error()}
+			report.add(result)
+			continue
+		}
+
+		existing, conflict := findConflict(&post, authorID, opts.Overwrite)
+		if conflict != "" {
+			result.Status, result.Conflict = StatusConflict, conflict
+			report.add(result)
+			continue
+		}
+		result.Status = StatusCreate
+		if existing != nil {
+			result.Status, result.ArticleID = StatusUpdate, existing.ID
+		}
+		if opts.DryRun {
+			report.add(result)
+			continue
+		}
+
+		if opts.Images != nil {
+			var warnings []string
+			post.Content, warnings = opts.Images.Rewrite(ctx, post.Content, authorID)
+			result.Warnings = append(result.Warnings, warnings...)
+		}
+		article, err := save(&post, existing, authorID)
+		if err != nil {
+			result.Status, result.Errors = StatusFailed, map[string]string{"article": err.Error()}
+			report.add(result)
+			continue
+		}
+		result.ArticleID = article.ID
+
+		// บทความที่แทนที่ของเดิมมีคอมเมนต์อยู่แล้ว นำเข้าซ้ำจะได้คอมเมนต์ซ้ำ
+		if existing != nil && len(post.Comments) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d comments not imported because the article already existed", len(post.Comments)))
+		} else if len(post.Comments) > 0 {
+			if warning := importComments(article.ID, post.Comments, authors); warning != "" {
+				result.Warnings = append(result.Warnings, warning)
+			}
+		}
+		report.add(result)
+	}
+	return report
+}
+
+// หาบทความเดิมที่ slug หรือชื่อซ้ำ คืนบทความที่จะถูกแทนที่ หรือเหตุผลที่นำเข้าไม่ได้
+func findConflict(post *Post, authorID uint, overwrite bool) (*models.Article, string) {
+	var matches []models.Article
+	if err := database.DB.Preload("Tags").Where("slug = ? OR title = ?", post.Slug, post.Title).Find(&matches).Error; err != nil {
+		return nil, "failed to check existing articles"
+	}
+	var existing *models.Article
+	for i := range matches {
+		match := &matches[i]
+		switch {
+		case match.Slug != post.Slug:
+			return nil, fmt.Sprintf("title is already used by article %q", match.Slug)
+		case !overwrite:
+			return nil, "an article with this slug already exists, import with overwrite to replace it"
+		case match.AuthorID != authorID:
+			return nil, "an article with this slug belongs to another author"
+		}
+		existing = match
+	}
+	return existing, ""
+}
+
+// บันทึกบทความใหม่ หรือแทนที่เนื้อหาของบทความเดิม
+func save(post *Post, existing *models.Article, authorID uint) (*models.Article, error) {
+	article := existing
+	var sitemapGroups []models.SitemapEntry
+	if article == nil {
+		article = &models.Article{AuthorID: authorID, CreatedAt: post.Date, Version: 1}
+	} else {
+		sitemapGroups = composables.ArticleSitemapGroups(database.DB, article)
+		// การนำเข้าทับก็เป็นการแก้ไข ผู้ที่เปิดแก้บทความนี้ค้างไว้จะได้ conflict แทนการบันทึกทับ
+		article.Version++
+	}
+	article.Title = post.Title
+	article.Slug = post.Slug
+	article.Content = post.Content
+	article.Excerpt = strings.TrimSpace(post.Excerpt)
+	article.SEOTitle = strings.TrimSpace(post.SEOTitle)
+	article.SEODescription = strings.TrimSpace(post.SEODescription)
+	article.CanonicalURL = strings.TrimSpace(post.CanonicalURL)
+
+	// หมวดหมู่และแท็กที่สร้างใหม่ถูก rollback ไปด้วยถ้าบันทึกบทความไม่สำเร็จ
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		category, err := repository.FindOrCreateCategory(tx, post.Category)
+		if err != nil {
+			return err
+		}
+		tags, err := repository.FindOrCreateTags(tx, post.Tags)
+		if err != nil {
+			return err
+		}
+		article.CategoryID = category.ID
+		if err := tx.Omit("Tags").Save(article).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(article).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		return composables.SyncArticleMedia(tx, article)
+	})
+	if err != nil {
+		return nil, err
+	}
+	composables.RefreshArticleSitemap(database.DB, article.ID, sitemapGroups)
+	if existing == nil {
+		metrics.ArticlesCreated.WithLabelValues(metrics.SourceImport).Inc()
+	}
+	return article, nil
+}
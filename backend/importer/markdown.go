@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"gopkg.in/yaml.v3"
+)
+
+// ขีดจำกัดของไฟล์ ZIP กันไฟล์ที่แตกออกมาใหญ่เกินหน่วยความจำ
+const (
+	maxZipFiles     = 1000
+	maxMarkdownSize = 2 << 20
+	maxZipTotalSize = 50 << 20
+)
+
+// FrontMatter คือส่วนหัว YAML ของไฟล์ Markdown ใช้ทั้งตอนนำเข้าและส่งออก
+type FrontMatter struct {
+	Title          string   `yaml:"title"`
+	Slug           string   `yaml:"slug"`
+	Category       string   `yaml:"category"`
+	Tags           []string `yaml:"tags"`
+	Date           string   `yaml:"date"`
+	Excerpt        string   `yaml:"excerpt,omitempty"`
+	SEOTitle       string   `yaml:"seo_title,omitempty"`
+	SEODescription string   `yaml:"seo_description,omitempty"`
+	CanonicalURL   string   `yaml:"canonical_url,omitempty"`
+	// Format บอกว่าเนื้อหาเป็น markdown (ค่าเริ่มต้น) หรือ html ไฟล์ที่ส่งออกจากระบบเป็น html
+	Format string `yaml:"format,omitempty"`
+}
+
+// ค่าของ format ใน front matter
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// รูปแบบวันที่ที่รับใน front matter
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	// เนื้อหาที่ส่งออกจากระบบเป็น HTML จึงต้องให้ HTML ในไฟล์ผ่านไปได้เหมือนตอนเขียนใน editor
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// ParseMarkdown อ่านไฟล์ Markdown หนึ่งไฟล์ที่มี front matter แปลงเนื้อหาเป็น HTML
+// ถ้า front matter ไม่มี slug จะใช้ชื่อไฟล์แทน
+func ParseMarkdown(name string, data []byte) (Post, error) {
+	post := Post{Source: name}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	lines := strings.SplitAfter(text, "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return post, errors.New("missing front matter")
+	}
+	end := 0
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end == 0 {
+		return post, errors.New("front matter is not closed with ---")
+	}
+	head := strings.Join(lines[1:end], "")
+	body := strings.Join(lines[end+1:], "")
+
+	var meta FrontMatter
+	dec := yaml.NewDecoder(strings.NewReader(head))
+	dec.KnownFields(true)
+	if err := dec.Decode(&meta); err != nil && !errors.Is(err, io.EOF) {
+		return post, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	post.Title = meta.Title
+	post.Slug = meta.Slug
+	if post.Slug == "" {
+		post.Slug = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	post.Category = meta.Category
+	post.Tags = meta.Tags
+	post.Excerpt = meta.Excerpt
+	post.SEOTitle = meta.SEOTitle
+	post.SEODescription = meta.SEODescription
+	post.CanonicalURL = meta.CanonicalURL
+	if meta.Date != "" {
+		date, err := parseDate(meta.Date)
+		if err != nil {
+			return post, err
+		}
+		post.Date = date
+	}
+
+	switch meta.Format {
+	case FormatHTML:
+		post.Content = strings.TrimSpace(body)
+	case "", FormatMarkdown:
+		var html bytes.Buffer
+		if err := markdown.Convert([]byte(body), &html); err != nil {
+			return post, fmt.Errorf("invalid markdown: %w", err)
+		}
+		post.Content = strings.TrimSpace(html.String())
+	default:
+		return post, fmt.Errorf("unknown format %q, use markdown or html", meta.Format)
+	}
+	return post, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or RFC 3339", value)
+}
+
+// ReadMarkdownZip อ่านไฟล์ .md ทุกไฟล์ใน ZIP ไฟล์อื่นจะถูกข้าม
+// ไฟล์ที่อ่านไม่ได้จะอยู่ใน failed พร้อมเหตุผล เพื่อแสดงในรายงานร่วมกับผลการนำเข้า
+func ReadMarkdownZip(data []byte) (posts []Post, failed []Result, err error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, errors.New("invalid zip file")
+	}
+	var total int64
+	count := 0
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".md") ||
+			strings.HasPrefix(path.Base(file.Name), ".") || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		if count++; count > maxZipFiles {
+			return nil, nil, fmt.Errorf("zip contains more than %d markdown files", maxZipFiles)
+		}
+		content, err := readZipFile(file)
+		if err == nil {
+			total += int64(len(content))
+			if total > maxZipTotalSize {
+				return nil, nil, fmt.Errorf("zip content is larger than %d MB", maxZipTotalSize>>20)
+			}
+		}
+		var post Post
+		if err == nil {
+			post, err = ParseMarkdown(file.Name, content)
+		}
+		if err != nil {
+			failed = append(failed, Result{Source: file.Name, Status: StatusInvalid, Errors: map[string]string{"file": err.Error()}})
+			continue
+		}
+		posts = append(posts, post)
+	}
+	return posts, failed, nil
+}
+
+// อ่านไม่เกินขนาดที่กำหนด ไม่เชื่อขนาดที่ส่วนหัวของ ZIP บอก
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(io.LimitReader(rc, maxMarkdownSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxMarkdownSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxMarkdownSize>>20)
+	}
+	return content, nil
+}
+
+// WriteMarkdown เขียนบทความเป็นไฟล์ Markdown ที่ ParseMarkdown อ่านกลับได้
+// เนื้อหาเป็น HTML ตามที่เก็บไว้และระบุ format: html เพื่อให้นำเข้ากลับได้ตรงทุกตัวอักษร
+func WriteMarkdown(w io.Writer, post Post) error {
+	meta := FrontMatter{
+		Title:          post.Title,
+		Slug:           post.Slug,
+		Category:       post.Category,
+		Tags:           post.Tags,
+		Date:           post.Date.UTC().Format(time.RFC3339),
+		Excerpt:        post.Excerpt,
+		SEOTitle:       post.SEOTitle,
+		SEODescription: post.SEODescription,
+		CanonicalURL:   post.CanonicalURL,
+		Format:         FormatHTML,
+	}
+	if meta.Tags == nil {
+		meta.Tags = []string{}
+	}
+	head, err := yaml.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "---\n%s---\n\n%s\n", head, strings.TrimSpace(post.Content))
+	return err
+}
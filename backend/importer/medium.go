@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// ท้าย URL และชื่อไฟล์ของ Medium มี id ฐานสิบหกต่อท้าย เช่น my-first-post-1a2b3c4d5e6f
+var mediumPostID = regexp.MustCompile(`-[0-9a-f]{8,16}$`)
+
+// ReadMediumZip อ่านไฟล์ export ของ Medium (Settings > Download your information)
+// บทความอยู่ในโฟลเดอร์ posts เป็น HTML หนึ่งไฟล์ต่อบทความ ไฟล์ที่ขึ้นต้นด้วย draft_ คือฉบับร่าง
+func ReadMediumZip(archive *zip.Reader) (posts []Post, skipped []Result, err error) {
+	var found bool
+	for _, file := range archive.File {
+		name := path.Base(file.Name)
+		if file.FileInfo().IsDir() || path.Base(path.Dir(file.Name)) != "posts" || !strings.EqualFold(path.Ext(name), ".html") {
+			continue
+		}
+		found = true
+		if strings.HasPrefix(name, "draft_") {
+			skipped = append(skipped, Result{Source: file.Name, Status: StatusSkipped, Reason: "draft"})
+			continue
+		}
+		post, err := readMediumPost(file)
+		if err != nil {
+			skipped = append(skipped, Result{Source: file.Name, Status: StatusInvalid, Errors: map[string]string{"file": err.Error()}})
+			continue
+		}
+		posts = append(posts, post)
+	}
+	if !found {
+		return nil, nil, errors.New("zip does not contain a Medium export (posts/*.html)")
+	}
+	return posts, skipped, nil
+}
+
+func readMediumPost(file *zip.File) (Post, error) {
+	post := Post{Source: file.Name}
+	rc, err := file.Open()
+	if err != nil {
+		return post, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxMarkdownSize+1))
+	if err != nil {
+		return post, err
+	}
+	if len(data) > maxMarkdownSize {
+		return post, errors.New("file is too large")
+	}
+	doc, err := nethtml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return post, err
+	}
+
+	if n := findNode(doc, func(n *nethtml.Node) bool { return n.Data == "h1" && hasClass(n, "p-name") }); n != nil {
+		post.Title = strings.TrimSpace(nodeText(n))
+	}
+	if n := findNode(doc, func(n *nethtml.Node) bool { return attr(n, "data-field") == "subtitle" }); n != nil {
+		post.Excerpt = strings.TrimSpace(nodeText(n))
+	}
+	if n := findNode(doc, func(n *nethtml.Node) bool { return n.Data == "time" && hasClass(n, "dt-published") }); n != nil {
+		post.Date, _ = time.Parse(time.RFC3339, attr(n, "datetime"))
+	}
+	if n := findNode(doc, func(n *nethtml.Node) bool { return n.Data == "a" && hasClass(n, "p-author") }); n != nil {
+		// ลิงก์ผู้เขียนเป็น https://medium.com/@username
+		if login := strings.TrimPrefix(path.Base(attr(n, "href")), "@"); login != "" && login != "." && login != "/" {
+			post.Author = &Author{Login: login, DisplayName: strings.TrimSpace(nodeText(n))}
+		}
+	}
+
+	// slug จาก URL ของบทความบน Medium ถ้าไม่มีใช้ชื่อไฟล์ เช่น 2020-01-02_My-Post-1a2b3c4d5e6f.html
+	slug := ""
+	if n := findNode(doc, func(n *nethtml.Node) bool { return n.Data == "a" && hasClass(n, "p-canonical") }); n != nil {
+		if u, err := url.Parse(attr(n, "href")); err == nil {
+			slug = path.Base(u.Path)
+		}
+	}
+	if slug == "" || slug == "." || slug == "/" {
+		slug = strings.TrimSuffix(path.Base(file.Name), path.Ext(file.Name))
+		if _, rest, ok := strings.Cut(slug, "_"); ok {
+			slug = rest
+		}
+	}
+	post.Slug = strings.ToLower(mediumPostID.ReplaceAllString(slug, ""))
+
+	body := findNode(doc, func(n *nethtml.Node) bool { return attr(n, "data-field") == "body" })
+	if body == nil {
+		return post, errors.New("post body not found")
+	}
+	cleanMediumBody(body)
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := nethtml.Render(&buf, c); err != nil {
+			return post, err
+		}
+	}
+	post.Content = strings.TrimSpace(buf.String())
+	return post, nil
+}
+
+// cleanMediumBody เอาส่วนที่ซ้ำกับ front matter (ชื่อเรื่องและคำโปรย) และโครงของ Medium ออก
+// section และ div ที่ใช้จัดหน้าถูกแทนด้วยเนื้อหาข้างใน และตัด class/id/name/data-* ที่ใช้กับ CSS ของ Medium
+func cleanMediumBody(n *nethtml.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type != nethtml.ElementNode {
+			c = next
+			continue
+		}
+		switch {
+		case hasClass(c, "graf--title") || hasClass(c, "graf--subtitle") || hasClass(c, "section-divider"):
+			n.RemoveChild(c)
+		case c.Data == "section" || c.Data == "div":
+			cleanMediumBody(c)
+			for c.FirstChild != nil {
+				child := c.FirstChild
+				c.RemoveChild(child)
+				n.InsertBefore(child, c)
+			}
+			n.RemoveChild(c)
+		default:
+			attrs := c.Attr[:0]
+			for _, a := range c.Attr {
+				if a.Key != "class" && a.Key != "id" && a.Key != "name" && !strings.HasPrefix(a.Key, "data-") {
+					attrs = append(attrs, a)
+				}
+			}
+			c.Attr = attrs
+			cleanMediumBody(c)
+		}
+		c = next
+	}
+}
+
+func findNode(n *nethtml.Node, match func(*nethtml.Node) bool) *nethtml.Node {
+	if n.Type == nethtml.ElementNode && match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findNode(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *nethtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *nethtml.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeText(n *nethtml.Node) string {
+	if n.Type == nethtml.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+	}
+	return b.String()
+}
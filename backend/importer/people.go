@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"backend/database"
+	"backend/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Author คือผู้เขียนในระบบต้นทาง
+type Author struct {
+	Login       string
+	Email       string
+	DisplayName string
+	FirstName   string
+	LastName    string
+}
+
+// Comment คือคอมเมนต์ในระบบต้นทาง Author เป็น nil ถ้าผู้เขียนคอมเมนต์ไม่มีบัญชี (guest)
+type Comment struct {
+	Author  *Author
+	Content string
+	Date    time.Time
+}
+
+// ผลการจับคู่ผู้เขียนกับบัญชีในระบบ
+const (
+	AuthorMatch  = "match"
+	AuthorCreate = "create"
+	AuthorAssign = "assign"
+)
+
+// AuthorResult บอกว่าผู้เขียนจากระบบต้นทางถูกจับคู่กับบัญชีไหน
+type AuthorResult struct {
+	Login    string `json:"login"`
+	Status   string `json:"status"`
+	UserID   uint   `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// บัญชีกลางที่รับคอมเมนต์ของ guest ในระบบต้นทาง
+const GuestUsername = "imported-guest"
+
+// GuestUser ค้นหาหรือสร้างบัญชีกลางของคอมเมนต์ที่ไม่มีเจ้าของ
+func GuestUser(tx *gorm.DB) (*models.User, error) {
+	user := models.User{
+		Username:  GuestUsername,
+		Email:     "imported-guest@invalid.local",
+		FirstName: "Guest",
+		Nickname:  "guest",
+		Role:      models.RoleUser,
+	}
+	if err := tx.Where("username = ?", GuestUsername).FirstOrCreate(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// จับคู่ผู้เขียนครั้งเดียวต่อคน และจดผลไว้ในรายงาน
+type authorResolver struct {
+	opts     Options
+	report   *Report
+	users    map[string]uint
+	assigned map[string]bool
+}
+
+func newAuthorResolver(opts Options, report *Report) *authorResolver {
+	return &authorResolver{opts: opts, report: report, users: map[string]uint{}, assigned: map[string]bool{}}
+}
+
+// resolve คืน id ของบัญชีที่เป็นผู้เขียน จับคู่จาก email ก่อนแล้วจึงเป็น username
+// ตอน dry run ผู้เขียนที่จะถูกสร้างได้ id เป็น 0
+func (r *authorResolver) resolve(author *Author) (uint, error) {
+	if author == nil || author.Login == "" {
+		return r.opts.AuthorID, nil
+	}
+	key := strings.ToLower(author.Login)
+	if id, ok := r.users[key]; ok {
+		return id, nil
+	}
+
+	result := AuthorResult{Login: author.Login}
+	var user models.User
+	err := gorm.ErrRecordNotFound
+	if author.Email != "" {
+		err = database.DB.Select("id", "username").Where("LOWER(email) = ?", strings.ToLower(author.Email)).First(&user).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err = database.DB.Select("id", "username").Where("LOWER(username) = ?", key).First(&user).Error; err == nil {
+			result.Note = "matched by username only"
+		}
+	}
+	switch {
+	case err == nil:
+		result.Status = AuthorMatch
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return 0, err
+	case !r.opts.CreateAuthors:
+		result.Status, result.Note = AuthorAssign, "no account found, articles assigned to the importer and comments to "+GuestUsername
+		user.ID = r.opts.AuthorID
+	case len(author.Login) < 3:
+		result.Status, result.Note = AuthorAssign, "login is too short for a username, articles assigned to the importer"
+		user.ID = r.opts.AuthorID
+	default:
+		result.Status = AuthorCreate
+		user = newAuthorUser(author)
+		if !r.opts.DryRun {
+			if err := database.DB.Create(&user).Error; err != nil {
+				return 0, err
+			}
+		}
+		result.Note = "new account without a password"
+	}
+	if result.Status != AuthorAssign {
+		result.UserID, result.Username = user.ID, user.Username
+	}
+	r.users[key] = user.ID
+	r.assigned[key] = result.Status == AuthorAssign
+	r.report.Authors = append(r.report.Authors, result)
+	return user.ID, nil
+}
+
+func newAuthorUser(author *Author) models.User {
+	nickname := author.DisplayName
+	if nickname == "" {
+		nickname = author.Login
+	}
+	email := author.Email
+	if email == "" {
+		email = strings.ToLower(author.Login) + "@invalid.local"
+	}
+	return models.User{
+		Username:  author.Login,
+		Email:     email,
+		FirstName: author.FirstName,
+		LastName:  author.LastName,
+		Nickname:  nickname,
+		Role:      models.RoleUser,
+	}
+}
+
+// บันทึกคอมเมนต์ของบทความที่เพิ่งสร้าง คืนคำเตือนถ้ามีคอมเมนต์ที่บันทึกไม่ได้
+func importComments(articleID uint, comments []Comment, authors *authorResolver) string {
+	rows := make([]models.Comment, 0, len(comments))
+	guests := 0
+	for _, comment := range comments {
+		content := strings.TrimSpace(comment.Content)
+		if content == "" {
+			continue
+		}
+		// คอมเมนต์ของผู้เขียนที่ไม่มีบัญชีไม่ควรกลายเป็นของผู้นำเข้า
+		var userID uint
+		if comment.Author != nil {
+			id, err := authors.resolve(comment.Author)
+			if err != nil {
+				return fmt.Sprintf("comments not imported: %v", err)
+			}
+			if !authors.assigned[strings.ToLower(comment.Author.Login)] {
+				userID = id
+			}
+		}
+		if userID == 0 {
+			guest, err := GuestUser(database.DB)
+			if err != nil {
+				return fmt.Sprintf("comments not imported: %v", err)
+			}
+			userID = guest.ID
+			guests++
+		}
+		rows = append(rows, models.Comment{ArticleID: articleID, UserID: userID, Content: content, CreatedAt: comment.Date})
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	if err := database.DB.CreateInBatches(rows, 100).Error; err != nil {
+		return fmt.Sprintf("comments not imported: %v", err)
+	}
+	if guests > 0 {
+		return fmt.Sprintf("%d guest comments attributed to %s", guests, GuestUsername)
+	}
+	return ""
+}
@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// ขนาดสูงสุดของไฟล์ WXR ใน ZIP
+const maxWXRSize = 50 << 20
+
+// โครงสร้างของไฟล์ WordPress eXtended RSS (Tools > Export ของ WordPress)
+// ใช้ชื่อ element โดยไม่ระบุ namespace เพราะ wp: เปลี่ยน URL ตามเวอร์ชันของไฟล์ (1.0, 1.1, 1.2)
+type wxrChannel struct {
+	Authors []wxrAuthor `xml:"channel>author"`
+	Items   []wxrItem   `xml:"channel>item"`
+}
+
+type wxrAuthor struct {
+	ID          int    `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+	FirstName   string `xml:"author_first_name"`
+	LastName    string `xml:"author_last_name"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	PubDate    string        `xml:"pubDate"`
+	Creator    string        `xml:"creator"`
+	Encoded    []wxrEncoded  `xml:"encoded"`
+	PostID     string        `xml:"post_id"`
+	PostDate   string        `xml:"post_date_gmt"`
+	PostName   string        `xml:"post_name"`
+	Status     string        `xml:"status"`
+	PostType   string        `xml:"post_type"`
+	Categories []wxrCategory `xml:"category"`
+	Comments   []wxrComment  `xml:"comment"`
+}
+
+// content:encoded กับ excerpt:encoded มีชื่อเดียวกัน แยกด้วย namespace
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+type wxrComment struct {
+	Author   string `xml:"comment_author"`
+	Email    string `xml:"comment_author_email"`
+	Date     string `xml:"comment_date_gmt"`
+	Content  string `xml:"comment_content"`
+	Approved string `xml:"comment_approved"`
+	Type     string `xml:"comment_type"`
+	UserID   int    `xml:"comment_user_id"`
+}
+
+// ReadWXR อ่านไฟล์ export ของ WordPress เฉพาะโพสต์ที่เผยแพร่แล้ว
+// หน้า (page) ฉบับร่าง และชนิดอื่นจะอยู่ใน skipped ส่วนไฟล์แนบ (attachment) ถูกใช้ผ่านรูปในเนื้อหาจึงไม่แสดง
+func ReadWXR(data []byte) (posts []Post, skipped []Result, err error) {
+	var doc wxrChannel
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid WordPress export: %w", err)
+	}
+	if len(doc.Items) == 0 && len(doc.Authors) == 0 {
+		return nil, nil, errors.New("invalid WordPress export: no items found")
+	}
+
+	byLogin := map[string]*Author{}
+	byID := map[int]*Author{}
+	for _, a := range doc.Authors {
+		author := &Author{Login: a.Login, Email: a.Email, DisplayName: a.DisplayName, FirstName: a.FirstName, LastName: a.LastName}
+		byLogin[a.Login] = author
+		byID[a.ID] = author
+	}
+
+	for _, item := range doc.Items {
+		source := "post " + item.PostID
+		title := html.UnescapeString(strings.TrimSpace(item.Title))
+		switch {
+		case item.PostType == "attachment":
+			continue
+		case item.PostType != "post":
+			skipped = append(skipped, Result{Source: source, Title: title, Status: StatusSkipped, Reason: item.PostType + " is not imported"})
+			continue
+		case item.Status != "publish":
+			skipped = append(skipped, Result{Source: source, Title: title, Status: StatusSkipped, Reason: "status is " + item.Status})
+			continue
+		}
+
+		post := Post{Source: source, Title: title}
+		// WordPress เก็บ slug ที่มีอักษรไทยเป็น percent-encoding
+		post.Slug, _ = url.PathUnescape(item.PostName)
+		for _, encoded := range item.Encoded {
+			switch {
+			case strings.Contains(encoded.XMLName.Space, "/excerpt/"):
+				post.Excerpt = strings.TrimSpace(encoded.Value)
+			case strings.Contains(encoded.XMLName.Space, "/content/"):
+				post.Content = wpContent(encoded.Value)
+			}
+		}
+		post.Date = wpDate(item.PostDate, item.PubDate)
+
+		for _, category := range item.Categories {
+			name := html.UnescapeString(strings.TrimSpace(category.Name))
+			switch {
+			case category.Domain == "post_tag":
+				post.Tags = append(post.Tags, name)
+			case category.Domain != "category":
+			case post.Category == "":
+				post.Category = name
+			default:
+				// บทความมีได้หมวดหมู่เดียว หมวดหมู่ที่เหลือเก็บเป็นแท็ก
+				post.Tags = append(post.Tags, name)
+				post.Warnings = append(post.Warnings, fmt.Sprintf("extra category %q imported as a tag", name))
+			}
+		}
+
+		if author, ok := byLogin[item.Creator]; ok {
+			post.Author = author
+		} else if item.Creator != "" {
+			post.Author = &Author{Login: item.Creator}
+		}
+
+		dropped := 0
+		for _, c := range item.Comments {
+			// pingback, trackback, สแปม และคอมเมนต์ที่ยังไม่อนุมัติไม่นำเข้า
+			if c.Approved != "1" || (c.Type != "" && c.Type != "comment") {
+				dropped++
+				continue
+			}
+			comment := Comment{Content: htmlToText(c.Content), Date: wpDate(c.Date, "")}
+			if author, ok := byID[c.UserID]; ok && c.UserID != 0 {
+				comment.Author = author
+			}
+			post.Comments = append(post.Comments, comment)
+		}
+		if dropped > 0 {
+			post.Warnings = append(post.Warnings, fmt.Sprintf("%d comments skipped (unapproved, spam, pingback or trackback)", dropped))
+		}
+		posts = append(posts, post)
+	}
+	return posts, skipped, nil
+}
+
+// ReadWordPressZip อ่าน ZIP ที่มีไฟล์ WXR (.xml) และโฟลเดอร์ wp-content/uploads สำหรับย้ายรูป
+func ReadWordPressZip(archive *zip.Reader) (posts []Post, skipped []Result, err error) {
+	var found bool
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".xml") || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxWXRSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) > maxWXRSize {
+			return nil, nil, fmt.Errorf("%s is larger than %d MB", file.Name, maxWXRSize>>20)
+		}
+		// WordPress แบ่งไฟล์ export ใหญ่เป็นหลายไฟล์ได้ จึงอ่านทุกไฟล์
+		p, s, err := ReadWXR(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		posts, skipped, found = append(posts, p...), append(skipped, s...), true
+	}
+	if !found {
+		return nil, nil, errors.New("zip does not contain a WordPress export (.xml)")
+	}
+	return posts, skipped, nil
+}
+
+func wpDate(gmt, fallback string) time.Time {
+	if date, err := time.Parse("2006-01-02 15:04:05", gmt); err == nil && date.Year() > 1 {
+		return date
+	}
+	if date, err := time.Parse(time.RFC1123Z, fallback); err == nil {
+		return date
+	}
+	return time.Time{}
+}
+
+var (
+	wpBlockComment = regexp.MustCompile(`(?s)<!--\s*/?wp:.*?-->`)
+	wpCaption      = regexp.MustCompile(`(?s)\[caption[^\]]*\]\s*((?:<a\b[^>]*>\s*)?<img\b[^>]*>(?:\s*</a>)?)(.*?)\[/caption\]`)
+	wpPreBlock     = regexp.MustCompile(`(?is)<pre\b.*?</pre>`)
+	wpParagraphs   = regexp.MustCompile(`\n\s*\n`)
+	wpBlockStart   = regexp.MustCompile(`(?i)^<(?:p|div|h[1-6]|ul|ol|li|dl|blockquote|pre|table|figure|hr|iframe|section|address|form)\b`)
+)
+
+// wpContent แปลงเนื้อหาของ WordPress เป็น HTML ที่แสดงได้โดยไม่ต้องมี WordPress
+// ตัด comment ของ block editor แปลง [caption] เป็น <figure> และใส่ <p> ให้เนื้อหาจาก classic editor แบบ wpautop
+func wpContent(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = wpBlockComment.ReplaceAllString(content, "")
+	content = wpCaption.ReplaceAllString(content, "<figure>$1<figcaption>$2</figcaption></figure>")
+
+	// <pre> อาจมีบรรทัดว่างข้างใน ต้องกันไว้ก่อนแบ่งย่อหน้า
+	var pre []string
+	content = wpPreBlock.ReplaceAllStringFunc(content, func(block string) string {
+		pre = append(pre, block)
+		return "\n\n\x00" + strconv.Itoa(len(pre)-1) + "\x00\n\n"
+	})
+
+	var out []string
+	for _, chunk := range wpParagraphs.Split(content, -1) {
+		chunk = strings.TrimSpace(chunk)
+		switch {
+		case chunk == "":
+		case strings.HasPrefix(chunk, "\x00"):
+			i, _ := strconv.Atoi(strings.Trim(chunk, "\x00"))
+			out = append(out, pre[i])
+		case wpBlockStart.MatchString(chunk):
+			out = append(out, chunk)
+		default:
+			out = append(out, "<p>"+strings.ReplaceAll(chunk, "\n", "<br>\n")+"</p>")
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// htmlToText แปลงคอมเมนต์ที่อาจมี HTML เป็นข้อความล้วน เพราะคอมเมนต์ในระบบแสดงเป็นข้อความ
+func htmlToText(s string) string {
+	var buf bytes.Buffer
+	tokens := nethtml.NewTokenizer(strings.NewReader(s))
+	for {
+		switch tokens.Next() {
+		case nethtml.ErrorToken:
+			text := strings.TrimSpace(buf.String())
+			for strings.Contains(text, "\n\n\n") {
+				text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+			}
+			return text
+		case nethtml.TextToken:
+			buf.Write(tokens.Text())
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken, nethtml.EndTagToken:
+			name, _ := tokens.TagName()
+			switch string(name) {
+			case "br":
+				buf.WriteString("\n")
+			case "p":
+				buf.WriteString("\n\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/models"
+	"log/slog"
+	"time"
+)
+
+// ProcessAccountDeletions ลบบัญชีที่พ้นช่วงเวลาผ่อนผันแล้ว
+func ProcessAccountDeletions() error {
+	_, _, err := DeleteDueAccounts()
+	return err
+}
+
+// DeleteDueAccounts ลบบัญชีที่พ้นช่วงเวลาผ่อนผันแล้ว คืนจำนวนบัญชีที่ลบได้และที่ลบไม่สำเร็จ
+// บัญชีที่ลบไม่สำเร็จถูกบันทึกลง log แล้วข้ามไป รอบถัดไปจะลองใหม่
+func DeleteDueAccounts() (deleted, failed int, err error) {
+	var users []models.User
+	if err := database.DB.
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", time.Now()).
+		Find(&users).Error; err != nil {
+		return 0, 0, err
+	}
+
+	for _, user := range users {
+		mode := models.DeletionModeAnonymize
+		if user.DeletionMode != nil {
+			mode = *user.DeletionMode
+		}
+		if err := composables.DeleteAccount(user.ID, mode); err != nil {
+			slog.Error("failed to delete account", "user_id", user.ID, "error", err)
+			failed++
+			continue
+		}
+		slog.Info("deleted account", "user_id", user.ID, "mode", mode)
+		deleted++
+	}
+	return deleted, failed, nil
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Start เริ่มงานเบื้องหลังทั้งหมด คืน stop ที่หยุดรอบถัดไปแล้วรอให้งานที่กำลังทำอยู่จบ
+// ถ้ายังมีงานที่ทำไม่จบเมื่อ ctx ของ stop หมดเวลา stop คืน error ที่บอกชื่องานนั้น
+func Start() (stop func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &runner{running: map[string]bool{}}
+	r.every(ctx, "account deletion", time.Hour, ProcessAccountDeletions)
+	r.every(ctx, "media cleanup", time.Hour, CollectOrphanMedia)
+	r.every(ctx, "sitemap sync", 24*time.Hour, SyncSitemap)
+	r.every(ctx, "login attempt cleanup", 24*time.Hour, PruneLoginAttempts)
+	r.every(ctx, "session cleanup", 24*time.Hour, PruneSessions)
+
+	return func(ctx context.Context) error {
+		cancel()
+		done := make(chan struct{})
+		go func() {
+			r.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+		}
+		// งานที่รอรอบถัดไปอยู่หยุดทันทีที่ถูกยกเลิก นับว่าไม่จบเฉพาะงานที่กำลังทำอยู่จริง
+		if names := r.busy(); len(names) > 0 {
+			return fmt.Errorf("background jobs did not finish (%s): %w", strings.Join(names, ", "), ctx.Err())
+		}
+		return nil
+	}
+}
+
+type runner struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// รันงานทันทีหนึ่งครั้ง แล้วรันซ้ำทุก interval
+func (r *runner) every(ctx context.Context, name string, interval time.Duration, job func() error) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			r.run(name, job)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// ถูกยกเลิกพร้อมกับถึงรอบ ไม่เริ่มงานใหม่
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (r *runner) run(name string, job func() error) {
+	r.mu.Lock()
+	r.running[name] = true
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.running, name)
+		r.mu.Unlock()
+	}()
+
+	if err := job(); err != nil {
+		slog.Error("job failed", "job", name, "error", err)
+	}
+}
+
+// ชื่องานที่กำลังทำอยู่ เรียงตามชื่อ
+func (r *runner) busy() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.running))
+	for name := range r.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
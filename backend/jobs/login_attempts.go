@@ -0,0 +1,16 @@
+package jobs
+
+import (
+	"backend/composables"
+	"log/slog"
+	"time"
+)
+
+// PruneLoginAttempts ลบประวัติการเข้าสู่ระบบที่ไม่ได้ใช้นับการล็อกและเก่าเกินกว่าที่เก็บไว้ให้แอดมินดู
+func PruneLoginAttempts() error {
+	pruned, err := composables.PruneLoginAttempts(time.Now())
+	if pruned > 0 {
+		slog.Info("pruned login attempts", "count", pruned)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// CollectOrphanMedia ลบรูปที่ไม่มีบทความใดใช้นานเกินช่วงผ่อนผัน
+// และไฟล์ใน storage ที่ไม่มีข้อมูล media ในฐานข้อมูลแล้ว (เช่นอัปโหลดไม่สำเร็จ)
+func CollectOrphanMedia() error {
+	// รูปที่ไม่มีบทความใช้จะถูกเก็บไว้ช่วงหนึ่งก่อนลบ เผื่อผู้เขียนยังไม่ได้บันทึกบทความ
+	_, _, err := RemoveOrphanMedia(time.Now().Add(-config.Get().MediaOrphanGrace))
+	return err
+}
+
+// RemoveOrphanMedia ลบรูปที่ไม่มีบทความใช้ตั้งแต่ก่อน cutoff และไฟล์ที่ไม่มีแถวใน media ที่เก่ากว่า cutoff
+// คืนจำนวนรูปและจำนวนไฟล์ที่ลบ
+func RemoveOrphanMedia(cutoff time.Time) (media, files int, err error) {
+	var orphans []models.Media
+	if err := database.DB.
+		Where("id NOT IN (?)", database.DB.Table("article_media").Select("media_id")).
+		Where("COALESCE(detached_at, created_at) < ?", cutoff).
+		Find(&orphans).Error; err != nil {
+		return 0, 0, err
+	}
+	for _, orphan := range orphans {
+		// ลบเฉพาะถ้ายังไม่มีใครใช้ตอนลบจริง
+		result := database.DB.
+			Where("id = ? AND id NOT IN (?)", orphan.ID, database.DB.Table("article_media").Select("media_id")).
+			Delete(&models.Media{})
+		if result.Error != nil {
+			slog.Error("failed to delete orphan media", "media_id", orphan.ID, "error", result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			composables.RemoveMediaFiles(orphan.StorageKey)
+			media++
+		}
+	}
+
+	// ไฟล์ที่ไม่มีแถวใน media
+	ctx := context.Background()
+	objects, err := storage.Default().List(ctx, "media/")
+	if err != nil {
+		return media, 0, err
+	}
+	var keys []string
+	if err := database.DB.Model(&models.Media{}).Pluck("storage_key", &keys).Error; err != nil {
+		return media, 0, err
+	}
+	known := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		known[key] = true
+	}
+	for _, obj := range objects {
+		i := strings.LastIndex(obj.Key, "_")
+		if i < 0 || known[obj.Key[:i]] || obj.ModTime.After(cutoff) {
+			continue
+		}
+		if err := storage.Default().Delete(ctx, obj.Key); err != nil {
+			slog.Error("failed to delete orphan file", "key", obj.Key, "error", err)
+			continue
+		}
+		files++
+	}
+
+	if media+files > 0 {
+		slog.Info("removed orphan media", "count", media, "files", files)
+	}
+	return media, files, nil
+}
@@ -0,0 +1,16 @@
+package jobs
+
+import (
+	"backend/composables"
+	"log/slog"
+	"time"
+)
+
+// PruneSessions ลบ session ที่ถูกยกเลิกหรือหมดอายุแล้ว ซึ่งไม่มีวันกลับมาใช้ได้อีก
+func PruneSessions() error {
+	pruned, err := composables.PruneSessions(time.Now())
+	if pruned > 0 {
+		slog.Info("pruned sessions", "count", pruned)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/models"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type sitemapKey struct {
+	Kind       string
+	Identifier string
+}
+
+// SyncSitemap เทียบ sitemap กับบทความทั้งหมดแล้วแก้เฉพาะรายการที่ต่างกัน
+// ปกติ sitemap ถูกอัปเดตทีละบทความอยู่แล้ว งานนี้เก็บส่วนที่เปลี่ยนจากที่อื่น เช่นลบบัญชีผู้ใช้หรือแก้ชื่อแท็ก
+func SyncSitemap() error {
+	added, updated, removed, err := RebuildSitemap()
+	if err != nil {
+		return err
+	}
+	if removed+updated+added > 0 {
+		slog.Info("sitemap synced", "added", added, "updated", updated, "removed", removed)
+	}
+	return nil
+}
+
+// RebuildSitemap ทำให้ sitemap_entries ตรงกับบทความทั้งหมด คืนจำนวนรายการที่เพิ่ม แก้ และลบ
+func RebuildSitemap() (added, updated, removed int, err error) {
+	want := map[sitemapKey]time.Time{}
+	bump := func(key sitemapKey, t time.Time) {
+		if t.After(want[key]) {
+			want[key] = t
+		}
+	}
+
+	usernames := map[uint]string{}
+	var users []models.User
+	if err := database.DB.Select("id", "username").
+		Where("id IN (?)", database.DB.Model(&models.Article{}).Select("author_id")).
+		Find(&users).Error; err != nil {
+		return 0, 0, 0, err
+	}
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+
+	var batch []models.Article
+	err = database.DB.Select("id", "slug", "canonical_url", "updated_at", "category_id", "author_id").
+		Preload("Tags").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				article := &batch[i]
+				if composables.ArticleInSitemap(article) {
+					bump(sitemapKey{models.SitemapKindArticle, article.Slug}, article.UpdatedAt)
+				}
+				groups := []sitemapKey{{models.SitemapKindCategory, strconv.FormatUint(uint64(article.CategoryID), 10)}}
+				for _, page := range composables.SitemapPages {
+					groups = append(groups, sitemapKey{models.SitemapKindPage, page})
+				}
+				for _, tag := range article.Tags {
+					groups = append(groups, sitemapKey{models.SitemapKindTag, tag.Name})
+				}
+				if username := usernames[article.AuthorID]; username != "" {
+					groups = append(groups, sitemapKey{models.SitemapKindAuthor, username})
+				}
+				for _, group := range groups {
+					bump(group, article.UpdatedAt)
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var existing []models.SitemapEntry
+	if err := database.DB.Find(&existing).Error; err != nil {
+		return 0, 0, 0, err
+	}
+	for _, entry := range existing {
+		key := sitemapKey{entry.Kind, entry.Identifier}
+		lastMod, ok := want[key]
+		delete(want, key)
+		switch {
+		case !ok:
+			if err := database.DB.Delete(&entry).Error; err != nil {
+				return added, updated, removed, err
+			}
+			removed++
+		case !entry.LastMod.Equal(lastMod):
+			if err := database.DB.Model(&entry).Update("last_mod", lastMod).Error; err != nil {
+				return added, updated, removed, err
+			}
+			updated++
+		}
+	}
+	missing := make([]models.SitemapEntry, 0, len(want))
+	for key, lastMod := range want {
+		missing = append(missing, models.SitemapEntry{Kind: key.Kind, Identifier: key.Identifier, LastMod: lastMod})
+	}
+	// เรียงก่อนเพิ่ม ให้ลำดับใน sitemap เหมือนกันทุกครั้งที่สร้างใหม่
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Kind != missing[j].Kind {
+			return missing[i].Kind < missing[j].Kind
+		}
+		return missing[i].Identifier < missing[j].Identifier
+	})
+	if len(missing) > 0 {
+		if err := database.DB.CreateInBatches(missing, 500).Error; err != nil {
+			return added, updated, removed, err
+		}
+		added = len(missing)
+	}
+	return added, updated, removed, nil
+}
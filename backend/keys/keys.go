@@ -0,0 +1,308 @@
+package keys
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const refreshInterval = time.Minute
+
+type entry struct {
+	kid       string
+	alg       string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	retired   bool
+}
+
+var (
+	settings  = config.Default().JWT
+	mu        sync.RWMutex
+	loaded    map[string]*entry
+	active    *entry
+	lastLoad  time.Time
+	rotateMux sync.Mutex
+)
+
+// Init ตั้งวิธีเซ็น secret และรอบการหมุน key ตาม cfg ต้องเรียกก่อนออก token
+func Init(cfg config.JWT) {
+	settings = cfg
+}
+
+// Algorithm คือวิธีเซ็นที่ตั้งไว้ใน JWT_ALG ค่าเริ่มต้นคือ HS256 (ใช้ JWT_SECRET แบบเดิม)
+func Algorithm() string {
+	switch alg := settings.Alg; alg {
+	case AlgRS256, AlgEdDSA:
+		return alg
+	default:
+		return AlgHS256
+	}
+}
+
+// TokenTTL คืออายุของ token และ session ที่สร้างตอน login
+func TokenTTL() time.Duration {
+	return settings.TokenTTL
+}
+
+func legacySecret() []byte {
+	return []byte(settings.Secret)
+}
+
+// Signer คืน key ที่ใช้เซ็น token ใหม่ ถ้า key ปัจจุบันเก่ากว่ารอบการหมุนจะสร้าง key ใหม่ให้
+func Signer() (kid string, method jwt.SigningMethod, key interface{}, err error) {
+	alg := Algorithm()
+	if alg == AlgHS256 {
+		return "", jwt.SigningMethodHS256, legacySecret(), nil
+	}
+
+	current, err := activeKey(alg)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return current.kid, signingMethod(alg), current.private, nil
+}
+
+// Keyfunc ใช้กับ jwt.Parse เลือก public key ตาม kid ใน header
+// token ที่ไม่มี kid คือ token HS256 แบบเดิม ยังรับได้ถ้ามี JWT_SECRET
+func Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if token.Method.Alg() != AlgHS256 || len(legacySecret()) == 0 {
+			return nil, errors.New("token has no kid")
+		}
+		return legacySecret(), nil
+	}
+
+	k, err := lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != k.alg {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), kid)
+	}
+	return k.public, nil
+}
+
+// PublicKeys คืน key ทั้งหมดที่ยังใช้ตรวจ token ได้ สำหรับ JWKS
+func PublicKeys() ([]JWK, error) {
+	if err := refresh(false); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+
+	jwks := make([]JWK, 0, len(loaded))
+	for _, k := range loaded {
+		jwk, err := toJWK(k)
+		if err != nil {
+			return nil, err
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks, nil
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	if alg == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func lookup(kid string) (*entry, error) {
+	if err := refresh(false); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	k, ok := loaded[kid]
+	mu.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	// instance อื่นอาจเพิ่งหมุน key โหลดใหม่อีกครั้งก่อนปฏิเสธ
+	if err := refresh(true); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if k, ok := loaded[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key %s", kid)
+}
+
+func activeKey(alg string) (*entry, error) {
+	if err := refresh(false); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	current := active
+	mu.RUnlock()
+
+	if current != nil && current.alg == alg && time.Since(current.createdAt) < settings.KeyRotation {
+		return current, nil
+	}
+
+	if err := rotate(alg); err != nil {
+		return nil, err
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if active == nil {
+		return nil, errors.New("no active signing key")
+	}
+	return active, nil
+}
+
+// โหลด key ที่ยังไม่หมดอายุจากฐานข้อมูล เพื่อให้ทุก instance เห็น key ชุดเดียวกัน
+func refresh(force bool) error {
+	mu.RLock()
+	fresh := loaded != nil && time.Since(lastLoad) < refreshInterval
+	mu.RUnlock()
+	if fresh && !force {
+		return nil
+	}
+
+	var rows []models.SigningKey
+	if err := database.DB.
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+
+	next := make(map[string]*entry, len(rows))
+	var newest *entry
+	for _, row := range rows {
+		k, err := parseEntry(row)
+		if err != nil {
+			slog.Warn("skipping unreadable signing key", "kid", row.Kid, "error", err)
+			continue
+		}
+		next[k.kid] = k
+		if newest == nil && !k.retired {
+			newest = k
+		}
+	}
+
+	mu.Lock()
+	loaded, active, lastLoad = next, newest, time.Now()
+	mu.Unlock()
+	return nil
+}
+
+// สร้าง key ใหม่และ retire key เดิม key เดิมยังตรวจ token ได้อีก JWT_KEY_RETENTION
+func rotate(alg string) error {
+	rotateMux.Lock()
+	defer rotateMux.Unlock()
+
+	// อาจมี goroutine อื่นหมุนไปแล้วระหว่างรอ lock
+	if err := refresh(true); err != nil {
+		return err
+	}
+	mu.RLock()
+	current := active
+	mu.RUnlock()
+	if current != nil && current.alg == alg && time.Since(current.createdAt) < settings.KeyRotation {
+		return nil
+	}
+
+	row, err := generate(alg)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expires := now.Add(settings.KeyRetention)
+	if err := database.DB.Model(&models.SigningKey{}).
+		Where("retired_at IS NULL").
+		Updates(map[string]interface{}{"retired_at": now, "expires_at": expires}).Error; err != nil {
+		return err
+	}
+	if err := database.DB.Create(&row).Error; err != nil {
+		return err
+	}
+	if err := database.DB.Where("expires_at < ?", now).Delete(&models.SigningKey{}).Error; err != nil {
+		return err
+	}
+	slog.Info("rotated jwt signing key", "kid", row.Kid, "alg", alg)
+
+	return refresh(true)
+}
+
+func generate(alg string) (models.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return models.SigningKey{}, fmt.Errorf("unsupported algorithm %s", alg)
+	}
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return models.SigningKey{}, err
+	}
+
+	return models.SigningKey{
+		Kid:        time.Now().UTC().Format("20060102") + "-" + hex.EncodeToString(id),
+		Algorithm:  alg,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil
+}
+
+func parseEntry(row models.SigningKey) (*entry, error) {
+	block, _ := pem.Decode([]byte(row.PrivateKey))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("key cannot sign")
+	}
+	return &entry{
+		kid:       row.Kid,
+		alg:       row.Algorithm,
+		private:   signer,
+		public:    signer.Public(),
+		createdAt: row.CreatedAt,
+		retired:   row.RetiredAt != nil,
+	}, nil
+}
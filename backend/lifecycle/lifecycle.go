@@ -0,0 +1,69 @@
+// Package lifecycle เริ่มส่วนต่างๆ ของ server ตามลำดับที่กำหนด และปิดย้อนลำดับตอนจบ
+//
+// แต่ละขั้นมีชื่อที่ใช้ใน log และใน error ถ้าเริ่มไม่สำเร็จ จึงเห็นทันทีว่าพังที่ขั้นไหน
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Step คือขั้นหนึ่งของการเริ่ม server
+// Stop ไม่บังคับ ถูกเรียกเฉพาะเมื่อ Start ของขั้นนี้สำเร็จแล้ว และต้องจบภายใน ctx ที่ได้รับ
+type Step struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Manager เก็บขั้นตามลำดับที่เพิ่ม และจำว่าขั้นไหนเริ่มไปแล้ว
+type Manager struct {
+	steps   []Step
+	started []Step
+}
+
+// Add เพิ่มขั้นต่อท้าย ขั้นที่เพิ่มก่อนเริ่มก่อนและปิดทีหลัง
+func (m *Manager) Add(steps ...Step) {
+	m.steps = append(m.steps, steps...)
+}
+
+// Start เริ่มทุกขั้นตามลำดับ หยุดที่ขั้นแรกที่ผิดพลาดแล้วคืน error ที่มีชื่อขั้นนั้น
+// ขั้นที่เริ่มไปแล้วยังทำงานอยู่ ผู้เรียกต้องเรียก Stop เสมอไม่ว่า Start จะสำเร็จหรือไม่
+func (m *Manager) Start(ctx context.Context) error {
+	for _, step := range m.steps {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("start %s: %w", step.Name, err)
+		}
+		begin := time.Now()
+		if err := step.Start(ctx); err != nil {
+			return fmt.Errorf("start %s: %w", step.Name, err)
+		}
+		m.started = append(m.started, step)
+		slog.Info("started", "step", step.Name, "elapsed", time.Since(begin))
+	}
+	return nil
+}
+
+// Stop ปิดขั้นที่เริ่มแล้วย้อนลำดับ ทุกขั้นถูกปิดแม้ขั้นก่อนหน้าจะผิดพลาด คืน error ของทุกขั้นรวมกัน
+// เวลาของทุกขั้นรวมกันไม่เกิน ctx ขั้นที่เหลือเมื่อหมดเวลาจะได้ ctx ที่หมดเวลาแล้วและควรปิดทันที
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		step := m.started[i]
+		if step.Stop == nil {
+			continue
+		}
+		begin := time.Now()
+		if err := step.Stop(ctx); err != nil {
+			slog.Error("failed to stop", "step", step.Name, "error", err)
+			errs = append(errs, fmt.Errorf("stop %s: %w", step.Name, err))
+			continue
+		}
+		slog.Info("stopped", "step", step.Name, "elapsed", time.Since(begin))
+	}
+	m.started = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Gorm คือ logger ของ GORM ที่เขียนผ่าน slog.Default
+// query ที่ผิดพลาดเป็น error, query ที่ช้ากว่า slow เป็น warning (slow เป็น 0 คือไม่บันทึก)
+// และทุก query เป็น debug เมื่อตั้ง LOG_LEVEL=debug
+func Gorm(slow time.Duration) gormlogger.Interface {
+	return &gormLogger{slow: slow, level: gormlogger.Info}
+}
+
+type gormLogger struct {
+	slow  time.Duration
+	level gormlogger.LogLevel
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, data...), "component", "gorm")
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, data...), "component", "gorm")
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, data...), "component", "gorm")
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	// ไม่พบข้อมูลและ key ซ้ำเป็นผลที่ผู้เรียกจัดการเองเสมอ ไม่ใช่ความผิดพลาดของระบบ
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, gorm.ErrDuplicatedKey):
+		sql, rows := fc()
+		slog.ErrorContext(ctx, "query failed", "component", "gorm", "error", err, "sql", sql, "rows", rows, "elapsed", elapsed)
+	case l.slow > 0 && elapsed > l.slow && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		slog.WarnContext(ctx, "slow query", "component", "gorm", "sql", sql, "rows", rows, "elapsed", elapsed, "threshold", l.slow)
+	case l.level >= gormlogger.Info && slog.Default().Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		slog.DebugContext(ctx, "query", "component", "gorm", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
+
+// ParamsFilter ให้ GORM เขียน SQL แบบมี ? แทนค่าจริง log จึงไม่มีรหัสผ่านที่ hash แล้วหรือ token ของผู้ใช้
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,68 @@
+// Package logging คือ log แบบมีโครงสร้างของ backend บน log/slog
+//
+// ทุกส่วนเขียน log ผ่าน slog ตัวเดียวกันที่ตั้งด้วย Init รวมถึง log.Println เดิมและ query ของ GORM
+// log ที่เขียนด้วย slog.*Context จะมี request_id ของ request นั้นติดไปด้วยเอง
+package logging
+
+import (
+	"backend/config"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+type requestIDKey struct{}
+
+// Init ตั้ง slog.Default ให้เขียนลง stderr ตามรูปแบบและระดับใน cfg
+func Init(cfg config.Log) {
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, cfg)))
+}
+
+// NewHandler สร้าง handler แบบ json หรือ text ที่เติม request_id จาก context ให้
+func NewHandler(w io.Writer, cfg config.Log) slog.Handler {
+	var level slog.Level
+	// ค่าผิดถูกตรวจไปแล้วใน config.Log.Validate ถ้าอ่านไม่ได้ใช้ info
+	_ = level.UnmarshalText([]byte(cfg.Level))
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "json" {
+		return contextHandler{slog.NewJSONHandler(w, opts)}
+	}
+	return contextHandler{slog.NewTextHandler(w, opts)}
+}
+
+// WithRequestID คืน context ที่มี request ID สำหรับ log ที่เขียนต่อจากนี้
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID คืน request ID ใน context ถ้าไม่มีคืนค่าว่าง
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Fatal บันทึก error แล้วจบโปรแกรม ใช้แทน log.Fatal หลัง Init
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -1,47 +1,87 @@
 package main
 
 import (
-	"backend/database"
+	"backend/cli"
+	"backend/config"
+	"backend/keys"
+	"backend/logging"
 	"backend/middleware"
-	"backend/models"
 	"backend/routes"
-	"backend/seed"
-	
+	"fmt"
+	"log/slog"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// ไม่ใส่คำสั่งคือ serve ส่วนคำสั่งอื่นอยู่ใน package cli ดูรายการด้วย backend help
 func main() {
-	app := fiber.New()
-
-	// คือการสร้าง folder สำหรับส่งข้อมูลรูปภาพ
-	app.Static("/uploads", "/app/uploads")
-
-	database.Init()
-	database.DB.AutoMigrate(
-		&models.User{},
-		&models.Article{},
-		&models.Comment{},
-		&models.Category{},
-		&models.Tags{},
-	)
-
-	routes.AuthRoutes(app)  // ลงทะเบียนและเข้าสู่ระบบ
-	routes.CategoryRoutes(app) // ดูหมวดหมู่บทความ
-	routes.ArticleRoutes(app)  // ดูบทความ
-	routes.GetTagsAll(app)  // ดูแท็ก
-	routes.CommentRoutes(app)  // ดูคอมเมนต์
-
-	
-	protected := app.Group("/", middleware.Protected())
-	routes.UserRoutes(protected)  // ดูข้อมูลผู้ใช้
-	
-	// Database Seed
-	seed.SeedCategories()
-	seed.SeedTags()  
-	seed.SeedUserAndArticles()
+	args := os.Args[1:]
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	commands := append([]cli.Command{
+		{Name: "serve", Summary: "run the HTTP server until SIGINT or SIGTERM (default)", Run: runServer},
+	}, cli.Commands...)
+	os.Exit(cli.Run(commands, args))
+}
+
+func runServer(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments, got %q", args)
+	}
+	// ค่าตั้งค่าไม่ครบหรือผิดจะหยุดตั้งแต่ตรงนี้ ก่อนเชื่อมต่อฐานข้อมูลหรือรับ request
+	cfg := config.MustLoad((*config.Config).Validate)
+	logging.Init(cfg.Log) // log แบบมีโครงสร้างตาม LOG_LEVEL และ LOG_FORMAT
+	keys.Init(cfg.JWT)    // วิธีเซ็น token และอายุของ token
+
+	// error กลับไปที่ cli.Run ซึ่งพิมพ์และจบด้วย exit code 1 แบบเดียวกับคำสั่งอื่น
+	if err := serve(cfg, newApp()); err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
 
+// newApp สร้าง Fiber app พร้อม middleware และ route ทั้งหมด ยังไม่เชื่อมต่อฐานข้อมูลหรือรับ request
+func newApp() *fiber.App {
+	app := fiber.New(fiber.Config{
+		// อ่าน IP จริงของผู้ใช้จาก nginx แต่เชื่อ header นี้เฉพาะเมื่อมาจาก proxy ในเครือข่ายภายใน
+		ProxyHeader:             "X-Real-IP",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"127.0.0.1", "::1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
+		// เท่ากับ client_max_body_size ของ nginx ค่าเริ่มต้น 4MB ของ Fiber เล็กกว่าไฟล์ที่ nginx ยอมให้ผ่าน
+		BodyLimit: 10 * 1024 * 1024,
+		// serve บันทึก address ลง log เองเมื่อพร้อม
+		DisableStartupMessage: true,
+	})
 
-	app.Listen(":8080")
+	// ทุก request มี X-Request-ID ถูกนับใน /metrics และถูกบันทึกใน access log
+	app.Use(middleware.RequestID(), middleware.Metrics(), middleware.AccessLog())
+
+	routes.HealthRoutes(app) // /healthz และ /readyz
+
+	routes.AuthRoutes(app)      // ลงทะเบียนและเข้าสู่ระบบ
+	routes.CategoryRoutes(app)  // ดูหมวดหมู่บทความ
+	routes.ArticleRoutes(app)   // ดูบทความ
+	routes.GetTagsAll(app)      // ดูแท็ก
+	routes.CommentRoutes(app)   // ดูคอมเมนต์
+	routes.AdminRoutes(app)     // เมนูสำหรับแอดมิน
+	routes.WellKnownRoutes(app) // JWKS สำหรับระบบอื่นตรวจ token
+	routes.UploadRoutes(app)    // ไฟล์ที่ผู้ใช้อัปโหลด
+	routes.MediaRoutes(app)     // คลังรูปสำหรับบทความ
+	routes.FeedRoutes(app)      // RSS, Atom และ JSON Feed
+	routes.SitemapRoutes(app)   // sitemap.xml และ robots.txt
+
+	protected := app.Group("/", middleware.Protected())
+	routes.UserRoutes(protected) // ดูข้อมูลผู้ใช้
+	return app
+}
 
+// newMetricsApp สร้าง app ของ /metrics ที่รับ request บน METRICS_PORT แยกจาก API
+// nginx ส่งต่อเฉพาะ port ของ API จึงไม่มี path ไหนจากภายนอกที่ไปถึง /metrics ได้
+func newMetricsApp() *fiber.App {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.MetricsRoutes(app)
+	return app
 }
@@ -0,0 +1,90 @@
+// Package metrics คือตัวนับและ histogram ของ backend ในรูปแบบ Prometheus ที่เปิดให้อ่านที่ /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ผลของการเข้าสู่ระบบที่ใช้เป็น label result ของ LoginAttempts
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+	// ถูกปฏิเสธก่อนตรวจรหัสเพราะผิดบ่อยเกินไป
+	LoginBlocked = "blocked"
+)
+
+// ที่มาของบทความที่ใช้เป็น label source ของ ArticlesCreated
+const (
+	SourceEditor = "editor"
+	SourceImport = "import"
+)
+
+// registry ของ backend เอง ไม่ใช้ DefaultRegisterer เพื่อให้มีแค่ metric ที่ลงทะเบียนไว้ที่นี่
+var registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests นับ request ตาม method, route (รูปแบบของ route เช่น /articles/:slug) และ status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration คือเวลาที่ใช้ตอบแต่ละ request เป็นวินาที
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method and route.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route"})
+
+	// LoginAttempts นับการเข้าสู่ระบบทั้งด้วยรหัสผ่านและ OIDC ตามผลลัพธ์
+	LoginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_login_attempts_total",
+		Help: "Login attempts by result (success, failure or blocked).",
+	}, []string{"result"})
+
+	// ArticlesCreated นับบทความใหม่ แยกที่เขียนในเว็บกับที่นำเข้า
+	ArticlesCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "articles_created_total",
+		Help: "Articles created by source (editor or import).",
+	}, []string{"source"})
+
+	// CommentsCreated นับคอมเมนต์ใหม่
+	CommentsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "comments_created_total",
+		Help: "Comments created.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		LoginAttempts,
+		ArticlesCreated,
+		CommentsCreated,
+	)
+	// ให้ label ของผลลัพธ์ที่รู้จักมีค่า 0 ตั้งแต่เริ่ม rate() และ alert จึงไม่ขาดช่วงก่อนเกิดครั้งแรก
+	for _, result := range []string{LoginSuccess, LoginFailure, LoginBlocked} {
+		LoginAttempts.WithLabelValues(result)
+	}
+	for _, source := range []string{SourceEditor, SourceImport} {
+		ArticlesCreated.WithLabelValues(source)
+	}
+}
+
+// RegisterDB เพิ่มสถิติของ connection pool (go_sql_*) ของฐานข้อมูล เรียกครั้งเดียวหลังเชื่อมต่อ
+func RegisterDB(db *sql.DB, name string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler คือ http.Handler ที่ตอบ metric ทั้งหมดในรูปแบบ text ของ Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"backend/database"
+	"backend/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminOnly ต้องใช้ต่อจาก Protected() เสมอ ตรวจ role จากฐานข้อมูลแทนการเชื่อ claim ใน token
+func AdminOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(uint)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+
+		var user models.User
+		if err := database.DB.Select("id", "role").First(&user, userID).Error; err != nil || user.Role != models.RoleAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Forbidden"})
+		}
+		return c.Next()
+	}
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"backend/composables"
+	"backend/keys"
 	"fmt"
-	"os"
+	"strings"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -10,8 +12,8 @@ import (
 )
 
 func Protected() fiber.Handler {
-	return jwtware.New(jwtware.Config{
-		SigningKey:   jwtware.SigningKey{Key: []byte(os.Getenv("JWT_SECRET"))},
+	jwtHandler := jwtware.New(jwtware.Config{
+		KeyFunc:      keys.Keyfunc, // เลือก key ตาม kid รองรับทั้ง HS256 เดิมและ RS256/EdDSA
 		ContextKey:   "user", // เก็บ token ไว้ใน c.Locals("user")
 		ErrorHandler: jwtError,
 		SuccessHandler: func(c *fiber.Ctx) error {
@@ -26,9 +28,39 @@ func Protected() fiber.Handler {
 				c.Locals("userID", uint(idFloat)) //
 			}
 
+			// token ต้องผูกกับ session ที่ยังไม่ถูกยกเลิก (ผู้ใช้ออกจากระบบจากเครื่องอื่นได้)
+			sid, _ := claims["sid"].(string)
+			session, err := composables.TouchSession(sid)
+			if err != nil {
+				return jwtError(c, err)
+			}
+			if userID, _ := c.Locals("userID").(uint); session.UserID != userID {
+				return jwtError(c, fmt.Errorf("session does not belong to user"))
+			}
+			c.Locals("sessionID", session.ID)
+
 			return c.Next()
 		},
 	})
+
+	return func(c *fiber.Ctx) error {
+		raw := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if strings.HasPrefix(raw, composables.PersonalAccessTokenPrefix) {
+			return personalAccessToken(c, raw)
+		}
+		return jwtHandler(c)
+	}
+}
+
+// ตรวจ personal access token แล้วเก็บ scope ไว้ใน c.Locals("tokenScopes")
+func personalAccessToken(c *fiber.Ctx, raw string) error {
+	token, err := composables.FindPersonalAccessToken(raw)
+	if err != nil {
+		return jwtError(c, err)
+	}
+	c.Locals("userID", token.UserID)
+	c.Locals("tokenScopes", token.Scopes)
+	return c.Next()
 }
 
 func jwtError(c *fiber.Ctx, err error) error {
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"backend/logging"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID ใช้ X-Request-ID ที่ส่งมา (เช่นจาก proxy) หรือสร้างใหม่ถ้าไม่มีหรือไม่ถูกรูปแบบ
+// แล้วส่งกลับใน response และเก็บไว้ใน c.UserContext() ให้ log ที่เขียนระหว่าง request นี้มี request_id เดียวกัน
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+		c.Locals("requestID", id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// AccessLog บันทึกทุก request หลังตอบแล้ว พร้อม status เวลาที่ใช้ และผู้ใช้ที่ login อยู่
+// ต้องใช้ต่อจาก RequestID error ที่ handler คืนมาถูกส่งให้ ErrorHandler ของ app ตรงนี้ เพื่อให้ได้ status จริง
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		// c.IP() ว่างเมื่อมาจาก proxy ที่เชื่อถือแต่ไม่ได้ส่ง X-Real-IP มา
+		ip := c.IP()
+		if ip == "" {
+			ip = c.Context().RemoteIP().String()
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("ip", ip),
+		}
+		// Body() ของ response แบบ stream จะอ่านทั้ง stream เข้าหน่วยความจำ จึงไม่บันทึกขนาด
+		if !c.Response().IsBodyStream() {
+			attrs = append(attrs, slog.Int("bytes", len(c.Response().Body())))
+		}
+		if userID, ok := c.Locals("userID").(uint); ok {
+			attrs = append(attrs, slog.Uint64("user_id", uint64(userID)))
+		}
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(c.UserContext(), level, "request", attrs...)
+		return nil
+	}
+}
+
+// ค่าจาก client ใช้ได้เมื่อสั้นและมีแค่ตัวอักษรที่ปลอดภัย เพื่อไม่ให้แทรกบรรทัดปลอมลงใน log ได้
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"backend/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Metrics นับ request และเวลาที่ใช้ตาม route ใช้ต่อจาก RequestID แต่ก่อน AccessLog
+// เพื่อให้ error ของ handler ถูก ErrorHandler แปลงเป็น status แล้วตอนที่นับ
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// ใช้รูปแบบของ route แทน path จริง ไม่งั้นทุก slug จะกลายเป็น series ใหม่
+		// request ที่ไม่ถึง handler ใด (404 หรือถูก middleware ของกลุ่มปฏิเสธ) ได้ prefix ของ middleware ตัวสุดท้าย เช่น /
+		route := c.Route().Path
+		// ค่าจาก Fiber ชี้ไปที่ buffer ที่ถูกใช้ซ้ำใน request ถัดไป label ที่ถูกเก็บไว้ต้องเป็นสำเนา
+		method := utils.CopyString(c.Method())
+		status := strconv.Itoa(c.Response().StatusCode())
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
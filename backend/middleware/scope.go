@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireScope ใช้ต่อจาก Protected() ผู้ใช้ที่ login ด้วย JWT มีสิทธิ์ทุก scope
+// ส่วน personal access token ต้องมี scope ที่ระบุ
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, ok := c.Locals("tokenScopes").([]string)
+		if !ok {
+			return c.Next()
+		}
+		for _, s := range scopes {
+			if s == scope {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Insufficient token scope"})
+	}
+}
+
+// SessionOnly ปฏิเสธ personal access token ใช้กับเมนูที่ต้อง login ด้วยรหัสผ่านเท่านั้น เช่นการจัดการ token
+func SessionOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("tokenScopes").([]string); ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Personal access tokens cannot be used here"})
+		}
+		return c.Next()
+	}
+}
@@ -13,6 +13,17 @@ type Article struct {
 	Category   Category  `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"category"`
 	Tags       []Tags    `gorm:"many2many:article_tags;" json:"tags"`
 	Comments   []Comment `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"comments"`
+	Media      []Media   `gorm:"many2many:article_media;" json:"media,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+	// เพิ่มขึ้นทุกครั้งที่แก้ไข ผู้แก้ไขต้องส่ง version ที่อ่านไปกลับมา ถ้าไม่ตรงแปลว่ามีคนแก้ไปก่อนแล้ว
+	Version uint `gorm:"not null;default:1" json:"version"`
+
+	// ข้อมูลสำหรับ SEO และการแชร์ลิงก์ ถ้าว่างจะใช้ค่าจาก Title และ Content แทน
+	CoverMediaID   *uint  `json:"cover_media_id"`
+	Cover          *Media `gorm:"foreignKey:CoverMediaID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"cover,omitempty"`
+	Excerpt        string `gorm:"type:varchar(500)" json:"excerpt"`
+	SEOTitle       string `gorm:"column:seo_title;type:varchar(255)" json:"seo_title"`
+	SEODescription string `gorm:"column:seo_description;type:varchar(500)" json:"seo_description"`
+	CanonicalURL   string `gorm:"type:varchar(500)" json:"canonical_url"`
 }
@@ -11,4 +11,6 @@ type Comment struct {
 	User      User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// เพิ่มขึ้นทุกครั้งที่แก้ไข ใช้แบบเดียวกับ Article.Version
+	Version uint `gorm:"not null;default:1" json:"version"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LoginAttempt เก็บประวัติการเข้าสู่ระบบทุกครั้ง ใช้ทั้งคำนวณการล็อกบัญชีและ audit log ของแอดมิน
+type LoginAttempt struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Identifier string    `gorm:"type:varchar(255);not null;index" json:"identifier"`
+	UserID     *uint     `gorm:"index" json:"user_id,omitempty"`
+	IP         string    `gorm:"type:varchar(64);not null;index" json:"ip"`
+	UserAgent  string    `gorm:"type:varchar(255)" json:"user_agent"`
+	Success    bool      `gorm:"not null;default:false" json:"success"`
+	Blocked    bool      `gorm:"not null;default:false" json:"blocked"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
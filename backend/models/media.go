@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// MediaVariant คือรูปหนึ่งขนาดของ Media ใช้ทำ srcset
+type MediaVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
+
+// Media คือรูปที่ผู้ใช้อัปโหลดไว้ใช้ในบทความ ไฟล์ทุกขนาดอยู่ใน storage ภายใต้ "<StorageKey>_<width>.<ext>"
+type Media struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	UserID      uint           `gorm:"not null;index" json:"-"`
+	User        User           `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	StorageKey  string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	Filename    string         `gorm:"type:varchar(255)" json:"filename"`
+	ContentType string         `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size        int64          `json:"size"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	URL         string         `gorm:"type:varchar(255);not null" json:"url"`
+	Variants    []MediaVariant `gorm:"serializer:json" json:"variants"`
+	AltText     string         `gorm:"type:varchar(500)" json:"alt_text"`
+	Caption     string         `gorm:"type:text" json:"caption"`
+	Articles    []Article      `gorm:"many2many:article_media;" json:"-"`
+	// เวลาที่ไม่มีบทความใดใช้รูปนี้แล้ว (nil ถ้ายังไม่เคยถูกใช้ หรือยังถูกใช้อยู่) ใช้ตัดสินว่ารูปกำพร้าหรือยัง
+	DetachedAt *time.Time `gorm:"index" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
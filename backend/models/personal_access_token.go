@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// scope ที่กำหนดให้ personal access token ได้
+const (
+	ScopeArticlesRead  = "articles:read"
+	ScopeArticlesWrite = "articles:write"
+	ScopeCommentsRead  = "comments:read"
+	ScopeCommentsWrite = "comments:write"
+	ScopeProfileRead   = "profile:read"
+	ScopeProfileWrite  = "profile:write"
+)
+
+var TokenScopes = []string{
+	ScopeArticlesRead,
+	ScopeArticlesWrite,
+	ScopeCommentsRead,
+	ScopeCommentsWrite,
+	ScopeProfileRead,
+	ScopeProfileWrite,
+}
+
+// PersonalAccessToken คือ token สำหรับสคริปต์และระบบอื่น เก็บเฉพาะ hash ของ token ไม่เก็บตัวจริง
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	User       User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	TokenHash  string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"`
+	Scopes     []string   `gorm:"type:text;serializer:json" json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SigningKey คือกุญแจสำหรับเซ็น JWT แบบ asymmetric (RS256 / EdDSA)
+// key ที่ถูก retire แล้วจะไม่ใช้เซ็นอีก แต่ยังใช้ตรวจ token เก่าได้จนถึง ExpiresAt
+type SigningKey struct {
+	ID         uint       `gorm:"primaryKey" json:"-"`
+	Kid        string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"kid"`
+	Algorithm  string     `gorm:"type:varchar(16);not null" json:"alg"`
+	PrivateKey string     `gorm:"type:text;not null" json:"-"` // PKCS#8 PEM
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
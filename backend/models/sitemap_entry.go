@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	SitemapKindPage     = "page"
+	SitemapKindArticle  = "article"
+	SitemapKindCategory = "category"
+	SitemapKindTag      = "tag"
+	SitemapKindAuthor   = "author"
+)
+
+// SitemapEntry คือ URL หนึ่งรายการใน sitemap.xml ถูกอัปเดตทีละรายการเมื่อบทความเปลี่ยน
+// Identifier คือ path ของหน้า (page), slug (article), id (category), ชื่อแท็ก (tag) หรือ username (author)
+type SitemapEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	Kind       string    `gorm:"type:varchar(16);not null;uniqueIndex:idx_sitemap_entry" json:"kind"`
+	Identifier string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_sitemap_entry" json:"identifier"`
+	LastMod    time.Time `gorm:"not null" json:"lastmod"`
+}
@@ -1,6 +1,39 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// วิธีจัดการเนื้อหาของบัญชีที่ขอลบ
+const (
+	// โอนบทความและคอมเมนต์ไปให้บัญชี "deleted user"
+	DeletionModeAnonymize = "anonymize"
+	// ลบบทความและคอมเมนต์ทั้งหมดไปพร้อมบัญชี
+	DeletionModeRemove = "remove"
+)
+
+// บัญชีกลางที่รับบทความและคอมเมนต์ของผู้ใช้ที่ลบบัญชีแบบ anonymize
+// ระบบหาบัญชีนี้จากอีเมล ทั้งอีเมลและ username นี้จึงสงวนไว้ ไม่ให้ใครสมัครหรือ login ผ่าน OIDC มาใช้
+const (
+	DeletedUserUsername = "deleted-user"
+	DeletedUserEmail    = "deleted-user@invalid.local"
+)
+
+// ReservedUsername บอกว่า username นี้สมัครใช้ไม่ได้
+func ReservedUsername(username string) bool {
+	return strings.EqualFold(strings.TrimSpace(username), DeletedUserUsername)
+}
+
+// ReservedEmail บอกว่าอีเมลนี้ใช้กับบัญชีของผู้ใช้ไม่ได้
+func ReservedEmail(email string) bool {
+	return strings.EqualFold(strings.TrimSpace(email), DeletedUserEmail)
+}
 
 type User struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
@@ -10,10 +43,15 @@ type User struct {
 	Nickname     string    `gorm:"not null" json:"nickname"`
 	Email        string    `gorm:"unique" json:"email"`
 	PasswordHash string    `gorm:"not null" json:"password"`
+	Role         string    `gorm:"type:varchar(20);not null;default:user" json:"role"`
 	Bio          *string   `json:"bio,omitempty"`
 	Image        *string   `json:"image,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Articles     []Article `gorm:"foreignKey:AuthorID" json:"-"`
 	Comments     []Comment `gorm:"foreignKey:UserID" json:"-"`
+
+	// ตั้งค่าเมื่อผู้ใช้ขอลบบัญชี ระบบจะลบจริงหลัง DeletionScheduledAt
+	DeletionMode        *string    `gorm:"type:varchar(20)" json:"deletion_mode,omitempty"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at,omitempty"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserIdentity ผูกบัญชีผู้ใช้กับบัญชีของผู้ให้บริการ OIDC (เช่น identity provider ของบริษัท)
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"-"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Provider  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_provider_subject" json:"-"`
+	Email     string    `gorm:"type:varchar(255)" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OIDCLoginState เก็บ state, PKCE verifier และ nonce ระหว่างที่ผู้ใช้ไป login กับผู้ให้บริการ
+type OIDCLoginState struct {
+	State        string `gorm:"type:varchar(64);primaryKey"`
+	Provider     string `gorm:"type:varchar(50);not null"`
+	CodeVerifier string `gorm:"type:varchar(128);not null"`
+	Nonce        string `gorm:"type:varchar(64);not null"`
+	CreatedAt    time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
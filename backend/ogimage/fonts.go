@@ -0,0 +1,75 @@
+package ogimage
+
+import (
+	"backend/config"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-text/typesetting/font"
+)
+
+// ฟอนต์เริ่มต้นจากแพ็กเกจ font-noto และ font-noto-thai ของ Alpine (ดู Dockerfile)
+// ฟอนต์แรกที่มีตัวอักษรนั้นจะถูกใช้ ฟอนต์ไทยจึงต้องอยู่ก่อน
+var defaultFonts = []string{
+	"/usr/share/fonts/noto/NotoSansThai-Bold.ttf",
+	"/usr/share/fonts/noto/NotoSans-Bold.ttf",
+	"/usr/share/fonts/noto/NotoSansThai-Regular.ttf",
+	"/usr/share/fonts/noto/NotoSans-Regular.ttf",
+}
+
+var ErrNoFont = errors.New("no usable font found, set OG_FONTS to TTF/OTF files that cover Thai and Latin")
+
+var (
+	fontsOnce sync.Once
+	fonts     fontmap
+	fontsErr  error
+)
+
+// fontmap เลือกฟอนต์แรกในรายการที่มี glyph ของตัวอักษรนั้น (font fallback)
+type fontmap []*font.Face
+
+func (m fontmap) ResolveFace(r rune) *font.Face {
+	for _, face := range m {
+		if _, ok := face.NominalGlyph(r); ok {
+			return face
+		}
+	}
+	return m[0]
+}
+
+// โหลดฟอนต์จาก OG_FONTS (คั่นด้วย comma) หรือ defaultFonts ครั้งแรกที่ใช้
+func loadFonts() (fontmap, error) {
+	fontsOnce.Do(func() {
+		paths := config.Get().OGFonts
+		if len(paths) == 0 {
+			paths = defaultFonts
+		}
+		for _, path := range paths {
+			face, err := loadFace(strings.TrimSpace(path))
+			if err != nil {
+				continue
+			}
+			fonts = append(fonts, face)
+		}
+		if len(fonts) == 0 {
+			fontsErr = ErrNoFont
+		}
+	})
+	return fonts, fontsErr
+}
+
+func loadFace(path string) (*font.Face, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	face, err := font.ParseTTF(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return face, nil
+}
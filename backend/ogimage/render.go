@@ -0,0 +1,267 @@
+// Package ogimage วาดรูปสำหรับแชร์บทความ (Open Graph image) ขนาด 1200x630
+// ข้อความถูก shape ด้วย HarfBuzz จึงวางสระและวรรณยุกต์ภาษาไทยได้ถูกตำแหน่ง
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/shaping"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+const (
+	Width  = 1200
+	Height = 630
+
+	// AvatarSize คือขนาดรูปผู้เขียนบนการ์ด (px)
+	AvatarSize = 72
+
+	padding     = 80
+	titleSize   = 64
+	titleLines  = 3
+	labelSize   = 28
+	accentWidth = 16
+)
+
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorAccent     = color.RGBA{0x25, 0x63, 0xeb, 0xff} // blue-600 เหมือนปุ่มใน frontend
+	colorTitle      = color.RGBA{0x11, 0x18, 0x27, 0xff}
+	colorText       = color.RGBA{0x37, 0x41, 0x51, 0xff}
+	colorPill       = color.RGBA{0xdb, 0xea, 0xfe, 0xff}
+	colorPillText   = color.RGBA{0x1d, 0x4e, 0xd8, 0xff}
+	colorAvatar     = color.RGBA{0xe5, 0xe7, 0xeb, 0xff}
+)
+
+// Card คือข้อมูลที่แสดงบนรูป Avatar เป็น nil ได้
+type Card struct {
+	Title    string
+	Author   string
+	Category string
+	SiteName string
+	Avatar   image.Image
+}
+
+// Render วาดการ์ดของบทความและคืนเป็น PNG
+func Render(card Card) ([]byte, error) {
+	fonts, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+	fillRect(dst, image.Rect(0, 0, accentWidth, Height), colorAccent)
+
+	left := padding + accentWidth
+	textWidth := Width - left - padding
+	y := padding
+
+	// หมวดหมู่เป็นป้ายด้านบน
+	if card.Category != "" {
+		line := layout(fonts, card.Category, labelSize, textWidth-48, 1)
+		if len(line) > 0 {
+			pillHeight := labelSize * 2
+			pill := image.Rect(left, y, left+lineWidth(line[0])+48, y+pillHeight)
+			fillRoundRect(dst, pill, pillHeight/2, colorPill)
+			drawLine(dst, line[0], left+24, y+baselineOffset(line[0], pillHeight), colorPillText)
+			y += pillHeight + 40
+		}
+	}
+
+	// ชื่อบทความ ยาวเกินจะตัดด้วย …
+	for _, line := range layout(fonts, card.Title, titleSize, textWidth, titleLines) {
+		lineHeight := titleSize * 5 / 4
+		drawLine(dst, line, left, y+baselineOffset(line, lineHeight), colorTitle)
+		y += lineHeight
+	}
+
+	// แถบล่าง: รูปและชื่อผู้เขียนทางซ้าย ชื่อเว็บทางขวา
+	footerTop := Height - padding - AvatarSize
+	nameLeft := left
+	if card.Author != "" {
+		drawAvatar(dst, card.Avatar, image.Rect(left, footerTop, left+AvatarSize, footerTop+AvatarSize))
+		nameLeft += AvatarSize + 24
+	}
+	if card.SiteName != "" {
+		if line := layout(fonts, card.SiteName, labelSize, textWidth/2, 1); len(line) > 0 {
+			x := Width - padding - lineWidth(line[0])
+			drawLine(dst, line[0], x, footerTop+baselineOffset(line[0], AvatarSize), colorAccent)
+		}
+	}
+	if card.Author != "" {
+		if line := layout(fonts, card.Author, labelSize, textWidth/2-AvatarSize, 1); len(line) > 0 {
+			drawLine(dst, line[0], nameLeft, footerTop+baselineOffset(line[0], AvatarSize), colorText)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// layout แบ่งข้อความเป็น run ตามฟอนต์และสคริปต์ shape แล้วตัดบรรทัดตามความกว้าง
+// ภาษาไทยไม่มีพจนานุกรมตัดคำ จะตัดที่ช่องว่างก่อน และตัดกลางคำเฉพาะเมื่อคำยาวเกินบรรทัด
+func layout(fonts fontmap, text string, size, maxWidth, maxLines int) []shaping.Line {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	input := shaping.Input{
+		Text:      runes,
+		RunStart:  0,
+		RunEnd:    len(runes),
+		Direction: di.DirectionLTR,
+		Face:      fonts[0],
+		Size:      fixed.I(size),
+		Language:  language.NewLanguage("th"),
+	}
+
+	var (
+		segmenter shaping.Segmenter
+		shaper    shaping.HarfbuzzShaper
+		wrapper   shaping.LineWrapper
+	)
+	inputs := segmenter.Split(input, fonts)
+	outputs := make([]shaping.Output, len(inputs))
+	for i, in := range inputs {
+		outputs[i] = shaper.Shape(in)
+	}
+
+	ellipsis := []rune("…")
+	truncator := shaper.Shape(shaping.Input{
+		Text:      ellipsis,
+		RunEnd:    len(ellipsis),
+		Direction: di.DirectionLTR,
+		Face:      fonts.ResolveFace(ellipsis[0]),
+		Size:      fixed.I(size),
+	})
+	lines, _ := wrapper.WrapParagraph(shaping.WrapConfig{
+		Direction:          di.DirectionLTR,
+		TruncateAfterLines: maxLines,
+		Truncator:          truncator,
+		BreakPolicy:        shaping.WhenNecessary,
+	}, maxWidth, runes, shaping.NewSliceIterator(outputs))
+	return lines
+}
+
+func lineWidth(line shaping.Line) int {
+	var width fixed.Int26_6
+	for _, run := range line {
+		width += run.Advance
+	}
+	return width.Ceil()
+}
+
+// ระยะจากขอบบนของกล่องสูง height ถึง baseline ที่ทำให้ข้อความอยู่กลางกล่อง
+func baselineOffset(line shaping.Line, height int) int {
+	var ascent, descent fixed.Int26_6
+	for _, run := range line {
+		ascent = max(ascent, run.LineBounds.Ascent)
+		descent = max(descent, -run.LineBounds.Descent)
+	}
+	return (height + ascent.Round() - descent.Round()) / 2
+}
+
+// drawLine วาด glyph ทุกตัวในบรรทัดโดยเริ่มที่ x และ baseline y
+func drawLine(dst draw.Image, line shaping.Line, x, y int, col color.Color) {
+	raster := vector.NewRasterizer(Width, Height)
+	dot := fixed.I(x)
+	for _, run := range line {
+		scale := float32(run.Size) / 64 / float32(run.Face.Upem())
+		for _, g := range run.Glyphs {
+			outline, ok := run.Face.GlyphData(g.GlyphID).(font.GlyphOutline)
+			if ok {
+				ox := float32(dot+g.XOffset) / 64
+				oy := float32(y) - float32(g.YOffset)/64
+				addOutline(raster, outline, ox, oy, scale)
+			}
+			dot += g.Advance
+		}
+	}
+	raster.Draw(dst, dst.Bounds(), image.NewUniform(col), image.Point{})
+}
+
+// แปลง outline ของฟอนต์ (หน่วย font unit แกน y ชี้ขึ้น) เป็น path บนรูป
+func addOutline(raster *vector.Rasterizer, outline font.GlyphOutline, ox, oy, scale float32) {
+	pt := func(p ot.SegmentPoint) (float32, float32) {
+		return ox + p.X*scale, oy - p.Y*scale
+	}
+	for _, seg := range outline.Segments {
+		switch seg.Op {
+		case ot.SegmentOpMoveTo:
+			raster.ClosePath()
+			raster.MoveTo(pt(seg.Args[0]))
+		case ot.SegmentOpLineTo:
+			raster.LineTo(pt(seg.Args[0]))
+		case ot.SegmentOpQuadTo:
+			x1, y1 := pt(seg.Args[0])
+			x2, y2 := pt(seg.Args[1])
+			raster.QuadTo(x1, y1, x2, y2)
+		case ot.SegmentOpCubeTo:
+			x1, y1 := pt(seg.Args[0])
+			x2, y2 := pt(seg.Args[1])
+			x3, y3 := pt(seg.Args[2])
+			raster.CubeTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	raster.ClosePath()
+}
+
+// drawAvatar วาดรูปผู้เขียนเป็นวงกลม ถ้าไม่มีรูปจะเป็นวงกลมสีเทา
+func drawAvatar(dst draw.Image, avatar image.Image, rect image.Rectangle) {
+	src := image.Image(image.NewUniform(colorAvatar))
+	if avatar != nil {
+		scaled := image.NewRGBA(rect)
+		xdraw.CatmullRom.Scale(scaled, rect, avatar, avatar.Bounds(), xdraw.Src, nil)
+		src = scaled
+	}
+	mask := circleMask(rect)
+	draw.DrawMask(dst, rect, src, rect.Min, mask, image.Point{}, draw.Over)
+}
+
+func circleMask(rect image.Rectangle) *image.Alpha {
+	w, h := float32(rect.Dx()), float32(rect.Dy())
+	raster := vector.NewRasterizer(rect.Dx(), rect.Dy())
+	addRoundRect(raster, 0, 0, w, h, w/2)
+	mask := image.NewAlpha(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	raster.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask
+}
+
+func fillRect(dst draw.Image, rect image.Rectangle, col color.Color) {
+	draw.Draw(dst, rect, image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+func fillRoundRect(dst draw.Image, rect image.Rectangle, radius int, col color.Color) {
+	raster := vector.NewRasterizer(Width, Height)
+	addRoundRect(raster, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Max.X), float32(rect.Max.Y), float32(radius))
+	raster.Draw(dst, dst.Bounds(), image.NewUniform(col), image.Point{})
+}
+
+// สี่เหลี่ยมมุมมน มุมเป็นเส้นโค้ง cubic ที่ใกล้ส่วนโค้งของวงกลม ถ้า r เท่าครึ่งหนึ่งของด้านจะได้วงกลม
+func addRoundRect(raster *vector.Rasterizer, x0, y0, x1, y1, r float32) {
+	k := r * 0.5523
+	raster.MoveTo(x0+r, y0)
+	raster.LineTo(x1-r, y0)
+	raster.CubeTo(x1-r+k, y0, x1, y0+r-k, x1, y0+r)
+	raster.LineTo(x1, y1-r)
+	raster.CubeTo(x1, y1-r+k, x1-r+k, y1, x1-r, y1)
+	raster.LineTo(x0+r, y1)
+	raster.CubeTo(x0+r-k, y1, x0, y1-r+k, x0, y1-r)
+	raster.LineTo(x0, y0+r)
+	raster.CubeTo(x0, y0+r-k, x0+r-k, y0, x0+r, y0)
+	raster.ClosePath()
+}
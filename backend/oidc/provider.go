@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"backend/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Provider คือผู้ให้บริการ OIDC หนึ่งราย ตั้งค่าจาก environment ด้วย prefix OIDC_<NAME>_
+type Provider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	mu        sync.Mutex
+	discovery *Discovery
+	jwks      *keyfunc.JWKS
+}
+
+// Discovery คือค่าจาก /.well-known/openid-configuration ที่เราใช้
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims คือข้อมูลผู้ใช้จาก ID token
+type Claims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+	Nickname          string `json:"nickname"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+var (
+	providersOnce sync.Once
+	providers     map[string]*Provider
+)
+
+// Providers คืนผู้ให้บริการที่ตั้งไว้ใน config.OIDC.Providers
+// (OIDC_PROVIDERS และ OIDC_<NAME>_* ใน environment หรือ oidc.providers ในไฟล์ YAML)
+func Providers() map[string]*Provider {
+	providersOnce.Do(func() {
+		providers = map[string]*Provider{}
+		for name, p := range config.Get().OIDC.Providers {
+			providers[name] = &Provider{
+				Name:         name,
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: p.ClientSecret,
+				RedirectURL:  p.RedirectURL,
+				Scopes:       p.Scopes,
+			}
+		}
+	})
+	return providers
+}
+
+// ProviderNames คืนชื่อผู้ให้บริการทั้งหมด เรียงตามตัวอักษร
+func ProviderNames() []string {
+	names := make([]string, 0, len(Providers()))
+	for name := range Providers() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup คืนผู้ให้บริการตามชื่อ
+func Lookup(name string) (*Provider, bool) {
+	p, ok := Providers()[strings.ToLower(name)]
+	return p, ok
+}
+
+// Discover โหลด openid-configuration ของผู้ให้บริการครั้งแรกที่ใช้ แล้วเก็บไว้
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery returned %s", res.Status)
+	}
+
+	var d Discovery
+	if err := json.NewDecoder(res.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("issuer mismatch: got %q", d.Issuer)
+	}
+
+	jwks, err := keyfunc.Get(d.JWKSURI, keyfunc.Options{
+		Client:            httpClient,
+		RefreshInterval:   time.Hour,
+		RefreshRateLimit:  time.Minute,
+		RefreshUnknownKID: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.discovery, p.jwks = &d, jwks
+	return p.discovery, nil
+}
+
+// AuthCodeURL สร้าง URL สำหรับพาผู้ใช้ไป login แบบ authorization code + PKCE (S256)
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", p.RedirectURL)
+	q.Set("scope", strings.Join(p.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange แลก authorization code เป็น token แล้วตรวจ ID token
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"client_id":     {p.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %s: %s %s", res.Status, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	return p.VerifyIDToken(body.IDToken, nonce)
+}
+
+// VerifyIDToken ตรวจลายเซ็น, issuer, audience, เวลาหมดอายุ และ nonce ของ ID token
+func (p *Provider) VerifyIDToken(raw, nonce string) (*Claims, error) {
+	if p.jwks == nil {
+		return nil, errors.New("provider not discovered")
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(raw, &claims, p.jwks.Keyfunc,
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "PS256", "EdDSA"}),
+		jwt.WithIssuer(p.discovery.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+	return &claims, nil
+}
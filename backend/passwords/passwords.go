@@ -0,0 +1,18 @@
+package passwords
+
+import "golang.org/x/crypto/bcrypt"
+
+// hash ของรหัสผ่านสุ่มที่ไม่มีใครรู้ ใช้เทียบเมื่อไม่พบบัญชี เพื่อไม่ให้เวลาตอบกลับบอกได้ว่ามีบัญชีอยู่หรือไม่
+var DummyHash = Hash("boblog-dummy-password-for-timing")
+
+// hash password
+func Hash(pw string) string {
+	hash, _ := bcrypt.GenerateFromPassword([]byte(pw), 12)
+	return string(hash)
+}
+
+// check password
+func Check(pw string, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pw))
+	return err == nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ArticleFilter คือเงื่อนไขของรายการบทความ ค่าว่างคือไม่กรองด้วยเงื่อนไขนั้น
+// Author คือ username ของผู้เขียน
+type ArticleFilter struct {
+	Search     string
+	CategoryID string
+	Tag        string
+	Author     string
+}
+
+// ArticleRepository อ่านเขียนบทความ
+type ArticleRepository interface {
+	// All คืนทุกบทความพร้อมผู้เขียน หมวดหมู่ แท็ก และคอมเมนต์
+	All(ctx context.Context) ([]models.Article, error)
+	// List คืนบทความตาม filter เรียงจากใหม่ไปเก่า
+	List(ctx context.Context, filter ArticleFilter) ([]models.Article, error)
+	ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error)
+	// FindBySlug คืนบทความพร้อมแท็ก พอสำหรับแก้ไขหรือลบ
+	FindBySlug(ctx context.Context, slug string) (*models.Article, error)
+	// FindDetailBySlug คืนบทความพร้อมข้อมูลทั้งหมดที่หน้าบทความใช้
+	FindDetailBySlug(ctx context.Context, slug string) (*models.Article, error)
+	TitleOrSlugTaken(ctx context.Context, title, slug string) (bool, error)
+	// FindCover คืนรูปในคลังของผู้ใช้ที่จะใช้เป็นภาพปก คืน ErrCoverNotOwned ถ้าไม่มีรูปนี้ในคลังของผู้ใช้
+	FindCover(ctx context.Context, userID, mediaID uint) (*models.Media, error)
+	// Create บันทึกบทความใหม่ คืน ErrDuplicate ถ้าชื่อเรื่องหรือ slug ซ้ำกับบทความอื่น
+	Create(ctx context.Context, article *models.Article) error
+	// Update บันทึกบทความ ถ้า replaceTags แท็กของบทความจะถูกแทนด้วย article.Tags
+	// article.Version คือ version ที่ผู้แก้ไขอ่านไป บันทึกได้เมื่อยังตรงกับในฐานข้อมูล แล้ว Version จะเพิ่มขึ้นหนึ่ง
+	// คืน ErrConflict ถ้ามีคนแก้ไปก่อน และ ErrDuplicate ถ้าชื่อเรื่องหรือ slug ซ้ำกับบทความอื่น
+	Update(ctx context.Context, article *models.Article, replaceTags bool) error
+	Delete(ctx context.Context, article *models.Article) error
+}
+
+// ArticleHooks คืองานที่ต้องทำคู่กับการบันทึกบทความ คือรูปที่บทความใช้ sitemap และภาพแชร์
+// repository ไม่รู้จักงานเหล่านี้เอง ตัวที่แอปใช้คือ composables.ArticleHooks
+type ArticleHooks interface {
+	// SitemapGroups คือหน้ารายการที่บทความปรากฏ อ่านใน transaction ก่อนแก้หรือลบ แล้วส่งต่อให้ Saved หรือ Deleted
+	SitemapGroups(tx *gorm.DB, article *models.Article) []models.SitemapEntry
+	// Saving รันใน transaction หลังสร้างหรือแก้บทความ error ทำให้ rollback ทั้งหมด
+	Saving(tx *gorm.DB, article *models.Article) error
+	// Deleting รันใน transaction ก่อนลบบทความ
+	Deleting(tx *gorm.DB, article *models.Article) error
+	// Saved รันหลัง commit before คือ SitemapGroups ของบทความก่อนแก้ เป็น nil ถ้าเป็นบทความใหม่
+	Saved(db *gorm.DB, articleID uint, before []models.SitemapEntry)
+	// Deleted รันหลัง commit ของการลบ
+	Deleted(db *gorm.DB, article *models.Article, groups []models.SitemapEntry)
+}
+
+// GormArticles คือ ArticleRepository บน GORM
+type GormArticles struct {
+	DB    *gorm.DB
+	Hooks ArticleHooks
+}
+
+// NewArticles สร้าง ArticleRepository บน db ที่เรียก hooks ทุกครั้งที่บันทึกหรือลบบทความ
+func NewArticles(db *gorm.DB, hooks ArticleHooks) *GormArticles {
+	return &GormArticles{DB: db, Hooks: hooks}
+}
+
+func (r *GormArticles) All(ctx context.Context) ([]models.Article, error) {
+	var articles []models.Article
+	err := conn(ctx, r.DB).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Preload("Comments").
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *GormArticles) List(ctx context.Context, filter ArticleFilter) ([]models.Article, error) {
+	var articles []models.Article
+	err := FilterArticles(conn(ctx, r.DB).Model(&models.Article{}), filter).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Preload("Cover").
+		Order("articles.created_at DESC").
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *GormArticles) ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := conn(ctx, r.DB).Where("author_id = ?", authorID).Find(&articles).Error
+	return articles, err
+}
+
+func (r *GormArticles) FindBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	var article models.Article
+	if err := conn(ctx, r.DB).Preload("Tags").First(&article, "slug = ?", slug).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &article, nil
+}
+
+func (r *GormArticles) FindDetailBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	var article models.Article
+	err := conn(ctx, r.DB).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Preload("Comments").
+		Preload("Cover").
+		First(&article, "slug = ?", slug).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &article, nil
+}
+
+func (r *GormArticles) TitleOrSlugTaken(ctx context.Context, title, slug string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&models.Article{}).Where("title = ? OR slug = ?", title, slug).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormArticles) FindCover(ctx context.Context, userID, mediaID uint) (*models.Media, error) {
+	var media models.Media
+	if err := conn(ctx, r.DB).Where("id = ? AND user_id = ?", mediaID, userID).First(&media).Error; err != nil {
+		if translateError(err) == ErrNotFound {
+			return nil, ErrCoverNotOwned
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *GormArticles) Create(ctx context.Context, article *models.Article) error {
+	article.Version = 1
+	return translateError(transaction(ctx, r.DB, func(ctx context.Context, tx *gorm.DB) error {
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		if err := r.Hooks.Saving(tx, article); err != nil {
+			return err
+		}
+		afterCommit(ctx, func() { r.Hooks.Saved(r.DB, article.ID, nil) })
+		return nil
+	}))
+}
+
+func (r *GormArticles) Update(ctx context.Context, article *models.Article, replaceTags bool) error {
+	return translateError(transaction(ctx, r.DB, func(ctx context.Context, tx *gorm.DB) error {
+		// หน้ารายการเดิมของบทความ เผื่อหมวดหมู่หรือแท็กถูกเปลี่ยน
+		var previous models.Article
+		if err := tx.Preload("Tags").First(&previous, article.ID).Error; err != nil {
+			return err
+		}
+		sitemapGroups := r.Hooks.SitemapGroups(tx, &previous)
+
+		if previous.Version != article.Version {
+			return ErrConflict
+		}
+		// เทียบ version ซ้ำใน UPDATE เผื่อมีคนบันทึกระหว่างที่อ่านกับเขียน
+		expected := article.Version
+		article.Version++
+		result := tx.Model(article).
+			Select("*").
+			Omit(clause.Associations, "id", "created_at").
+			Where("version = ?", expected).
+			Updates(article)
+		if result.Error != nil || result.RowsAffected == 0 {
+			article.Version = expected
+			if result.Error != nil {
+				return result.Error
+			}
+			return ErrConflict
+		}
+		if !replaceTags {
+			article.Tags = previous.Tags
+		} else if len(article.Tags) > 0 {
+			tags := article.Tags
+			if err := tx.Model(article).Association("Tags").Replace(&tags); err != nil {
+				return err
+			}
+		} else if err := tx.Model(article).Association("Tags").Clear(); err != nil {
+			return err
+		} else {
+			article.Tags = []models.Tags{}
+		}
+		if err := r.Hooks.Saving(tx, article); err != nil {
+			return err
+		}
+		afterCommit(ctx, func() { r.Hooks.Saved(r.DB, article.ID, sitemapGroups) })
+		return nil
+	}))
+}
+
+func (r *GormArticles) Delete(ctx context.Context, article *models.Article) error {
+	return translateError(transaction(ctx, r.DB, func(ctx context.Context, tx *gorm.DB) error {
+		sitemapGroups := r.Hooks.SitemapGroups(tx, article)
+		if err := tx.Model(article).Association("Tags").Clear(); err != nil {
+			return err
+		}
+		if err := r.Hooks.Deleting(tx, article); err != nil {
+			return err
+		}
+		if err := tx.Delete(article).Error; err != nil {
+			return err
+		}
+		afterCommit(ctx, func() { r.Hooks.Deleted(r.DB, article, sitemapGroups) })
+		return nil
+	}))
+}
+
+// FilterArticles เพิ่มเงื่อนไขของ filter ให้ query ของตาราง articles
+// subquery สร้างจาก connection เดียวกับ tx จึงใช้ใน transaction ได้
+func FilterArticles(tx *gorm.DB, filter ArticleFilter) *gorm.DB {
+	db := tx.Session(&gorm.Session{NewDB: true})
+	// แท็กค้นผ่าน subquery แทนการ join เพื่อไม่ให้บทความที่มีหลายแท็กซ้ำในผลลัพธ์และจำนวนรวม
+	for _, kw := range strings.Fields(filter.Search) {
+		pattern := Contains(kw)
+		tagged := db.Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tags_id").
+			Where("LOWER(tags.name) LIKE ?"+LikeEscape, pattern)
+		tx = tx.Where("LOWER(articles.title) LIKE ?"+LikeEscape+" OR LOWER(articles.content) LIKE ?"+LikeEscape+" OR articles.id IN (?)",
+			pattern, pattern, tagged)
+	}
+	if filter.CategoryID != "" {
+		tx = tx.Where("articles.category_id = ?", filter.CategoryID)
+	}
+	if filter.Tag != "" {
+		tagged := db.Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tags_id").
+			Where("LOWER(tags.name) = ?", strings.ToLower(filter.Tag))
+		tx = tx.Where("articles.id IN (?)", tagged)
+	}
+	if filter.Author != "" {
+		tx = tx.Where("articles.author_id IN (?)", db.Model(&models.User{}).Select("id").Where("username = ?", filter.Author))
+	}
+	return tx
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// เปิดฐานข้อมูล sqlite ในไฟล์ชั่วคราวที่รัน migration ครบแล้ว
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "blog.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	cfg := config.MustLoad(nil)
+	if err := database.Connect(cfg.Database); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if _, err := database.MigrateUp(database.DB); err != nil {
+		t.Fatal(err)
+	}
+	return database.DB
+}
+
+func createUser(t *testing.T, db *gorm.DB, username string) *models.User {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", PasswordHash: "x"}
+	if err := NewUsers(db).Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+// recordingHooks จดว่า GormArticles เรียก hook ใดไปบ้าง
+type recordingHooks struct {
+	calls     []string
+	savingErr error
+}
+
+func (h *recordingHooks) SitemapGroups(tx *gorm.DB, article *models.Article) []models.SitemapEntry {
+	return []models.SitemapEntry{{Kind: "article", Identifier: article.Slug}}
+}
+
+func (h *recordingHooks) Saving(tx *gorm.DB, article *models.Article) error {
+	h.calls = append(h.calls, "saving")
+	return h.savingErr
+}
+
+func (h *recordingHooks) Deleting(tx *gorm.DB, article *models.Article) error {
+	h.calls = append(h.calls, "deleting")
+	return nil
+}
+
+func (h *recordingHooks) Saved(db *gorm.DB, articleID uint, before []models.SitemapEntry) {
+	if before == nil {
+		h.calls = append(h.calls, "created")
+	} else {
+		h.calls = append(h.calls, "saved "+before[0].Identifier)
+	}
+}
+
+func (h *recordingHooks) Deleted(db *gorm.DB, article *models.Article, groups []models.SitemapEntry) {
+	h.calls = append(h.calls, "deleted "+groups[0].Identifier)
+}
+
+func (h *recordingHooks) took(t *testing.T, want ...string) {
+	t.Helper()
+	if len(h.calls) != len(want) {
+		t.Fatalf("hooks: got %v, want %v", h.calls, want)
+	}
+	for i := range want {
+		if h.calls[i] != want[i] {
+			t.Fatalf("hooks: got %v, want %v", h.calls, want)
+		}
+	}
+	h.calls = nil
+}
+
+// สร้างบทความพร้อมหมวดหมู่และแท็กตามชื่อ
+func newArticle(t *testing.T, db *gorm.DB, repo *GormArticles, author *models.User, slug, title, content, category string, tags ...string) *models.Article {
+	t.Helper()
+	c, err := FindOrCreateCategory(db, category)
+	if err != nil {
+		t.Fatal(err)
+	}
+	article := models.Article{Title: title, Slug: slug, Content: content, AuthorID: author.ID, CategoryID: c.ID}
+	if article.Tags, err = FindOrCreateTags(db, tags); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Create(context.Background(), &article); err != nil {
+		t.Fatalf("create %s: %v", slug, err)
+	}
+	return &article
+}
+
+func TestArticles(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	hooks := &recordingHooks{}
+	repo := NewArticles(db, hooks)
+	author := createUser(t, db, "alice")
+
+	article := newArticle(t, db, repo, author, "hello", "Hello", "first post", "Go", "go", "web")
+	if article.ID == 0 || article.Version != 1 {
+		t.Fatalf("created %+v", article)
+	}
+	hooks.took(t, "saving", "created")
+
+	if taken, err := repo.TitleOrSlugTaken(ctx, "other", "hello"); err != nil || !taken {
+		t.Fatalf("slug taken: %v, %v", taken, err)
+	}
+	if taken, err := repo.TitleOrSlugTaken(ctx, "other", "other"); err != nil || taken {
+		t.Fatalf("free title: %v, %v", taken, err)
+	}
+	duplicate := models.Article{Title: "Hello", Slug: "hello-2", Content: "x", AuthorID: author.ID, CategoryID: article.CategoryID}
+	if err := repo.Create(ctx, &duplicate); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("duplicate title: %v", err)
+	}
+	hooks.took(t)
+
+	// error ของ hook ใน transaction ต้องทำให้บทความไม่ถูกบันทึก
+	hooks.savingErr = errors.New("sync media failed")
+	failed := models.Article{Title: "Failed", Slug: "failed", Content: "x", AuthorID: author.ID, CategoryID: article.CategoryID}
+	if err := repo.Create(ctx, &failed); !errors.Is(err, hooks.savingErr) {
+		t.Fatalf("create with failing hook: %v", err)
+	}
+	hooks.savingErr = nil
+	hooks.took(t, "saving")
+	if _, err := repo.FindBySlug(ctx, "failed"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("article of failed create: %v", err)
+	}
+
+	detail, err := repo.FindDetailBySlug(ctx, "hello")
+	if err != nil || detail.Author.Username != "alice" || detail.Category.Name != "Go" || len(detail.Tags) != 2 {
+		t.Fatalf("detail: %+v, %v", detail, err)
+	}
+	if _, err := repo.FindBySlug(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing slug: %v", err)
+	}
+
+	// แก้โดยไม่แทนแท็ก แท็กเดิมต้องยังอยู่
+	edit, err := repo.FindBySlug(ctx, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit.Title = "Hello again"
+	edit.Tags = nil
+	if err := repo.Update(ctx, edit, false); err != nil {
+		t.Fatal(err)
+	}
+	if edit.Version != 2 || len(edit.Tags) != 2 {
+		t.Fatalf("updated %+v", edit)
+	}
+	hooks.took(t, "saving", "saved hello")
+
+	// ผู้แก้ไขที่ยังถือ version 1 ต้องได้ ErrConflict และข้อมูลต้องไม่เปลี่ยน
+	stale := *article
+	stale.Title = "Stale"
+	if err := repo.Update(ctx, &stale, false); !errors.Is(err, ErrConflict) || stale.Version != 1 {
+		t.Fatalf("stale update: %v, version %d", err, stale.Version)
+	}
+	hooks.took(t)
+
+	web := edit.Tags[1]
+	edit.Tags = []models.Tags{web}
+	if err := repo.Update(ctx, edit, true); err != nil {
+		t.Fatal(err)
+	}
+	edit.Tags = nil
+	if err := repo.Update(ctx, edit, true); err != nil {
+		t.Fatal(err)
+	}
+	hooks.took(t, "saving", "saved hello", "saving", "saved hello")
+	saved, err := repo.FindDetailBySlug(ctx, "hello")
+	if err != nil || saved.Title != "Hello again" || saved.Version != 4 || len(saved.Tags) != 0 {
+		t.Fatalf("after updates: %+v, %v", saved, err)
+	}
+
+	list, err := repo.ListByAuthor(ctx, author.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list by author: %+v, %v", list, err)
+	}
+	all, err := repo.All(ctx)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("all: %+v, %v", all, err)
+	}
+
+	if err := repo.Delete(ctx, saved); err != nil {
+		t.Fatal(err)
+	}
+	hooks.took(t, "deleting", "deleted hello")
+	if _, err := repo.FindBySlug(ctx, "hello"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted article: %v", err)
+	}
+}
+
+func TestArticleCover(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewArticles(db, &recordingHooks{})
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	media := models.Media{UserID: alice.ID, StorageKey: "media/1.png", ContentType: "image/png", URL: "/uploads/media/1.png"}
+	if err := db.Create(&media).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cover, err := repo.FindCover(ctx, alice.ID, media.ID)
+	if err != nil || cover.ID != media.ID {
+		t.Fatalf("own cover: %+v, %v", cover, err)
+	}
+	if _, err := repo.FindCover(ctx, bob.ID, media.ID); !errors.Is(err, ErrCoverNotOwned) {
+		t.Fatalf("cover of another user: %v", err)
+	}
+	if _, err := repo.FindCover(ctx, alice.ID, media.ID+1); !errors.Is(err, ErrCoverNotOwned) {
+		t.Fatalf("missing cover: %v", err)
+	}
+}
+
+// hook ที่ลงด้วย afterCommit ต้องรอ transaction นอกสุด และไม่รันเลยถ้า rollback
+func TestArticleHooksAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	hooks := &recordingHooks{}
+	repo := NewArticles(db, hooks)
+	author := createUser(t, db, "alice")
+	category, err := FindOrCreateCategory(db, "Go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errAbort := errors.New("abort")
+	err = NewTransactor(db).Transaction(ctx, func(ctx context.Context) error {
+		article := models.Article{Title: "A", Slug: "a", Content: "x", AuthorID: author.ID, CategoryID: category.ID}
+		if err := repo.Create(ctx, &article); err != nil {
+			return err
+		}
+		hooks.took(t, "saving")
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: %v", err)
+	}
+	hooks.took(t)
+	if _, err := repo.FindBySlug(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("article of rolled back transaction: %v", err)
+	}
+
+	err = NewTransactor(db).Transaction(ctx, func(ctx context.Context) error {
+		article := models.Article{Title: "A", Slug: "a", Content: "x", AuthorID: author.ID, CategoryID: category.ID}
+		if err := repo.Create(ctx, &article); err != nil {
+			return err
+		}
+		hooks.took(t, "saving")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks.took(t, "created")
+}
+
+func TestFilterArticles(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewArticles(db, &recordingHooks{})
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	newArticle(t, db, repo, alice, "fiber", "Fiber routing", "handlers and middleware", "Go", "go", "web")
+	newArticle(t, db, repo, alice, "docker", "Docker basics", "images at 100% speed", "DevOps", "containers", "container-tools")
+	newArticle(t, db, repo, bob, "channels", "Channels", "goroutines talk", "Go", "go", "concurrency")
+	goCategory, err := FindOrCreateCategory(db, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter ArticleFilter
+		want   []string
+	}{
+		{"all, newest first", ArticleFilter{}, []string{"channels", "docker", "fiber"}},
+		{"title", ArticleFilter{Search: "ROUTING"}, []string{"fiber"}},
+		{"content", ArticleFilter{Search: "goroutines"}, []string{"channels"}},
+		// บทความที่มีหลายแท็กตรงคำค้นต้องไม่ซ้ำ
+		{"tag name", ArticleFilter{Search: "container"}, []string{"docker"}},
+		{"every keyword", ArticleFilter{Search: "go web"}, []string{"fiber"}},
+		{"percent is literal", ArticleFilter{Search: "100%"}, []string{"docker"}},
+		{"underscore is literal", ArticleFilter{Search: "a_d"}, nil},
+		{"category", ArticleFilter{CategoryID: strconv.FormatUint(uint64(goCategory.ID), 10)}, []string{"channels", "fiber"}},
+		{"tag ignores case", ArticleFilter{Tag: "GO"}, []string{"channels", "fiber"}},
+		{"author", ArticleFilter{Author: "bob"}, []string{"channels"}},
+		{"tag and author", ArticleFilter{Tag: "go", Author: "alice"}, []string{"fiber"}},
+		{"unknown author", ArticleFilter{Author: "carol"}, nil},
+	} {
+		articles, err := repo.List(ctx, tc.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var slugs []string
+		for _, article := range articles {
+			slugs = append(slugs, article.Slug)
+		}
+		if !slices.Equal(slugs, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, slugs, tc.want)
+		}
+
+		// จำนวนรวมจาก query เดียวกันต้องตรงกับรายการ
+		var count int64
+		if err := FilterArticles(db.Model(&models.Article{}), tc.filter).Count(&count).Error; err != nil || count != int64(len(tc.want)) {
+			t.Errorf("%s: count %d, %v, want %d", tc.name, count, err, len(tc.want))
+		}
+	}
+}
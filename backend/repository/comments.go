@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// CommentRepository อ่านเขียนคอมเมนต์ คอมเมนต์ที่อ่านหรือแก้ไขแล้วมีข้อมูลผู้เขียนคอมเมนต์มาด้วย
+type CommentRepository interface {
+	// ListByArticle คืนคอมเมนต์ของบทความเรียงจากใหม่ไปเก่า
+	ListByArticle(ctx context.Context, articleID uint) ([]models.Comment, error)
+	FindByID(ctx context.Context, id uint) (*models.Comment, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	// Update บันทึกเนื้อหาคอมเมนต์ comment.Version คือ version ที่ผู้แก้ไขอ่านไป ใช้แบบเดียวกับ ArticleRepository.Update
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, comment *models.Comment) error
+}
+
+// GormComments คือ CommentRepository บน GORM
+type GormComments struct {
+	DB *gorm.DB
+}
+
+// NewComments สร้าง CommentRepository บน db
+func NewComments(db *gorm.DB) *GormComments {
+	return &GormComments{DB: db}
+}
+
+func (r *GormComments) ListByArticle(ctx context.Context, articleID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := conn(ctx, r.DB).
+		Preload("User").
+		Order("created_at desc").
+		Where("article_id = ?", articleID).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *GormComments) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := conn(ctx, r.DB).Preload("User").First(&comment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &comment, nil
+}
+
+func (r *GormComments) Create(ctx context.Context, comment *models.Comment) error {
+	comment.Version = 1
+	return conn(ctx, r.DB).Create(comment).Error
+}
+
+func (r *GormComments) Update(ctx context.Context, comment *models.Comment) error {
+	db := conn(ctx, r.DB)
+	expected := comment.Version
+	comment.Version++
+	result := db.Model(comment).Select("content", "version", "updated_at").Where("version = ?", expected).Updates(comment)
+	if result.Error != nil || result.RowsAffected == 0 {
+		comment.Version = expected
+		if result.Error != nil {
+			return result.Error
+		}
+		return ErrConflict
+	}
+	return db.Preload("User").First(comment, comment.ID).Error
+}
+
+func (r *GormComments) Delete(ctx context.Context, comment *models.Comment) error {
+	return conn(ctx, r.DB).Delete(comment).Error
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var _ repository.ArticleRepository = (*Articles)(nil)
+
+// Articles คือ ArticleRepository ในหน่วยความจำ
+type Articles struct {
+	s *Store
+}
+
+func (r *Articles) All(ctx context.Context) ([]models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.sorted(func(models.Article) bool { return true }, true), nil
+}
+
+// List กรองแบบเดียวกับ repository.FilterArticles
+func (r *Articles) List(ctx context.Context, filter repository.ArticleFilter) ([]models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	keywords := strings.Fields(strings.ToLower(filter.Search))
+	articles := r.sorted(func(article models.Article) bool {
+		article = r.s.hydrate(article, false)
+		for _, kw := range keywords {
+			found := strings.Contains(strings.ToLower(article.Title), kw) || strings.Contains(strings.ToLower(article.Content), kw)
+			for _, tag := range article.Tags {
+				found = found || strings.Contains(strings.ToLower(tag.Name), kw)
+			}
+			if !found {
+				return false
+			}
+		}
+		if filter.CategoryID != "" && filter.CategoryID != strconv.FormatUint(uint64(article.CategoryID), 10) {
+			return false
+		}
+		if filter.Tag != "" {
+			tagged := false
+			for _, tag := range article.Tags {
+				tagged = tagged || strings.EqualFold(tag.Name, filter.Tag)
+			}
+			if !tagged {
+				return false
+			}
+		}
+		return filter.Author == "" || article.Author.Username == filter.Author
+	}, false)
+	sort.SliceStable(articles, func(i, j int) bool { return articles[i].CreatedAt.After(articles[j].CreatedAt) })
+	return articles, nil
+}
+
+func (r *Articles) ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	articles := r.sorted(func(article models.Article) bool { return article.AuthorID == authorID }, false)
+	// GORM ไม่ได้ Preload ความสัมพันธ์ให้รายการนี้
+	for i := range articles {
+		articles[i].Author, articles[i].Category, articles[i].Tags, articles[i].Cover = models.User{}, models.Category{}, nil, nil
+	}
+	return articles, nil
+}
+
+func (r *Articles) FindBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	return r.find(slug, false)
+}
+
+func (r *Articles) FindDetailBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	return r.find(slug, true)
+}
+
+func (r *Articles) TitleOrSlugTaken(ctx context.Context, title, slug string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, article := range r.s.articles {
+		if article.Title == title || article.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *Articles) FindCover(ctx context.Context, userID, mediaID uint) (*models.Media, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	media, ok := r.s.media[mediaID]
+	if !ok || media.UserID != userID {
+		return nil, repository.ErrCoverNotOwned
+	}
+	return &media, nil
+}
+
+func (r *Articles) Create(ctx context.Context, article *models.Article) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(article); err != nil {
+		return err
+	}
+	article.ID = r.s.nextID()
+	article.Version = 1
+	stamp(&article.CreatedAt, &article.UpdatedAt)
+	r.s.articles[article.ID] = *article
+	return nil
+}
+
+func (r *Articles) Update(ctx context.Context, article *models.Article, replaceTags bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	previous, ok := r.s.articles[article.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if previous.Version != article.Version {
+		return repository.ErrConflict
+	}
+	if err := r.checkUnique(article); err != nil {
+		return err
+	}
+	saved := *article
+	saved.Version++
+	if !replaceTags {
+		saved.Tags = previous.Tags
+	}
+	stamp(nil, &saved.UpdatedAt)
+	r.s.articles[article.ID] = saved
+	article.UpdatedAt, article.Tags, article.Version = saved.UpdatedAt, saved.Tags, saved.Version
+	return nil
+}
+
+func (r *Articles) Delete(ctx context.Context, article *models.Article) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.articles, article.ID)
+	// คอมเมนต์ถูกลบตาม foreign key แบบ cascade
+	for id, comment := range r.s.comments {
+		if comment.ArticleID == article.ID {
+			delete(r.s.comments, id)
+		}
+	}
+	return nil
+}
+
+func (r *Articles) find(slug string, detail bool) (*models.Article, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, article := range r.s.articles {
+		if article.Slug == slug {
+			article = r.s.hydrate(article, detail)
+			return &article, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// คืนบทความที่ตรงเงื่อนไขเรียงตาม id เหมือนลำดับที่ฐานข้อมูลคืนเมื่อไม่ได้ระบุ ORDER BY
+func (r *Articles) sorted(match func(models.Article) bool, withComments bool) []models.Article {
+	articles := []models.Article{}
+	for _, article := range r.s.articles {
+		if match(article) {
+			articles = append(articles, r.s.hydrate(article, withComments))
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+	return articles
+}
+
+// ชื่อเรื่องและ slug ห้ามซ้ำ เหมือน unique index ของตาราง articles
+func (r *Articles) checkUnique(article *models.Article) error {
+	for _, other := range r.s.articles {
+		if other.ID != article.ID && (other.Title == article.Title || other.Slug == article.Slug) {
+			return repository.ErrDuplicate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"sort"
+)
+
+var _ repository.CommentRepository = (*Comments)(nil)
+
+// Comments คือ CommentRepository ในหน่วยความจำ
+type Comments struct {
+	s *Store
+}
+
+func (r *Comments) ListByArticle(ctx context.Context, articleID uint) ([]models.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	comments := []models.Comment{}
+	for _, comment := range r.s.comments {
+		if comment.ArticleID == articleID {
+			comment.User = r.s.users[comment.UserID]
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].ID > comments[j].ID
+		}
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *Comments) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	comment, ok := r.s.comments[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	comment.User = r.s.users[comment.UserID]
+	return &comment, nil
+}
+
+func (r *Comments) Create(ctx context.Context, comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	comment.ID = r.s.nextID()
+	comment.Version = 1
+	stamp(&comment.CreatedAt, &comment.UpdatedAt)
+	r.s.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *Comments) Update(ctx context.Context, comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	previous, ok := r.s.comments[comment.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if previous.Version != comment.Version {
+		return repository.ErrConflict
+	}
+	comment.Version++
+	stamp(nil, &comment.UpdatedAt)
+	r.s.comments[comment.ID] = *comment
+	comment.User = r.s.users[comment.UserID]
+	return nil
+}
+
+func (r *Comments) Delete(ctx context.Context, comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.comments, comment.ID)
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package memory คือ repository ปลอมที่เก็บข้อมูลไว้ในหน่วยความจำ
+// ใช้ทดสอบ domain service โดยไม่ต้องมีฐานข้อมูล ทุก repository ที่ได้จาก Store เดียวกันเห็นข้อมูลชุดเดียวกัน
+// เพื่อให้ผู้เขียน หมวดหมู่ และแท็กของบทความถูกเติมให้เหมือนการ Preload ของ GORM
+package memory
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ repository.Transactor = (*Store)(nil)
+
+// Store เก็บข้อมูลทุกตาราง
+type Store struct {
+	mu         sync.Mutex
+	lastID     uint
+	users      map[uint]models.User
+	articles   map[uint]models.Article
+	comments   map[uint]models.Comment
+	tags       map[uint]models.Tags
+	categories map[uint]models.Category
+	media      map[uint]models.Media
+}
+
+// New สร้าง Store ว่าง
+func New() *Store {
+	return &Store{
+		users:      map[uint]models.User{},
+		articles:   map[uint]models.Article{},
+		comments:   map[uint]models.Comment{},
+		tags:       map[uint]models.Tags{},
+		categories: map[uint]models.Category{},
+		media:      map[uint]models.Media{},
+	}
+}
+
+// Articles คืน ArticleRepository ของ Store
+func (s *Store) Articles() *Articles { return &Articles{s} }
+
+// Comments คืน CommentRepository ของ Store
+func (s *Store) Comments() *Comments { return &Comments{s} }
+
+// Users คืน UserRepository ของ Store
+func (s *Store) Users() *Users { return &Users{s} }
+
+// Tags คืน TagRepository ของ Store
+func (s *Store) Tags() *Tags { return &Tags{s} }
+
+// Categories คืน CategoryRepository ของ Store
+func (s *Store) Categories() *Categories { return &Categories{s} }
+
+// Transaction รัน fn แล้วคืนข้อมูลทุกตารางกลับเป็นเหมือนก่อนเรียกถ้า fn คืน error
+// ไม่กันการเขียนจาก goroutine อื่นระหว่างนั้น พอสำหรับทดสอบการ rollback แต่ไม่ใช่การแยก transaction จริง
+func (s *Store) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	snapshot := Store{
+		lastID:     s.lastID,
+		users:      maps.Clone(s.users),
+		articles:   maps.Clone(s.articles),
+		comments:   maps.Clone(s.comments),
+		tags:       maps.Clone(s.tags),
+		categories: maps.Clone(s.categories),
+		media:      maps.Clone(s.media),
+	}
+	s.mu.Unlock()
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.lastID, s.users, s.articles, s.comments = snapshot.lastID, snapshot.users, snapshot.articles, snapshot.comments
+		s.tags, s.categories, s.media = snapshot.tags, snapshot.categories, snapshot.media
+		return err
+	}
+	return nil
+}
+
+// AddMedia เพิ่มรูปในคลังของผู้ใช้ ใช้เป็นภาพปกของบทความได้
+func (s *Store) AddMedia(media models.Media) models.Media {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if media.ID == 0 {
+		media.ID = s.nextID()
+	}
+	s.media[media.ID] = media
+	return media
+}
+
+// id ใช้ลำดับเดียวทุกตาราง ไม่ต้องตรงกับฐานข้อมูลจริง แค่ไม่ซ้ำ
+func (s *Store) nextID() uint {
+	s.lastID++
+	return s.lastID
+}
+
+// เติมความสัมพันธ์ของบทความจากตารางอื่น เหมือน Preload
+func (s *Store) hydrate(article models.Article, withComments bool) models.Article {
+	article.Author = s.users[article.AuthorID]
+	article.Category = s.categories[article.CategoryID]
+	tags := make([]models.Tags, 0, len(article.Tags))
+	for _, tag := range article.Tags {
+		if current, ok := s.tags[tag.ID]; ok {
+			tags = append(tags, current)
+		}
+	}
+	article.Tags = tags
+	article.Cover = nil
+	if article.CoverMediaID != nil {
+		if media, ok := s.media[*article.CoverMediaID]; ok {
+			article.Cover = &media
+		}
+	}
+	article.Comments = nil
+	if withComments {
+		for _, comment := range s.comments {
+			if comment.ArticleID == article.ID {
+				article.Comments = append(article.Comments, comment)
+			}
+		}
+		sort.Slice(article.Comments, func(i, j int) bool { return article.Comments[i].ID < article.Comments[j].ID })
+	}
+	return article
+}
+
+func (s *Store) findTagByName(name string) (models.Tags, bool) {
+	for _, tag := range s.tags {
+		if strings.EqualFold(tag.Name, name) {
+			return tag, true
+		}
+	}
+	return models.Tags{}, false
+}
+
+func (s *Store) findUser(match func(models.User) bool) (*models.User, error) {
+	for _, user := range s.users {
+		if match(user) {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// ตั้งเวลาสร้างและแก้ไขแบบเดียวกับ GORM
+func stamp(created, updated *time.Time) {
+	now := time.Now()
+	if created != nil && created.IsZero() {
+		*created = now
+	}
+	*updated = now
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	_ repository.TagRepository      = (*Tags)(nil)
+	_ repository.CategoryRepository = (*Categories)(nil)
+)
+
+// Tags คือ TagRepository ในหน่วยความจำ
+type Tags struct {
+	s *Store
+}
+
+func (r *Tags) All(ctx context.Context) ([]models.Tags, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	tags := []models.Tags{}
+	for _, tag := range r.s.tags {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags, nil
+}
+
+func (r *Tags) FindByIDs(ctx context.Context, ids []uint) ([]models.Tags, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var tags []models.Tags
+	for _, id := range ids {
+		if tag, ok := r.s.tags[id]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (r *Tags) FindByName(ctx context.Context, name string) (*models.Tags, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if tag, ok := r.s.findTagByName(name); ok {
+		return &tag, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *Tags) FindOrCreate(ctx context.Context, names []string) ([]models.Tags, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var tags []models.Tags
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tag, ok := r.s.findTagByName(name)
+		if !ok {
+			tag = models.Tags{ID: r.s.nextID(), Name: name}
+			r.s.tags[tag.ID] = tag
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Categories คือ CategoryRepository ในหน่วยความจำ
+type Categories struct {
+	s *Store
+}
+
+func (r *Categories) All(ctx context.Context) ([]models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	categories := []models.Category{}
+	for _, category := range r.s.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	return categories, nil
+}
+
+func (r *Categories) FindOrCreate(ctx context.Context, name string) (*models.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("empty category name")
+	}
+	for _, category := range r.s.categories {
+		if strings.EqualFold(category.Name, name) {
+			return &category, nil
+		}
+	}
+	category := models.Category{ID: r.s.nextID(), Name: name}
+	r.s.categories[category.ID] = category
+	return &category, nil
+}
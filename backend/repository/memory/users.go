@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"backend/models"
+	"backend/repository"
+	"context"
+	"strings"
+)
+
+var _ repository.UserRepository = (*Users)(nil)
+
+// Users คือ UserRepository ในหน่วยความจำ
+type Users struct {
+	s *Store
+}
+
+func (r *Users) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.findUser(func(user models.User) bool { return user.ID == id })
+}
+
+func (r *Users) FindByLogin(ctx context.Context, emailOrUsername string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.findUser(func(user models.User) bool {
+		return strings.EqualFold(user.Email, emailOrUsername) || strings.EqualFold(user.Username, emailOrUsername)
+	})
+}
+
+func (r *Users) EmailTaken(ctx context.Context, email string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	_, err := r.s.findUser(func(user models.User) bool { return strings.EqualFold(user.Email, email) })
+	return err == nil, nil
+}
+
+func (r *Users) UsernameTaken(ctx context.Context, username string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	_, err := r.s.findUser(func(user models.User) bool { return strings.EqualFold(user.Username, username) })
+	return err == nil, nil
+}
+
+func (r *Users) Create(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+	user.ID = r.s.nextID()
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r *Users) Save(ctx context.Context, user *models.User) error {
+	if user.ID == 0 {
+		return r.Create(ctx, user)
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+// อีเมลและ username ห้ามซ้ำ เหมือน unique index ของตาราง users
+func (r *Users) checkUnique(user *models.User) error {
+	for _, other := range r.s.users {
+		if other.ID == user.ID {
+			continue
+		}
+		if (user.Email != "" && strings.EqualFold(other.Email, user.Email)) || (user.Username != "" && strings.EqualFold(other.Username, user.Username)) {
+			return repository.ErrDuplicate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "strings"
+
+// LikeEscape ต่อท้ายเงื่อนไข LIKE ให้ทุก driver ใช้ ! เป็นตัว escape
+// SQLite ไม่มีตัว escape ถ้าไม่ระบุ ส่วน MySQL และ postgres ใช้ \ ซึ่งต้อง escape ต่างกันใน string
+const LikeEscape = " ESCAPE '!'"
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Contains คืน pattern ของ LIKE ที่หา s ตรงตัวอักษร % และ _ ที่ผู้ใช้พิมพ์ไม่ถือเป็น wildcard
+// ใช้คู่กับ LikeEscape และ LOWER() ของคอลัมน์ เพราะ postgres เทียบ LIKE แบบสนตัวพิมพ์
+func Contains(s string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(s)) + "%"
+}
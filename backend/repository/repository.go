@@ -0,0 +1,31 @@
+// Package repository แยกการอ่านเขียนฐานข้อมูลออกจาก service
+// แต่ละ repository เป็น interface ที่มีตัวจริงบน GORM ในแพ็กเกจนี้ และตัวปลอมในหน่วยความจำที่ repository/memory
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound คือหาข้อมูลที่ขอไม่เจอ ทุก repository คืนค่านี้แทน error ของ driver
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate คือข้อมูลซ้ำกับ unique index เช่นสอง request สร้างบทความชื่อเดียวกันพร้อมกัน
+	ErrDuplicate = errors.New("duplicate key")
+	// ErrConflict คือข้อมูลถูกแก้ไปแล้วหลังจากที่ผู้แก้ไขอ่าน version ไป
+	ErrConflict = errors.New("version conflict")
+	// ErrCoverNotOwned คือรูปปกที่ไม่ได้อยู่ในคลังรูปของผู้เขียน
+	ErrCoverNotOwned = errors.New("cover image not found in your media library")
+)
+
+// แปลง error ของ GORM ให้ผู้เรียกไม่ต้องรู้จัก gorm
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
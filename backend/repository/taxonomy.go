@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository อ่านเขียนแท็ก ชื่อแท็กเทียบแบบไม่สนตัวพิมพ์
+type TagRepository interface {
+	All(ctx context.Context) ([]models.Tags, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Tags, error)
+	FindByName(ctx context.Context, name string) (*models.Tags, error)
+	// FindOrCreate คืนแท็กตามชื่อ ชื่อที่ยังไม่มีจะถูกสร้าง ชื่อว่างถูกข้าม
+	// request ที่สร้างชื่อเดียวกันพร้อมกันจะได้แท็กเดียวกัน ไม่ใช่ error
+	FindOrCreate(ctx context.Context, names []string) ([]models.Tags, error)
+}
+
+// CategoryRepository อ่านเขียนหมวดหมู่ ชื่อหมวดหมู่เทียบแบบไม่สนตัวพิมพ์
+type CategoryRepository interface {
+	All(ctx context.Context) ([]models.Category, error)
+	// FindOrCreate คืนหมวดหมู่ตามชื่อ สร้างให้ถ้ายังไม่มี ปลอดภัยเมื่อมีหลาย request สร้างชื่อเดียวกันพร้อมกัน
+	FindOrCreate(ctx context.Context, name string) (*models.Category, error)
+}
+
+// GormTags คือ TagRepository บน GORM
+type GormTags struct {
+	DB *gorm.DB
+}
+
+// NewTags สร้าง TagRepository บน db
+func NewTags(db *gorm.DB) *GormTags {
+	return &GormTags{DB: db}
+}
+
+func (r *GormTags) All(ctx context.Context) ([]models.Tags, error) {
+	var tags []models.Tags
+	err := conn(ctx, r.DB).Find(&tags).Error
+	return tags, err
+}
+
+func (r *GormTags) FindByIDs(ctx context.Context, ids []uint) ([]models.Tags, error) {
+	var tags []models.Tags
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := conn(ctx, r.DB).Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
+
+func (r *GormTags) FindByName(ctx context.Context, name string) (*models.Tags, error) {
+	var tag models.Tags
+	if err := conn(ctx, r.DB).Where("LOWER(name) = ?", strings.ToLower(name)).First(&tag).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &tag, nil
+}
+
+func (r *GormTags) FindOrCreate(ctx context.Context, names []string) ([]models.Tags, error) {
+	return FindOrCreateTags(conn(ctx, r.DB), names)
+}
+
+// GormCategories คือ CategoryRepository บน GORM
+type GormCategories struct {
+	DB *gorm.DB
+}
+
+// NewCategories สร้าง CategoryRepository บน db
+func NewCategories(db *gorm.DB) *GormCategories {
+	return &GormCategories{DB: db}
+}
+
+func (r *GormCategories) All(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	err := conn(ctx, r.DB).Find(&categories).Error
+	return categories, err
+}
+
+func (r *GormCategories) FindOrCreate(ctx context.Context, name string) (*models.Category, error) {
+	return FindOrCreateCategory(conn(ctx, r.DB), name)
+}
+
+// FindOrCreateCategory ค้นหาหรือสร้างหมวดหมู่ด้วย db ที่อาจเป็น transaction อยู่แล้ว
+func FindOrCreateCategory(db *gorm.DB, name string) (*models.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("empty category name")
+	}
+	category := models.Category{Name: name}
+	if err := findOrCreateByName(db, &category, name); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindOrCreateTags ค้นหาหรือสร้างแท็กหลายตัวด้วย db ที่อาจเป็น transaction อยู่แล้ว
+func FindOrCreateTags(db *gorm.DB, names []string) ([]models.Tags, error) {
+	var tags []models.Tags
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tag := models.Tags{Name: name}
+		if err := findOrCreateByName(db, &tag, name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// หาแถวที่ชื่อตรงกับ name แบบไม่สนตัวพิมพ์มาใส่ row ถ้าไม่มีจึงบันทึก row เป็นแถวใหม่
+// request ที่สร้างชื่อเดียวกันพร้อมกันจะชน unique index ของ name จึง insert แบบ ON CONFLICT DO NOTHING
+// แล้วอ่านแถวของอีกฝ่ายแทนการคืน error ซึ่งจะทำให้ transaction ของ Postgres ใช้ต่อไม่ได้
+// การอ่านซ้ำใช้ FOR UPDATE เพื่อให้ MySQL เห็นแถวที่เพิ่ง commit แม้ transaction จะเริ่ม snapshot ไปแล้ว
+func findOrCreateByName[T any](db *gorm.DB, row *T, name string) error {
+	var found T
+	err := db.Where("LOWER(name) = ?", strings.ToLower(name)).First(&found).Error
+	if err == nil {
+		*row = found
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var existing T
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", name).First(&existing).Error; err != nil {
+			return err
+		}
+		*row = existing
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestFindOrCreateTags(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewTags(db)
+
+	created, err := repo.FindOrCreate(ctx, []string{"Go", " ", " web "})
+	if err != nil || len(created) != 2 || created[1].Name != "web" {
+		t.Fatalf("create: %+v, %v", created, err)
+	}
+	// ชื่อที่ต่างกันแค่ตัวพิมพ์คือแท็กเดิม
+	found, err := repo.FindOrCreate(ctx, []string{"GO"})
+	if err != nil || len(found) != 1 || found[0].ID != created[0].ID || found[0].Name != "Go" {
+		t.Fatalf("find: %+v, %v", found, err)
+	}
+	var count int64
+	if err := db.Model(&models.Tags{}).Count(&count).Error; err != nil || count != 2 {
+		t.Fatalf("tags: %d, %v", count, err)
+	}
+
+	if _, err := FindOrCreateCategory(db, "  "); err == nil {
+		t.Fatal("empty category name: want an error")
+	}
+}
+
+// อีก request สร้างชื่อเดียวกันไปหลังจากที่เราหาไม่เจอแต่ก่อน insert
+// insert ต้องไม่ error และต้องได้แถวของอีกฝ่ายกลับมา
+func TestFindOrCreateAfterConcurrentInsert(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	var other models.Tags
+	inserted := false
+	err := db.Callback().Create().Before("gorm:create").Register("test:concurrent_insert", func(tx *gorm.DB) {
+		tag, ok := tx.Statement.Dest.(*models.Tags)
+		if !ok || inserted {
+			return
+		}
+		inserted = true
+		// ใช้ connection ของ statement เอง เพราะ transaction ของ sqlite จองสิทธิ์เขียนไว้แล้ว
+		other = models.Tags{Name: tag.Name}
+		if err := tx.Session(&gorm.Session{NewDB: true}).Create(&other).Error; err != nil {
+			tx.AddError(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewTransactor(db).Transaction(ctx, func(ctx context.Context) error {
+		tags, err := NewTags(db).FindOrCreate(ctx, []string{"race"})
+		if err != nil {
+			return err
+		}
+		if other.ID == 0 || tags[0].ID != other.ID {
+			t.Errorf("got tag %+v, want the concurrent one %+v", tags[0], other)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+	var count int64
+	if err := db.Model(&models.Tags{}).Where("name = ?", "race").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("tags named race: %d, %v", count, err)
+	}
+}
+
+// หลาย request สร้างหมวดหมู่และแท็กชื่อเดียวกันพร้อมกันใน transaction ต้องสำเร็จทั้งหมดและได้แถวเดียวกัน
+func TestFindOrCreateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	tx := NewTransactor(db)
+	tags := NewTags(db)
+	categories := NewCategories(db)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	ids := make([][2]uint, workers)
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = tx.Transaction(ctx, func(ctx context.Context) error {
+				category, err := categories.FindOrCreate(ctx, "Shared")
+				if err != nil {
+					return err
+				}
+				found, err := tags.FindOrCreate(ctx, []string{"shared"})
+				if err != nil {
+					return err
+				}
+				ids[i] = [2]uint{category.ID, found[0].ID}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range ids {
+		if errs[i] != nil {
+			t.Fatalf("worker %d: %v", i, errs[i])
+		}
+		if ids[i] != ids[0] {
+			t.Fatalf("worker %d got ids %v, worker 0 got %v", i, ids[i], ids[0])
+		}
+	}
+	var names []string
+	if err := db.Model(&models.Category{}).Pluck("name", &names).Error; err != nil || strings.Join(names, ",") != "Shared" {
+		t.Fatalf("categories: %v, %v", names, err)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor รันงานของหลาย repository ใน transaction เดียว
+type Transactor interface {
+	// Transaction เรียก fn ด้วย ctx ที่ผูกกับ transaction repository ที่ได้ ctx นี้จะอ่านเขียนใน transaction เดียวกัน
+	// ถ้า fn คืน error ทุกอย่างที่เขียนไปจะถูก rollback
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// GormTransactor คือ Transactor บน GORM
+type GormTransactor struct {
+	DB *gorm.DB
+}
+
+// NewTransactor สร้าง Transactor บน db
+func NewTransactor(db *gorm.DB) *GormTransactor {
+	return &GormTransactor{DB: db}
+}
+
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return transaction(ctx, t.DB, func(ctx context.Context, _ *gorm.DB) error { return fn(ctx) })
+}
+
+type txKey struct{}
+
+// txState คือ transaction ที่ผูกกับ ctx และงานที่รอให้ commit ก่อน
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// คืน transaction ที่ผูกกับ ctx ถ้ามี ไม่อย่างนั้นคืน db
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// รัน fn ใน transaction ถ้า ctx อยู่ใน transaction แล้ว fn จะเข้าร่วม transaction เดิมแทนการเปิด savepoint
+// error ของ fn จึงต้องถูกส่งต่อขึ้นไปให้ transaction นอกสุด rollback ไม่ควรกลืนไว้
+// งานที่ลงไว้ด้วย afterCommit จะรันเมื่อ transaction นอกสุด commit แล้วเท่านั้น
+func transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context, tx *gorm.DB) error) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx, state.tx.WithContext(ctx))
+	}
+	state := &txState{}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state), tx)
+	})
+	if err != nil {
+		return err
+	}
+	for _, run := range state.afterCommit {
+		run()
+	}
+	return nil
+}
+
+// เลื่อน fn ไปรันหลัง commit เช่นงานที่อ่านข้อมูลด้วย connection อื่นหรือลบไฟล์ ถ้าไม่อยู่ใน transaction จะรันทันที
+func afterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// transaction ของ sqlite เริ่มด้วย BEGIN IMMEDIATE transaction ที่สองจึงต้องรอตั้งแต่ตอนเริ่ม ไม่ใช่ตอนเขียน
+func TestSQLiteTransactionTakesWriteLock(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Create(&models.Category{Name: "Go"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	first := db.Begin()
+	if first.Error != nil {
+		t.Fatal(first.Error)
+	}
+	var category models.Category
+	if err := first.First(&category).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	began := make(chan error, 1)
+	go func() {
+		second := db.Begin()
+		if second.Error != nil {
+			began <- second.Error
+			return
+		}
+		// อ่านแล้วเขียนแบบเดียวกับ findOrCreateByName
+		var category models.Category
+		err := second.First(&category).Error
+		if err == nil {
+			err = second.Create(&models.Category{Name: "Web"}).Error
+		}
+		if err != nil {
+			second.Rollback()
+		} else {
+			err = second.Commit().Error
+		}
+		began <- err
+	}()
+
+	select {
+	case err := <-began:
+		t.Fatalf("second transaction did not wait for the first: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := first.Model(&category).Update("name", "Golang").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-began; err != nil {
+		t.Fatalf("second transaction: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.Category{}).Count(&count).Error; err != nil || count != 2 {
+		t.Fatalf("categories: %d, %v", count, err)
+	}
+}
+
+// Transaction ที่ซ้อนกันเข้าร่วม transaction นอกสุด error ของตัวในต้อง rollback ทั้งหมด
+func TestNestedTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	tx := NewTransactor(db)
+	categories := NewCategories(db)
+
+	errAbort := errors.New("abort")
+	err := tx.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := categories.FindOrCreate(ctx, "Outer"); err != nil {
+			return err
+		}
+		return tx.Transaction(ctx, func(ctx context.Context) error {
+			if _, err := categories.FindOrCreate(ctx, "Inner"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("transaction: %v", err)
+	}
+	all, err := categories.All(ctx)
+	if err != nil || len(all) != 0 {
+		t.Fatalf("categories after rollback: %+v, %v", all, err)
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"backend/models"
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository อ่านเขียนบัญชีผู้ใช้ อีเมลและ username เทียบแบบไม่สนตัวพิมพ์
+type UserRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	// FindByLogin หาบัญชีจากอีเมลหรือ username ที่ใช้เข้าสู่ระบบ
+	FindByLogin(ctx context.Context, emailOrUsername string) (*models.User, error)
+	EmailTaken(ctx context.Context, email string) (bool, error)
+	UsernameTaken(ctx context.Context, username string) (bool, error)
+	Create(ctx context.Context, user *models.User) error
+	Save(ctx context.Context, user *models.User) error
+}
+
+// GormUsers คือ UserRepository บน GORM
+type GormUsers struct {
+	DB *gorm.DB
+}
+
+// NewUsers สร้าง UserRepository บน db
+func NewUsers(db *gorm.DB) *GormUsers {
+	return &GormUsers{DB: db}
+}
+
+func (r *GormUsers) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := conn(ctx, r.DB).First(&user, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *GormUsers) FindByLogin(ctx context.Context, emailOrUsername string) (*models.User, error) {
+	login := strings.ToLower(emailOrUsername)
+	var user models.User
+	if err := conn(ctx, r.DB).Where("LOWER(email) = ? OR LOWER(username) = ?", login, login).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *GormUsers) EmailTaken(ctx context.Context, email string) (bool, error) {
+	return r.taken(ctx, "email", email)
+}
+
+func (r *GormUsers) UsernameTaken(ctx context.Context, username string) (bool, error) {
+	return r.taken(ctx, "username", username)
+}
+
+func (r *GormUsers) taken(ctx context.Context, column, value string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&models.User{}).Where("LOWER("+column+") = ?", strings.ToLower(value)).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormUsers) Create(ctx context.Context, user *models.User) error {
+	return conn(ctx, r.DB).Create(user).Error
+}
+
+func (r *GormUsers) Save(ctx context.Context, user *models.User) error {
+	return conn(ctx, r.DB).Save(user).Error
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend/controller"
+	"backend/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Admin Routes
+func AdminRoutes(app *fiber.App) {
+	admin := app.Group("/admin", middleware.Protected(), middleware.SessionOnly(), middleware.AdminOnly())
+
+	admin.Get("/login-attempts", controller.GetLoginAttempts)   // ดูประวัติการเข้าสู่ระบบ
+	admin.Post("/import/wordpress", controller.ImportWordPress) // นำเข้าบทความจาก WordPress
+	admin.Post("/import/medium", controller.ImportMedium)       // นำเข้าบทความจาก Medium
+}
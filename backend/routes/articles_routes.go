@@ -3,6 +3,7 @@ package routes
 import (
 	"backend/controller"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,13 +14,17 @@ import (
 func ArticleRoutes(app *fiber.App) {
 	articles := app.Group("/articles")
 
-	articles.Get("/my-articles", middleware.Protected(), controller.GetMyArticles) // ดูบทความที่ตัวเองเขียน
+	articles.Get("/my-articles", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesRead), controller.GetMyArticles) // ดูบทความที่ตัวเองเขียน
+	articles.Get("/export", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesRead), controller.ExportArticles) // ส่งออกบทความเป็นไฟล์ Markdown
 	articles.Get("/", controller.SearchArticlesTags)                                // ค้นหาบทความและแท็ก
 	articles.Get("/:slug", controller.GetArticleBySlug)                            	// ดูบทความตาม slug
+	articles.Get("/:slug/meta", controller.GetArticleMeta)                         	// meta สำหรับแสดงตัวอย่างลิงก์ (Open Graph / Twitter)
+	articles.Get("/:slug/og.png", controller.GetArticleOGImage)                    	// รูปการ์ดสำหรับแชร์ลิงก์
 
-	articles.Post("/", middleware.Protected(), controller.CreateArticle) 			// สร้างบทความ
-	articles.Put("/:slug", middleware.Protected(), controller.UpdateArticle) // แก้ไขบทความ
-	articles.Delete("/:slug", middleware.Protected(), controller.DeleteArticle) // ลบบทความ
+	articles.Post("/", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesWrite), controller.CreateArticle) 			// สร้างบทความ
+	articles.Post("/import", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesWrite), controller.ImportArticles) // นำเข้าบทความจากไฟล์ Markdown
+	articles.Put("/:slug", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesWrite), controller.UpdateArticle) // แก้ไขบทความ
+	articles.Delete("/:slug", middleware.Protected(), middleware.RequireScope(models.ScopeArticlesWrite), controller.DeleteArticle) // ลบบทความ
 
 }
 
@@ -4,6 +4,7 @@ package routes
 import (
 	"backend/controller"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -11,8 +12,8 @@ import (
 // Comment Routes
 func CommentRoutes(app *fiber.App) {
     articles := app.Group("/articles")
-    articles.Post("/:slug/comments", middleware.Protected(), controller.CreateComment)
+    articles.Post("/:slug/comments", middleware.Protected(), middleware.RequireScope(models.ScopeCommentsWrite), controller.CreateComment)
     articles.Get("/:slug/comments", controller.GetComments)
-	articles.Put("/:slug/comments/:commentId", middleware.Protected(), controller.UpdateComment)
-	articles.Delete("/:slug/comments/:commentId", middleware.Protected(), controller.DeleteComment)
+	articles.Put("/:slug/comments/:commentId", middleware.Protected(), middleware.RequireScope(models.ScopeCommentsWrite), controller.UpdateComment)
+	articles.Delete("/:slug/comments/:commentId", middleware.Protected(), middleware.RequireScope(models.ScopeCommentsWrite), controller.DeleteComment)
 }
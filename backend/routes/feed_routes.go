@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Feed Routes
+// :kind คือ category (id), tag (ชื่อแท็ก) หรือ author (username) เพิ่ม ?content=summary เพื่อส่งแค่คำอธิบาย
+func FeedRoutes(app *fiber.App) {
+	app.Get("/feed.xml", controller.GetRSSFeed)   // RSS ของทั้งเว็บ
+	app.Get("/atom.xml", controller.GetAtomFeed)  // Atom ของทั้งเว็บ
+	app.Get("/feed.json", controller.GetJSONFeed) // JSON Feed ของทั้งเว็บ
+
+	feeds := app.Group("/feeds/:kind/:value")
+	feeds.Get("/feed.xml", controller.GetRSSFeed)
+	feeds.Get("/atom.xml", controller.GetAtomFeed)
+	feeds.Get("/feed.json", controller.GetJSONFeed)
+}
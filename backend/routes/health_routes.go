@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"backend/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Health Routes
+func HealthRoutes(app *fiber.App) {
+	app.Get("/healthz", controller.Healthz) // liveness
+	app.Get("/readyz", controller.Readyz)   // readiness: ฐานข้อมูลและ migration
+}
+
+// Metrics Routes อยู่บน app ของ METRICS_PORT ไม่ใช่ app ของ API ที่ nginx ส่งต่อ
+func MetricsRoutes(app *fiber.App) {
+	app.Get("/metrics", controller.GetMetrics) // Prometheus
+}
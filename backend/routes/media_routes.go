@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controller"
+	"backend/middleware"
+	"backend/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Media Routes
+func MediaRoutes(app *fiber.App) {
+	media := app.Group("/media", middleware.Protected())
+
+	media.Get("/", middleware.RequireScope(models.ScopeArticlesRead), controller.ListMedia)          // คลังรูปของผู้ใช้
+	media.Post("/", middleware.RequireScope(models.ScopeArticlesWrite), controller.UploadMedia)      // อัปโหลดรูปสำหรับบทความ
+	media.Get("/:id", middleware.RequireScope(models.ScopeArticlesRead), controller.GetMedia)        // ดูข้อมูลรูป
+	media.Put("/:id", middleware.RequireScope(models.ScopeArticlesWrite), controller.UpdateMedia)    // แก้ไข alt text และ caption
+	media.Delete("/:id", middleware.RequireScope(models.ScopeArticlesWrite), controller.DeleteMedia) // ลบรูปที่ไม่ได้ใช้แล้ว
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Sitemap Routes
+func SitemapRoutes(app *fiber.App) {
+	app.Get("/robots.txt", controller.GetRobots)                      // robots.txt พร้อมลิงก์ไป sitemap
+	app.Get("/sitemap.xml", controller.GetSitemap)                    // sitemap หรือ sitemap index
+	app.Get("/sitemaps/sitemap-:page.xml", controller.GetSitemapPage) // ไฟล์ย่อยของ sitemap index
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Upload Routes
+func UploadRoutes(app *fiber.App) {
+	app.Get("/uploads/*", controller.ServeUpload) // ไฟล์ที่อัปโหลด ไฟล์ใน private/ ไม่เปิดให้ดาวน์โหลด
+}
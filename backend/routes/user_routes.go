@@ -3,6 +3,7 @@ package routes
 import (
 	"backend/controller"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,6 +16,10 @@ func AuthRoutes(app *fiber.App) {
 	auth := app.Group("/auth")
 	auth.Post("/register", controller.Register)
 	auth.Post("/login", controller.Login)
+
+	auth.Get("/oidc/providers", controller.OIDCProviders)           // รายชื่อผู้ให้บริการ login ภายนอก
+	auth.Get("/oidc/:provider/login", controller.OIDCLogin)         // เริ่ม login กับผู้ให้บริการ
+	auth.Get("/oidc/:provider/callback", controller.OIDCCallback)   // รับผลจากผู้ให้บริการ
 	
 }
 
@@ -22,6 +27,20 @@ func AuthRoutes(app *fiber.App) {
 func UserRoutes(r fiber.Router) {
 	users := r.Group("/user", middleware.Protected())
 
-	users.Get("/", controller.Profile) // ดูข้อมูลผู้ใช้งานปัจจุบัน
-	users.Put("/", controller.UpdateProfile) // แก้ไขข้อมูลผู้ใช้งานปัจจุบัน
+	users.Get("/", middleware.RequireScope(models.ScopeProfileRead), controller.Profile) // ดูข้อมูลผู้ใช้งานปัจจุบัน
+	users.Put("/", middleware.RequireScope(models.ScopeProfileWrite), controller.UpdateProfile) // แก้ไขข้อมูลผู้ใช้งานปัจจุบัน
+
+	tokens := users.Group("/tokens", middleware.SessionOnly())
+	tokens.Get("/", controller.ListTokens)       // ดู personal access token
+	tokens.Post("/", controller.CreateToken)     // สร้าง personal access token
+	tokens.Delete("/:id", controller.RevokeToken) // ยกเลิก personal access token
+
+	sessions := users.Group("/sessions", middleware.SessionOnly())
+	sessions.Get("/", controller.ListSessions)           // ดูอุปกรณ์ที่ login อยู่
+	sessions.Delete("/", controller.RevokeOtherSessions) // ออกจากระบบทุกเครื่องยกเว้นเครื่องนี้
+	sessions.Delete("/:id", controller.RevokeSession)    // ออกจากระบบจากเครื่องที่เลือก
+
+	users.Get("/export", middleware.SessionOnly(), controller.ExportAccount)              // ดาวน์โหลดข้อมูลส่วนตัวทั้งหมด
+	users.Post("/deletion", middleware.SessionOnly(), controller.RequestAccountDeletion)  // ขอลบบัญชี
+	users.Delete("/deletion", middleware.SessionOnly(), controller.CancelAccountDeletion) // ยกเลิกการขอลบบัญชี
 }
\ No newline at end of file
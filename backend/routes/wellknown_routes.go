@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Well-Known Routes
+func WellKnownRoutes(app *fiber.App) {
+	wellKnown := app.Group("/.well-known")
+	wellKnown.Get("/jwks.json", controller.GetJWKS) // public key สำหรับตรวจ JWT
+}
@@ -3,13 +3,52 @@ package seed
 import (
 	"backend/database"
 	"backend/models"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-func SeedCategories() {
+// Set คือชุดข้อมูลตั้งต้นที่เลือกรันได้ด้วย backend seed <name> รันซ้ำได้โดยไม่สร้างข้อมูลซ้ำ
+type Set struct {
+	Name        string
+	Description string
+	Run         func() error
+}
+
+// Sets คือชุดข้อมูลทั้งหมด เรียงตามลำดับที่แสดงใน usage
+var Sets = []Set{
+	{Name: "taxonomy", Description: "default categories and tags", Run: seedTaxonomy},
+	{Name: "demo", Description: "taxonomy, admin@example.com (password123) and five articles", Run: seedDemo},
+}
+
+// Run รันชุดข้อมูลตามชื่อ
+func Run(name string) error {
+	for _, set := range Sets {
+		if set.Name == name {
+			return set.Run()
+		}
+	}
+	return fmt.Errorf("unknown seed set %q", name)
+}
+
+func seedTaxonomy() error {
+	if err := SeedCategories(); err != nil {
+		return err
+	}
+	return SeedTags()
+}
+
+func seedDemo() error {
+	if err := seedTaxonomy(); err != nil {
+		return err
+	}
+	return SeedUserAndArticles()
+}
+
+func SeedCategories() error {
 	categories := []models.Category{
 		{Name: "Technology"},
 		{Name: "Health"},
@@ -19,16 +58,15 @@ func SeedCategories() {
 	}
 
 	for _, category := range categories {
-		var existing models.Category
-		database.DB.Where("name = ?", category.Name).First(&existing)
-		if existing.ID == 0 {
-			database.DB.Create(&category)
+		if err := database.DB.Where("name = ?", category.Name).FirstOrCreate(&category).Error; err != nil {
+			return fmt.Errorf("seed category %s: %w", category.Name, err)
 		}
 	}
-	log.Println("✅ Seeded categories")
+	slog.Info("seeded categories")
+	return nil
 }
 
-func SeedTags() {
+func SeedTags() error {
 	tags := []models.Tags{
 		{Name: "Go"},
 		{Name: "Docker"},
@@ -37,18 +75,20 @@ func SeedTags() {
 	}
 
 	for _, tag := range tags {
-		var existing models.Tags
-		database.DB.Where("name = ?", tag.Name).First(&existing)
-		if existing.ID == 0 {
-			database.DB.Create(&tag)
+		if err := database.DB.Where("name = ?", tag.Name).FirstOrCreate(&tag).Error; err != nil {
+			return fmt.Errorf("seed tag %s: %w", tag.Name, err)
 		}
 	}
-	log.Println("✅ Seeded tags")
+	slog.Info("seeded tags")
+	return nil
 }
 
-func SeedUserAndArticles() {
+func SeedUserAndArticles() error {
 	// Create user
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), 14)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), 14)
+	if err != nil {
+		return err
+	}
 	user := models.User{
 		Username:     "admin",
 		FirstName:    "Admin",
@@ -56,21 +96,25 @@ func SeedUserAndArticles() {
 		Nickname:     "Boss",
 		Email:        "admin@example.com",
 		PasswordHash: string(hashedPassword),
+		Role:         models.RoleAdmin,
+	}
+	if err := database.DB.Where("email = ?", user.Email).FirstOrCreate(&user).Error; err != nil {
+		return fmt.Errorf("seed user %s: %w", user.Email, err)
 	}
-	database.DB.Where("email = ?", user.Email).FirstOrCreate(&user)
 
 	// Get category
 	var techCat models.Category
 	if err := database.DB.First(&techCat, "name = ?", "Technology").Error; err != nil {
-		log.Println("❌ Category not found, skipping article seeding")
-		return
+		return fmt.Errorf("category Technology not found, run the taxonomy set first: %w", err)
 	}
 
 	// Get tags
 	var tags []models.Tags
-	if err := database.DB.Find(&tags).Error; err != nil || len(tags) < 4 {
-		log.Println("❌ Not enough tags found")
-		return
+	if err := database.DB.Where("name IN ?", []string{"Go", "Docker", "API", "Kubernetes"}).Order("id").Find(&tags).Error; err != nil {
+		return err
+	}
+	if len(tags) < 4 {
+		return errors.New("not enough tags found, run the taxonomy set first")
 	}
 
 	// Article seed data
@@ -88,28 +132,30 @@ func SeedUserAndArticles() {
 	}
 
 	for _, item := range articleData {
-		var existing models.Article
-		database.DB.Where("slug = ?", item.Slug).First(&existing)
-		if existing.ID == 0 {
-			article := models.Article{
-				Title:      item.Title,
-				Slug:       item.Slug,
-				Content:    item.Content,
-				AuthorID:   user.ID,
-				CategoryID: techCat.ID,
-			}
-			if err := database.DB.Create(&article).Error; err != nil {
-				log.Println("❌ Failed to create article:", article.Slug)
-				continue
-			}
-
-			// ✅ ผูกแท็กหลายตัว
-			if err := database.DB.Model(&article).Association("Tags").Replace(&item.TagSet); err != nil {
-				log.Println("❌ Failed to associate tags for", article.Slug)
-			}
+		var count int64
+		if err := database.DB.Model(&models.Article{}).Where("slug = ?", item.Slug).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		article := models.Article{
+			Title:      item.Title,
+			Slug:       item.Slug,
+			Content:    item.Content,
+			AuthorID:   user.ID,
+			CategoryID: techCat.ID,
+		}
+		if err := database.DB.Create(&article).Error; err != nil {
+			return fmt.Errorf("seed article %s: %w", article.Slug, err)
+		}
+
+		// ✅ ผูกแท็กหลายตัว
+		if err := database.DB.Model(&article).Association("Tags").Replace(&item.TagSet); err != nil {
+			return fmt.Errorf("associate tags of %s: %w", article.Slug, err)
 		}
 	}
 
-	log.Println("✅ Seeded user and articles with tags")
+	slog.Info("seeded user and articles with tags")
+	return nil
 }
-
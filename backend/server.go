@@ -0,0 +1,149 @@
+package main
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/jobs"
+	"backend/lifecycle"
+	"backend/metrics"
+	"backend/seed"
+	"backend/service"
+	"backend/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// serve เริ่ม server ตามลำดับแล้วรอจนได้ SIGTERM หรือ SIGINT จากนั้นรอ request ที่ค้างอยู่และงานเบื้องหลัง
+// ไม่เกิน SHUTDOWN_TIMEOUT แล้วปิดฐานข้อมูล ส่งสัญญาณซ้ำระหว่างรอเพื่อปิดทันที
+func serve(cfg *config.Config, app *fiber.App) error {
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	// http และ metrics ส่ง error ได้คนละครั้ง
+	serveErr := make(chan error, 2)
+	var server lifecycle.Manager
+	server.Add(startupSteps(cfg, app, serveErr)...)
+
+	err := server.Start(ctx)
+	if err == nil {
+		slog.Info("server is ready", "addr", cfg.Server.Addr(), "metrics_port", cfg.Server.MetricsPort)
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down", "timeout", cfg.Server.ShutdownTimeout)
+		case err = <-serveErr:
+			if err == nil {
+				err = errors.New("http server stopped unexpectedly")
+			}
+		}
+	}
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	return errors.Join(err, server.Stop(shutdownCtx))
+}
+
+// ลำดับการเริ่ม ปิดย้อนลำดับ: หยุดรับ request ก่อน แล้วหยุดงานเบื้องหลัง ปิดฐานข้อมูลเป็นอย่างสุดท้าย
+func startupSteps(cfg *config.Config, app *fiber.App, serveErr chan<- error) []lifecycle.Step {
+	var stopJobs func(ctx context.Context) error
+
+	steps := []lifecycle.Step{
+		{
+			Name: "database",
+			Start: func(ctx context.Context) error {
+				if err := database.Connect(cfg.Database); err != nil {
+					return err
+				}
+				sqlDB, err := database.DB.DB()
+				if err != nil {
+					return err
+				}
+				metrics.RegisterDB(sqlDB, cfg.Database.Name) // สถิติของ connection pool ใน /metrics
+				service.InitServices()                       // domain service บน repository ของฐานข้อมูล
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return database.Close()
+			},
+		},
+		{
+			Name: "migrations",
+			Start: func(ctx context.Context) error {
+				if !cfg.Database.AutoMigrate {
+					// รันเองด้วย cmd/migrate ระหว่างนี้ /readyz ตอบ 503
+					if err := database.CheckMigrations(ctx, database.DB); err != nil {
+						slog.Warn("database is not up to date, run migrate up", "error", err)
+					}
+					return nil
+				}
+				applied, err := database.MigrateUp(database.DB)
+				for _, m := range applied {
+					slog.Info("applied migration", "version", m.Version, "name", m.Name)
+				}
+				return err
+			},
+		},
+		{
+			Name: "storage", // ที่เก็บไฟล์อัปโหลด (local หรือ s3 ตาม STORAGE_DRIVER)
+			Start: func(ctx context.Context) error {
+				return storage.Init(cfg.Storage)
+			},
+		},
+	}
+
+	if cfg.Database.Seed {
+		steps = append(steps, lifecycle.Step{
+			Name: "seed",
+			Start: func(ctx context.Context) error {
+				return seed.Run("demo")
+			},
+		})
+	}
+
+	steps = append(steps,
+		lifecycle.Step{
+			// งานเบื้องหลัง เช่นลบบัญชีที่พ้นช่วงผ่อนผัน เริ่มหลัง seed เพื่อให้ sitemap เห็นบทความตั้งต้น
+			Name: "jobs",
+			Start: func(ctx context.Context) error {
+				stopJobs = jobs.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				return stopJobs(ctx)
+			},
+		},
+	)
+
+	if cfg.Server.MetricsPort != 0 {
+		// เริ่มก่อน API และปิดทีหลัง ให้ Prometheus เก็บตัวเลขระหว่างปิดได้
+		steps = append(steps, listenStep("metrics", newMetricsApp(), cfg.Server.MetricsAddr(), serveErr))
+	}
+	return append(steps, listenStep("http", app, cfg.Server.Addr(), serveErr))
+}
+
+// listenStep รับ request ของ app ที่ addr
+func listenStep(name string, app *fiber.App, addr string, serveErr chan<- error) lifecycle.Step {
+	return lifecycle.Step{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			// เปิด port ก่อน เพื่อให้ port ที่ถูกใช้อยู่เป็น error ของขั้นนี้แทนที่จะพังทีหลัง
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			go func() { serveErr <- app.Listener(ln) }()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// หยุดรับการเชื่อมต่อใหม่ทันที แล้วรอ request ที่กำลังทำอยู่จนจบหรือ ctx หมดเวลา
+			return app.ShutdownWithContext(ctx)
+		},
+	}
+}
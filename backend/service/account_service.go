@@ -0,0 +1,222 @@
+package service
+
+import (
+	"archive/zip"
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/passwords"
+	"backend/storage"
+	"backend/utils"
+	"backend/validation"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ระยะเวลาผ่อนผันก่อนลบบัญชีจริง ผู้ใช้ยกเลิกได้ภายในช่วงนี้
+func accountDeletionGrace() time.Duration {
+	return config.Get().AccountDeletionGrace
+}
+
+type exportedComment struct {
+	ID          uint      `json:"id"`
+	Content     string    `json:"content"`
+	ArticleSlug string    `json:"article_slug"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Export Personal Data
+func HandleExportAccount(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	user, err := composables.GetUserByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("User not found"))
+	}
+	user.PasswordHash = ""
+
+	var articles []models.Article
+	if err := database.DB.Preload("Category").Preload("Tags").
+		Where("author_id = ?", userID).Order("created_at").Find(&articles).Error; err != nil {
+		return serverError(c, err, "Failed to export articles")
+	}
+
+	var comments []models.Comment
+	if err := database.DB.Preload("Article").
+		Where("user_id = ?", userID).Order("created_at").Find(&comments).Error; err != nil {
+		return serverError(c, err, "Failed to export comments")
+	}
+	exportedComments := make([]exportedComment, 0, len(comments))
+	for _, cm := range comments {
+		exportedComments = append(exportedComments, exportedComment{
+			ID:          cm.ID,
+			Content:     cm.Content,
+			ArticleSlug: cm.Article.Slug,
+			CreatedAt:   cm.CreatedAt,
+			UpdatedAt:   cm.UpdatedAt,
+		})
+	}
+
+	var identities []models.UserIdentity
+	if err := database.DB.Where("user_id = ?", userID).Find(&identities).Error; err != nil {
+		return serverError(c, err, "Failed to export linked accounts")
+	}
+	var tokens []models.PersonalAccessToken
+	if err := database.DB.Where("user_id = ?", userID).Find(&tokens).Error; err != nil {
+		return serverError(c, err, "Failed to export access tokens")
+	}
+	var sessions []models.Session
+	if err := database.DB.Where("user_id = ?", userID).Find(&sessions).Error; err != nil {
+		return serverError(c, err, "Failed to export sessions")
+	}
+
+	// เตรียมทุกอย่างที่ผิดพลาดได้ก่อนเริ่มส่ง หลังจากนั้น status 200 ถูกส่งไปแล้วเปลี่ยนไม่ได้
+	documents := []exportDocument{
+		{name: "profile.json", value: fiber.Map{
+			"user":                   user,
+			"linked_identities":      identities,
+			"personal_access_tokens": tokens,
+			"sessions":               sessions,
+		}},
+		{name: "articles.json", value: articles},
+		{name: "comments.json", value: exportedComments},
+	}
+	for i := range documents {
+		if documents[i].data, err = json.MarshalIndent(documents[i].value, "", "  "); err != nil {
+			return serverError(c, err, "Failed to build export")
+		}
+	}
+	uploads, err := composables.UserUploadFiles(userID)
+	if err != nil {
+		return serverError(c, err, "Failed to export uploaded files")
+	}
+
+	filename := fmt.Sprintf("boblog-export-%s-%s.zip", user.Username, time.Now().Format("20060102"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// ไฟล์อัปโหลดอาจใหญ่ จึงเขียน ZIP ลง response ทีละไฟล์แทนการสร้างทั้งก้อนในหน่วยความจำ
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeAccountExport(ctx, w, documents, uploads); err != nil {
+			// ZIP ที่หยุดกลางทางไม่มี central directory จึงเปิดไม่ได้ ผู้ใช้ไม่ได้ไฟล์ที่ดูเหมือนครบแต่ขาดข้อมูล
+			slog.ErrorContext(ctx, "failed to stream account export", "user_id", userID, "error", err)
+		}
+	})
+	return nil
+}
+
+type exportDocument struct {
+	name  string
+	value interface{}
+	data  []byte
+}
+
+func writeAccountExport(ctx context.Context, w io.Writer, documents []exportDocument, uploads []storage.Object) error {
+	archive := zip.NewWriter(w)
+	for _, doc := range documents {
+		fw, err := archive.CreateHeader(zipHeader(doc.name))
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(doc.data); err != nil {
+			return err
+		}
+	}
+	for _, obj := range uploads {
+		if err := addFileToZip(ctx, archive, obj.Key, "files/"+obj.Key); err != nil {
+			return fmt.Errorf("add %s: %w", obj.Key, err)
+		}
+	}
+	return archive.Close()
+}
+
+func addFileToZip(ctx context.Context, archive *zip.Writer, key, name string) error {
+	f, _, err := storage.Default().Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := archive.CreateHeader(zipHeader(name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func zipHeader(name string) *zip.FileHeader {
+	return &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()}
+}
+
+// Request Account Deletion
+func HandleRequestAccountDeletion(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	user, err := composables.GetUserByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("User not found"))
+	}
+	if user.Email == models.DeletedUserEmail {
+		return c.Status(403).JSON(utils.ErrorResponse("This account cannot be deleted"))
+	}
+
+	var input validation.DeleteAccountInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid input format"))
+	}
+	if errs := validation.ValidateStructDeleteAccount(input); errs != nil {
+		return c.Status(400).JSON(fiber.Map{"errors": errs})
+	}
+	if user.PasswordHash != "" && !passwords.Check(input.Password, user.PasswordHash) {
+		return c.Status(400).JSON(fiber.Map{"errors": map[string]string{"password": "Password is incorrect"}})
+	}
+	if user.PasswordHash == "" && input.Confirm != user.Username {
+		return c.Status(400).JSON(fiber.Map{"errors": map[string]string{"confirm": "Type your username to confirm"}})
+	}
+
+	scheduledAt := time.Now().Add(accountDeletionGrace())
+	if err := database.DB.Model(user).Updates(map[string]interface{}{
+		"deletion_mode":         input.Mode,
+		"deletion_scheduled_at": scheduledAt,
+	}).Error; err != nil {
+		return serverError(c, err, "Failed to schedule account deletion")
+	}
+
+	return c.Status(202).JSON(utils.SuccessResponse(fiber.Map{
+		"mode":         input.Mode,
+		"scheduled_at": scheduledAt,
+	}, "Account deletion scheduled, you can cancel it until then"))
+}
+
+// Cancel Account Deletion
+func HandleCancelAccountDeletion(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	result := database.DB.Model(&models.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL", userID).
+		Updates(map[string]interface{}{"deletion_mode": nil, "deletion_scheduled_at": nil})
+	if result.Error != nil {
+		return serverError(c, result.Error, "Failed to cancel account deletion")
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(404).JSON(utils.ErrorResponse("No account deletion is scheduled"))
+	}
+	return c.JSON(utils.SuccessResponse(nil, "Account deletion cancelled"))
+}
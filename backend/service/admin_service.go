@@ -0,0 +1,61 @@
+package service
+
+import (
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Get Login Attempts (audit log)
+func HandleGetLoginAttempts(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	tx := database.DB.Model(&models.LoginAttempt{})
+	if identifier := c.Query("identifier"); identifier != "" {
+		tx = tx.Where("identifier = ?", identifier)
+	}
+	if ip := c.Query("ip"); ip != "" {
+		tx = tx.Where("ip = ?", ip)
+	}
+	if userID := c.QueryInt("user_id"); userID > 0 {
+		tx = tx.Where("user_id = ?", userID)
+	}
+	switch c.Query("result") {
+	case "success":
+		tx = tx.Where("success = ?", true)
+	case "failed":
+		tx = tx.Where("success = ? AND blocked = ?", false, false)
+	case "blocked":
+		tx = tx.Where("blocked = ?", true)
+	}
+
+	// Session ทำให้ใช้ query เดิมซ้ำได้ทั้งตอนนับและตอนดึงข้อมูล
+	tx = tx.Session(&gorm.Session{})
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return serverError(c, err, "Failed to load login attempts")
+	}
+
+	var attempts []models.LoginAttempt
+	if err := tx.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&attempts).Error; err != nil {
+		return serverError(c, err, "Failed to load login attempts")
+	}
+
+	return c.JSON(utils.SuccessResponse(fiber.Map{
+		"items": attempts,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	}, "Login attempts retrieved"))
+}
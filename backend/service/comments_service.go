@@ -1,15 +1,16 @@
 package service
 
 import (
-    "backend/database"
-	"backend/models"
-	"time"
+	"backend/composables"
+	"backend/domain"
+	"backend/metrics"
 	"github.com/gofiber/fiber/v2"
-    "strings"
+	"errors"
 	"net/url"
+	"strconv"
 	"backend/utils"
 )
- 
+
 
 // Get Comments
 func HandleGetComments(c *fiber.Ctx) error {
@@ -20,21 +21,9 @@ func HandleGetComments(c *fiber.Ctx) error {
     if err != nil {
         return c.Status(400).JSON(utils.ErrorResponse("Invalid slug encoding"))
     }
-	// วิธีการใช้ var , := 
-    var article models.Article
-	//  ORM 
-    if err := database.DB.Where("slug = ?", slug).First(&article).Error; err != nil {
-        return c.Status(404).JSON(utils.ErrorResponse("Article not found"))
-    }
-    var comments []models.Comment
-    if err := database.DB.
-        Preload("User").
-        Order("created_at desc").
-		// SQL 
-        Where("article_id = ?", article.ID).
-        Find(&comments).Error; err != nil {
-		// Find & Frist 
-        return c.Status(500).JSON(utils.ErrorResponse("Failed to fetch comments"))
+    comments, err := commentService.List(c.UserContext(), slug)
+    if err != nil {
+        return domainError(c, err, "Article not found", "", "Failed to fetch comments")
     }
     return c.JSON(utils.SuccessResponse(comments, "get comments success"))
 }
@@ -43,17 +32,12 @@ func HandleGetComments(c *fiber.Ctx) error {
 func HandleCreateComment(c *fiber.Ctx) error {
 
 	slugEncoded := c.Params("slug")
-			
+
 	slug, err := url.QueryUnescape(slugEncoded)
 	if err != nil {
 		return c.Status(400).JSON(utils.ErrorResponse("Invalid slug encoding"))
 	}
 
-	var article models.Article
-	if err := database.DB.Where("slug = ?", slug).First(&article).Error; err != nil {
-		return c.Status(404).JSON(utils.ErrorResponse("Article not found"))
-	}
-
 	var input struct {
 		Content string `json:"content"`
 	}
@@ -61,28 +45,19 @@ func HandleCreateComment(c *fiber.Ctx) error {
 		return c.Status(400).JSON(utils.ErrorResponse("Invalid request body"))
 	}
 
-	if strings.TrimSpace(input.Content) == "" {
-		return c.Status(400).JSON(utils.ErrorResponse("Content is required"))
-	}
-
 	userIDRaw := c.Locals("userID")
 	userID, ok := userIDRaw.(uint)
 	if !ok {
 		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
 	}
 
-
-	comment := models.Comment{
-		Content:   input.Content,
-		ArticleID: article.ID,
-		UserID:    userID,
-		CreatedAt: time.Now(),
-	}
-
-	if err := database.DB.Create(&comment).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to create comment"))
+	comment, err := commentService.Create(c.UserContext(), userID, slug, input.Content)
+	if err != nil {
+		return commentError(c, err, "Article not found", "Failed to create comment")
 	}
+	metrics.CommentsCreated.Inc()
 
+	c.Set(fiber.HeaderETag, composables.VersionETag(comment.Version))
 	return c.JSON(comment)
 }
 
@@ -99,39 +74,26 @@ func HandleUpdateComment(c *fiber.Ctx) error {
 		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
 	}
 
-	var comment models.Comment
-	if err := database.DB.Where("id = ?", commentID).First(&comment).Error; err != nil {
-		return c.Status(404).JSON(utils.ErrorResponse("Comment not found"))
-	}
-
-	if comment.UserID != userID {
-		return c.Status(403).JSON(utils.ErrorResponse("Forbidden"))
-	}
-
 	var input struct {
 		Content string `json:"content"`
+		Version *uint  `json:"version"`
 	}
 	if err := c.BodyParser(&input); err != nil {
 		return c.Status(400).JSON(utils.ErrorResponse("Invalid request body"))
 	}
+	input.Version = ifMatchVersion(c, input.Version)
 
-	if strings.TrimSpace(input.Content) == "" {
-		return c.Status(400).JSON(utils.ErrorResponse("Content is required"))
-	}
-	
-	comment.Content = input.Content
-	comment.UpdatedAt = time.Now()
-
-	if err := database.DB.Save(&comment).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to update comment"))
+	id, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Comment not found"))
 	}
-
-	
-	//  โหลดข้อมูล User เพื่อ return กลับไป
-	if err := database.DB.Preload("User").First(&comment, commentID).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to update comment"))
+	//  คอมเมนต์ที่ได้มีข้อมูล User มาด้วย
+	comment, err := commentService.Update(c.UserContext(), userID, uint(id), input.Content, input.Version)
+	if err != nil {
+		return commentError(c, err, "Comment not found", "Failed to update comment")
 	}
 
+	c.Set(fiber.HeaderETag, composables.VersionETag(comment.Version))
 	return c.JSON(comment)
 }
 
@@ -148,21 +110,23 @@ func HandleDeleteComment(c *fiber.Ctx) error {
 		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
 	}
 
-	var comment models.Comment
-	if err := database.DB.Where("id = ?", commentID).First(&comment).Error; err != nil {
+	id, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
 		return c.Status(404).JSON(utils.ErrorResponse("Comment not found"))
 	}
-
-	if comment.UserID != userID {
-		return c.Status(403).JSON(utils.ErrorResponse("Forbidden"))
+	if err := commentService.Delete(c.UserContext(), userID, uint(id)); err != nil {
+		return commentError(c, err, "Comment not found", "Failed to delete comment")
 	}
 
+	return c.JSON(utils.SuccessResponse(nil, "delete comment success"))
+}
 
-	if err := database.DB.Delete(&comment).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to delete comment"))
+// คอมเมนต์ว่างตอบ 400 ส่วน error อื่นเหมือน handler ทั่วไป
+func commentError(c *fiber.Ctx, err error, notFound, failed string) error {
+	if errors.Is(err, domain.ErrEmptyContent) {
+		return c.Status(400).JSON(utils.ErrorResponse("Content is required"))
 	}
-
-	return c.JSON(utils.SuccessResponse(nil, "delete comment success"))
+	return domainError(c, err, notFound, "Forbidden", failed)
 }
 
 
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/domain"
+	"backend/repository"
+	"backend/utils"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// domain service ที่ handler ใช้ ถูกผูกกับ repository บน GORM ใน InitServices
+var (
+	articleService  *domain.ArticleService
+	commentService  *domain.CommentService
+	tagService      *domain.TagService
+	categoryService *domain.CategoryService
+	userService     *domain.UserService
+)
+
+// InitServices สร้าง domain service บน database.DB ต้องเรียกหลัง database.Connect
+func InitServices() {
+	articles := repository.NewArticles(database.DB, composables.ArticleHooks)
+	tags := repository.NewTags(database.DB)
+	categories := repository.NewCategories(database.DB)
+
+	articleService = &domain.ArticleService{
+		Articles:   articles,
+		Tags:       tags,
+		Categories: categories,
+		Tx:         repository.NewTransactor(database.DB),
+	}
+	commentService = &domain.CommentService{Articles: articles, Comments: repository.NewComments(database.DB)}
+	tagService = &domain.TagService{Tags: tags}
+	categoryService = &domain.CategoryService{Categories: categories}
+	userService = &domain.UserService{Users: repository.NewUsers(database.DB)}
+}
+
+// อ่าน version จาก If-Match แทน version ใน body ถ้ามี header นี้
+func ifMatchVersion(c *fiber.Ctx, body *uint) *uint {
+	if composables.IfMatchAny(c) {
+		version := domain.AnyVersion
+		return &version
+	}
+	if version, ok := composables.IfMatchVersion(c); ok {
+		return &version
+	}
+	return body
+}
+
+// ตอบ error จาก domain service ด้วย status ที่ตรงกัน ข้อความของ 404, 403 และ 500 เป็นของแต่ละ handler
+func domainError(c *fiber.Ctx, err error, notFound, forbidden, failed string) error {
+	var invalid *domain.ValidationError
+	var conflict *domain.ConflictError
+	switch {
+	case errors.As(err, &invalid):
+		return c.Status(400).JSON(fiber.Map{"errors": invalid.Fields})
+	case errors.As(err, &conflict):
+		// ส่งข้อมูลล่าสุดกลับไปให้ผู้แก้ไขรวมการแก้ของตัวเองแล้วส่งใหม่ด้วย version นี้
+		status := fiber.StatusConflict
+		if c.Get(fiber.HeaderIfMatch) != "" {
+			status = fiber.StatusPreconditionFailed
+		}
+		c.Set(fiber.HeaderETag, composables.VersionETag(conflict.Version))
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": "it was changed by someone else, current version is " + strconv.FormatUint(uint64(conflict.Version), 10),
+			"version": conflict.Version,
+			"data":    conflict.Current,
+		})
+	case errors.Is(err, domain.ErrVersionRequired):
+		return c.Status(fiber.StatusPreconditionRequired).JSON(utils.ErrorResponse("If-Match header or version is required"))
+	case errors.Is(err, domain.ErrNotFound):
+		return c.Status(404).JSON(utils.ErrorResponse(notFound))
+	case errors.Is(err, domain.ErrForbidden):
+		return c.Status(403).JSON(utils.ErrorResponse(forbidden))
+	}
+	return serverError(c, err, failed)
+}
+
+// ตอบ 500 ด้วยข้อความของ handler และบันทึก error จริงไว้ใน log
+func serverError(c *fiber.Ctx, err error, message string) error {
+	slog.ErrorContext(c.UserContext(), message, "error", err, "method", c.Method(), "path", c.Path())
+	return c.Status(500).JSON(utils.ErrorResponse(message))
+}
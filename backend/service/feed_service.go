@@ -0,0 +1,134 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/feed"
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// จำนวนบทความล่าสุดใน feed
+const feedLimit = 20
+
+// โหมดเนื้อหาของ feed เลือกได้ด้วย ?content=full|summary ค่าเริ่มต้นอ่านจาก FEED_CONTENT
+func feedContentMode(c *fiber.Ctx) string {
+	mode := c.Query("content", config.Get().Site.FeedContent)
+	if mode == "summary" {
+		return "summary"
+	}
+	return "full"
+}
+
+// Get Feed (RSS, Atom หรือ JSON Feed ของทั้งเว็บ หรือเฉพาะหมวดหมู่ แท็ก และผู้เขียน)
+func HandleGetFeed(c *fiber.Ctx, format string) error {
+	value, err := url.PathUnescape(c.Params("value"))
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid feed path"))
+	}
+
+	site := siteName()
+	f := &feed.Feed{
+		Title:       site,
+		Description: "Latest articles from " + site,
+		Link:        composables.SiteURL() + "/articles",
+		FeedURL:     composables.SiteURL() + c.OriginalURL(),
+		Language:    "th",
+	}
+	var filter repository.ArticleFilter
+
+	switch c.Params("kind") {
+	case "":
+	case "category":
+		var category models.Category
+		if err := database.DB.First(&category, "id = ?", value).Error; err != nil {
+			return c.Status(404).JSON(utils.ErrorResponse("Category not found"))
+		}
+		filter.CategoryID = value
+		f.Title = site + ": " + category.Name
+		f.Description = "Articles in " + category.Name
+	case "tag":
+		var tag models.Tags
+		if err := database.DB.Where("LOWER(name) = LOWER(?)", value).First(&tag).Error; err != nil {
+			return c.Status(404).JSON(utils.ErrorResponse("Tag not found"))
+		}
+		filter.Tag = tag.Name
+		f.Title = site + ": #" + tag.Name
+		f.Description = "Articles tagged " + tag.Name
+	case "author":
+		var author models.User
+		if err := database.DB.Where("username = ?", value).First(&author).Error; err != nil {
+			return c.Status(404).JSON(utils.ErrorResponse("Author not found"))
+		}
+		filter.Author = author.Username
+		name := composables.ArticleAuthorName(&models.Article{Author: author})
+		f.Title = site + ": " + name
+		f.Description = "Articles by " + name
+	default:
+		return c.Status(404).JSON(utils.ErrorResponse("Feed not found"))
+	}
+
+	var articles []models.Article
+	if err := composables.ArticleListQuery(filter).Limit(feedLimit).Find(&articles).Error; err != nil {
+		return serverError(c, err, "Failed to load feed")
+	}
+
+	full := feedContentMode(c) == "full"
+	for i := range articles {
+		article := &articles[i]
+		item := feed.Item{
+			ID:        composables.ArticleURL(article),
+			Title:     article.Title,
+			Link:      composables.ArticleURL(article),
+			Summary:   composables.ArticleDescription(article),
+			Author:    composables.ArticleAuthorName(article),
+			Published: article.CreatedAt,
+			Updated:   article.UpdatedAt,
+		}
+		if article.Category.Name != "" {
+			item.Categories = append(item.Categories, article.Category.Name)
+		}
+		for _, tag := range article.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+		if article.Cover != nil {
+			item.Image = composables.AbsoluteURL(article.Cover.URL)
+		}
+		if full {
+			item.Content = composables.AbsoluteHTML(article.Content)
+		}
+		if article.UpdatedAt.After(f.Updated) {
+			f.Updated = article.UpdatedAt
+		}
+		f.Items = append(f.Items, item)
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	data, contentType, err := feed.Encode(f, format)
+	if err != nil {
+		return serverError(c, err, "Failed to build feed")
+	}
+
+	// ETag มาจากเนื้อหาที่ส่งจริง การแก้ชื่อผู้เขียนหรือหมวดหมู่ก็ทำให้ feed เปลี่ยนด้วย
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderLastModified, f.Updated.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if composables.NotModified(c, etag, f.Updated) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(data)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"backend/database"
+	"backend/metrics"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// เวลาที่รอฐานข้อมูลตอบใน /readyz ก่อนถือว่ายังไม่พร้อม
+const readyTimeout = 2 * time.Second
+
+// Liveness process ยังตอบ request ได้
+// ไม่ตรวจฐานข้อมูล เพื่อไม่ให้ทุก instance ถูก restart พร้อมกันเมื่อฐานข้อมูลล่ม
+func HandleHealthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readiness เชื่อมต่อฐานข้อมูลได้และรัน migration ครบแล้ว ถ้าไม่พร้อมตอบ 503 ให้ไม่ส่ง request มาที่ instance นี้
+// รายละเอียดของ error อยู่ใน log เท่านั้น เพราะ path นี้เปิดผ่าน nginx ได้
+func HandleReadyz(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), readyTimeout)
+	defer cancel()
+
+	checks := fiber.Map{"database": "ok", "migrations": "ok"}
+	ready := true
+	fail := func(check string, err error) {
+		slog.WarnContext(ctx, "readiness check failed", "check", check, "error", err)
+		checks[check] = "failed"
+		ready = false
+	}
+
+	sqlDB, err := database.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		fail("database", err)
+		checks["migrations"] = "skipped"
+	} else if err := database.CheckMigrations(ctx, database.DB); err != nil {
+		fail("migrations", err)
+	}
+
+	if !ready {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "unavailable", "checks": checks})
+	}
+	return c.JSON(fiber.Map{"status": "ok", "checks": checks})
+}
+
+var metricsHandler = adaptor.HTTPHandler(metrics.Handler())
+
+// Prometheus metrics
+func HandleMetrics(c *fiber.Ctx) error {
+	return metricsHandler(c)
+}
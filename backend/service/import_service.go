@@ -0,0 +1,170 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/importer"
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ขนาดไฟล์นำเข้าสูงสุด เท่ากับ client_max_body_size ของ nginx
+const maxImportSize = 10 << 20
+
+// อ่านไฟล์ที่อัปโหลดในช่อง file ข้อผิดพลาดเป็น *fiber.Error ที่มี status ที่ควรตอบ
+func readImportUpload(c *fiber.Ctx) (string, []byte, error) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, fiber.NewError(400, "File is required")
+	}
+	if file.Size > maxImportSize {
+		return "", nil, fiber.NewError(413, "File is too large")
+	}
+	f, err := file.Open()
+	if err != nil {
+		return "", nil, fiber.NewError(400, "Failed to read file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxImportSize+1))
+	if err != nil {
+		return "", nil, fiber.NewError(400, "Failed to read file")
+	}
+	if len(data) > maxImportSize {
+		return "", nil, fiber.NewError(413, "File is too large")
+	}
+	return file.Filename, data, nil
+}
+
+func importErrorResponse(c *fiber.Ctx, err error) error {
+	var e *fiber.Error
+	if errors.As(err, &e) {
+		return c.Status(e.Code).JSON(utils.ErrorResponse(e.Message))
+	}
+	var unsupported *importer.UnsupportedFileError
+	if errors.As(err, &unsupported) {
+		return c.Status(415).JSON(utils.ErrorResponse(unsupported.Message))
+	}
+	return c.Status(400).JSON(utils.ErrorResponse(err.Error()))
+}
+
+func sendImportReport(c *fiber.Ctx, report *importer.Report) error {
+	if report.DryRun {
+		return c.JSON(utils.SuccessResponse(report, "Import checked, nothing was saved"))
+	}
+	return c.JSON(utils.SuccessResponse(report, "Import completed"))
+}
+
+// Import Articles
+// รับไฟล์ .md หนึ่งไฟล์หรือ .zip ที่มีหลายไฟล์ dry_run=true ตรวจอย่างเดียวโดยไม่บันทึก
+// overwrite=true แทนที่บทความของตัวเองที่ slug ตรงกัน
+func HandleImportArticles(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	filename, data, err := readImportUpload(c)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	posts, failed, _, err := importer.ReadFile(importer.SourceMarkdown, filename, data)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	report := importer.Import(c.Context(), posts, importer.Options{
+		AuthorID:  userID,
+		DryRun:    c.FormValue("dry_run") == "true",
+		Overwrite: c.FormValue("overwrite") == "true",
+	})
+	report.AddUnread(failed)
+	return sendImportReport(c, report)
+}
+
+// Import WordPress / Medium (admin)
+// WordPress รับไฟล์ WXR (.xml) หรือ .zip ที่มีไฟล์ WXR กับโฟลเดอร์ wp-content/uploads ส่วน Medium รับ .zip ที่ดาวน์โหลดจาก Medium
+// create_authors=true สร้างบัญชีให้ผู้เขียนที่ไม่มีในระบบ download_images=false ไม่ดาวน์โหลดรูปที่ไม่มีใน ZIP
+// category คือหมวดหมู่ของบทความที่ไม่มีหมวดหมู่ (ค่าเริ่มต้น Uncategorized)
+func HandleImportBlog(c *fiber.Ctx, source string) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	filename, data, err := readImportUpload(c)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	posts, skipped, archive, err := importer.ReadFile(source, filename, data)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	category := strings.TrimSpace(c.FormValue("category"))
+	if category == "" {
+		category = "Uncategorized"
+	}
+	report := importer.Import(c.Context(), posts, importer.Options{
+		AuthorID:        userID,
+		DryRun:          c.FormValue("dry_run") == "true",
+		Overwrite:       c.FormValue("overwrite") == "true",
+		CreateAuthors:   c.FormValue("create_authors") == "true",
+		DefaultCategory: category,
+		Images:          importer.NewImageSource(archive, c.FormValue("download_images") != "false"),
+	})
+	report.AddUnread(skipped)
+	return sendImportReport(c, report)
+}
+
+// Export Articles
+// ส่งออกบทความเป็น ZIP ของไฟล์ Markdown รูปแบบเดียวกับที่นำเข้าได้
+// author=<username> ส่งออกบทความของผู้ใช้นั้น (ค่าเริ่มต้นคือตัวเอง) all=true ส่งออกทั้งเว็บ
+// การส่งออกของผู้อื่นหรือทั้งเว็บต้องเป็น admin
+func HandleExportArticles(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	var user models.User
+	if err := database.DB.Select("id", "username", "role").First(&user, userID).Error; err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	filter := repository.ArticleFilter{Author: user.Username}
+	name := "articles-" + user.Username
+	if c.Query("all") == "true" {
+		filter.Author, name = "", "articles"
+	} else if author := c.Query("author"); author != "" && author != user.Username {
+		var exists int64
+		database.DB.Model(&models.User{}).Where("username = ?", author).Count(&exists)
+		if exists == 0 {
+			return c.Status(404).JSON(utils.ErrorResponse("User not found"))
+		}
+		filter.Author, name = author, "articles-"+author
+	}
+	if filter.Author != user.Username && user.Role != models.RoleAdmin {
+		return c.Status(403).JSON(utils.ErrorResponse("Only admins can export other users' articles"))
+	}
+
+	var articles []models.Article
+	if err := composables.ArticleListQuery(filter).Find(&articles).Error; err != nil {
+		return serverError(c, err, "Failed to load articles")
+	}
+
+	var buf bytes.Buffer
+	if err := importer.Export(&buf, articles); err != nil {
+		return serverError(c, err, "Failed to export articles")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name + ".zip"}))
+	return c.Send(buf.Bytes())
+}
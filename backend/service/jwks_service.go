@@ -0,0 +1,18 @@
+package service
+
+import (
+	"backend/keys"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Get JSON Web Key Set
+func HandleGetJWKS(c *fiber.Ctx) error {
+	jwks, err := keys.PublicKeys()
+	if err != nil {
+		return serverError(c, err, "Failed to load signing keys")
+	}
+	// key ใหม่จะถูกโหลดจากฐานข้อมูลทุกนาที cache สั้นๆ ให้ผู้ใช้เห็น key ใหม่เร็ว
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(fiber.Map{"keys": jwks})
+}
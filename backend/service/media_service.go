@@ -0,0 +1,222 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/imaging"
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+	"backend/validation"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// mediaItem คือ Media พร้อมบอกว่ามีบทความใช้อยู่หรือไม่
+type mediaItem struct {
+	models.Media
+	InUse bool `json:"in_use"`
+}
+
+// List Media Library
+func HandleListMedia(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit := c.QueryInt("limit", 30)
+	if limit < 1 || limit > 100 {
+		limit = 30
+	}
+
+	tx := database.DB.Model(&models.Media{}).Where("user_id = ?", userID)
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		like := repository.Contains(search)
+		tx = tx.Where("LOWER(filename) LIKE ?"+repository.LikeEscape+" OR LOWER(alt_text) LIKE ?"+repository.LikeEscape+" OR LOWER(caption) LIKE ?"+repository.LikeEscape, like, like, like)
+	}
+	tx = tx.Session(&gorm.Session{})
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return serverError(c, err, "Failed to load media")
+	}
+	var media []models.Media
+	if err := tx.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&media).Error; err != nil {
+		return serverError(c, err, "Failed to load media")
+	}
+
+	inUse := map[uint]bool{}
+	if len(media) > 0 {
+		ids := make([]uint, len(media))
+		for i, m := range media {
+			ids[i] = m.ID
+		}
+		var used []uint
+		database.DB.Table("article_media").Where("media_id IN ?", ids).Distinct().Pluck("media_id", &used)
+		for _, id := range used {
+			inUse[id] = true
+		}
+	}
+	items := make([]mediaItem, len(media))
+	for i, m := range media {
+		items[i] = mediaItem{Media: m, InUse: inUse[m.ID]}
+	}
+
+	return c.JSON(utils.SuccessResponse(fiber.Map{
+		"items": items,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	}, "Media retrieved"))
+}
+
+// Upload Media
+func HandleUploadMedia(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	var input validation.UpdateMediaInput
+	altText, caption := c.FormValue("alt_text"), c.FormValue("caption")
+	input.AltText, input.Caption = &altText, &caption
+	if errs := validation.ValidateStructMedia(input); errs != nil {
+		return c.Status(400).JSON(fiber.Map{"errors": errs})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("File is required"))
+	}
+	if file.Size > imaging.MaxUploadBytes {
+		return c.Status(413).JSON(utils.ErrorResponse(composables.ErrMediaTooLarge.Error()))
+	}
+	f, err := file.Open()
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Failed to read file"))
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, imaging.MaxUploadBytes+1))
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Failed to read file"))
+	}
+
+	media, err := composables.StoreMedia(c.Context(), userID, file.Filename, data)
+	if errors.Is(err, composables.ErrMediaTooLarge) {
+		return c.Status(413).JSON(utils.ErrorResponse(err.Error()))
+	}
+	if errors.Is(err, composables.ErrMediaInvalid) {
+		return c.Status(415).JSON(utils.ErrorResponse(err.Error()))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to save image")
+	}
+
+	media.AltText = strings.TrimSpace(altText)
+	media.Caption = strings.TrimSpace(caption)
+	if err := database.DB.Create(media).Error; err != nil {
+		composables.RemoveMediaFiles(media.StorageKey)
+		return serverError(c, err, "Failed to save image")
+	}
+	return c.Status(201).JSON(utils.SuccessResponse(mediaItem{Media: *media}, "Image uploaded"))
+}
+
+// Get Media
+func HandleGetMedia(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	media, err := findOwnMedia(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Media not found"))
+	}
+	inUse, err := composables.MediaInUse(database.DB, media.ID)
+	if err != nil {
+		return serverError(c, err, "Failed to load media")
+	}
+	return c.JSON(utils.SuccessResponse(mediaItem{Media: *media, InUse: inUse}, "Media retrieved"))
+}
+
+// Update Media (alt text และ caption)
+func HandleUpdateMedia(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	media, err := findOwnMedia(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Media not found"))
+	}
+
+	var input validation.UpdateMediaInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid input format"))
+	}
+	if errs := validation.ValidateStructMedia(input); errs != nil {
+		return c.Status(400).JSON(fiber.Map{"errors": errs})
+	}
+	if input.AltText != nil {
+		media.AltText = strings.TrimSpace(*input.AltText)
+	}
+	if input.Caption != nil {
+		media.Caption = strings.TrimSpace(*input.Caption)
+	}
+	if err := database.DB.Save(media).Error; err != nil {
+		return serverError(c, err, "Failed to update media")
+	}
+
+	inUse, _ := composables.MediaInUse(database.DB, media.ID)
+	return c.JSON(utils.SuccessResponse(mediaItem{Media: *media, InUse: inUse}, "Media updated"))
+}
+
+// Delete Media
+func HandleDeleteMedia(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	media, err := findOwnMedia(userID, c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Media not found"))
+	}
+
+	// ตรวจซ้ำใน transaction เดียวกับที่ลบ
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		inUse, err := composables.MediaInUse(tx, media.ID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return errMediaInUse
+		}
+		return tx.Delete(media).Error
+	})
+	if errors.Is(err, errMediaInUse) {
+		return c.Status(409).JSON(utils.ErrorResponse("This image is used in an article, remove it from the article first"))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to delete media")
+	}
+	composables.RemoveMediaFiles(media.StorageKey)
+	return c.JSON(utils.SuccessResponse(nil, "Media deleted"))
+}
+
+var errMediaInUse = errors.New("media is used by an article")
+
+// หา media ตาม id ที่เป็นของผู้ใช้คนนี้
+func findOwnMedia(userID uint, id string) (*models.Media, error) {
+	var media models.Media
+	if err := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&media).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
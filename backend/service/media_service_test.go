@@ -0,0 +1,113 @@
+package service
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// บทความของ B ที่แปะ URL รูปของ A ต้องไม่ทำให้รูปนั้นถูกนับว่าใช้อยู่ A จึงยังลบรูปของตัวเองได้
+func TestDeleteMediaReferencedByAnotherAuthor(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(dir, "blog.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("STORAGE_LOCAL_DIR", filepath.Join(dir, "uploads"))
+	cfg := config.MustLoad(nil)
+	if err := database.Connect(cfg.Database); err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(database.DB); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Init(cfg.Storage); err != nil {
+		t.Fatal(err)
+	}
+	InitServices()
+
+	users := make([]models.User, 2)
+	for i, name := range []string{"alice", "bob"} {
+		users[i] = models.User{Username: name, Email: name + "@example.com", PasswordHash: "x"}
+		if err := database.DB.Create(&users[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice, bob := users[0], users[1]
+	key := fmt.Sprintf("media/user_%d/1700000000000_ab12cd34", alice.ID)
+	media := models.Media{UserID: alice.ID, StorageKey: key, ContentType: "image/jpeg", URL: "/uploads/" + key + "_640.jpg"}
+	if err := database.DB.Create(&media).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	// แทน middleware.Protected ด้วยการอ่าน id ผู้ใช้จาก header
+	app.Use(func(c *fiber.Ctx) error {
+		id, _ := strconv.ParseUint(c.Get("X-User-ID"), 10, 64)
+		c.Locals("userID", uint(id))
+		return c.Next()
+	})
+	app.Post("/articles", HandleCreateArticle)
+	app.Delete("/media/:id", HandleDeleteMedia)
+	call := func(method, path string, user uint, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", strconv.FormatUint(uint64(user), 10))
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode
+	}
+
+	article, _ := json.Marshal(map[string]interface{}{
+		"title":         "Borrowed",
+		"slug":          "borrowed",
+		"content":       "![x](" + media.URL + ")",
+		"category_name": "Go",
+	})
+	if status := call("POST", "/articles", bob.ID, string(article)); status != fiber.StatusCreated && status != fiber.StatusOK {
+		t.Fatalf("create article: status %d", status)
+	}
+	var pinned int64
+	if err := database.DB.Table("article_media").Where("media_id = ?", media.ID).Count(&pinned).Error; err != nil || pinned != 0 {
+		t.Fatalf("article_media rows for another user's image: %d, %v", pinned, err)
+	}
+
+	// รูปของ A ในบทความของ A ยังถูกนับว่าใช้อยู่
+	own := models.Media{UserID: alice.ID, StorageKey: key + "0", ContentType: "image/jpeg", URL: "/uploads/" + key + "0_640.jpg"}
+	if err := database.DB.Create(&own).Error; err != nil {
+		t.Fatal(err)
+	}
+	article, _ = json.Marshal(map[string]interface{}{
+		"title":         "Own",
+		"slug":          "own",
+		"content":       "![x](" + own.URL + ")",
+		"category_name": "Go",
+	})
+	if status := call("POST", "/articles", alice.ID, string(article)); status != fiber.StatusCreated && status != fiber.StatusOK {
+		t.Fatalf("create own article: status %d", status)
+	}
+	if status := call("DELETE", fmt.Sprintf("/media/%d", own.ID), alice.ID, ""); status != fiber.StatusConflict {
+		t.Fatalf("delete image used by own article: status %d, want 409", status)
+	}
+
+	path := fmt.Sprintf("/media/%d", media.ID)
+	if status := call("DELETE", path, bob.ID, ""); status != fiber.StatusNotFound {
+		t.Fatalf("delete by bob: status %d, want 404", status)
+	}
+	if status := call("DELETE", path, alice.ID, ""); status != fiber.StatusOK {
+		t.Fatalf("delete by alice: status %d, want 200", status)
+	}
+}
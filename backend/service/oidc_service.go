@@ -0,0 +1,254 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/oidc"
+	"backend/utils"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// state มีอายุสั้นๆ พอให้ผู้ใช้ login กับผู้ให้บริการเสร็จ
+const oidcStateTTL = 10 * time.Minute
+
+// cookie ที่ผูก state กับ browser ที่เริ่ม login กัน login CSRF
+// ที่ผู้โจมตีเอา callback ของบัญชีตัวเองไปเปิดใน browser ของเหยื่อ
+const oidcStateCookie = "oidc_state"
+
+var errEmailNotVerified = errors.New("email exists but is not verified by the provider")
+
+var errReservedEmail = errors.New("email is reserved")
+
+// List OIDC Providers
+func HandleOIDCProviders(c *fiber.Ctx) error {
+	return c.JSON(utils.SuccessResponse(oidc.ProviderNames(), "OIDC providers"))
+}
+
+// Start OIDC Login
+func HandleOIDCLogin(c *fiber.Ctx) error {
+	provider, ok := oidc.Lookup(c.Params("provider"))
+	if !ok {
+		return c.Status(404).JSON(utils.ErrorResponse("Unknown login provider"))
+	}
+
+	state, err := oidc.RandomString(24)
+	if err != nil {
+		return serverError(c, err, "Failed to start login")
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return serverError(c, err, "Failed to start login")
+	}
+	verifier, err := oidc.RandomString(48)
+	if err != nil {
+		return serverError(c, err, "Failed to start login")
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Context(), state, nonce, verifier)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "oidc discovery failed", "provider", provider.Name, "error", err)
+		return c.Status(502).JSON(utils.ErrorResponse("Login provider is unavailable"))
+	}
+
+	// state ที่หมดอายุค้างอยู่ก็ใช้ไม่ได้อยู่แล้ว ลบไม่สำเร็จจึงไม่ต้องหยุด login
+	if err := database.DB.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		slog.WarnContext(c.UserContext(), "failed to delete expired oidc states", "error", err)
+	}
+	if err := database.DB.Create(&models.OIDCLoginState{
+		State:        state,
+		Provider:     provider.Name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}).Error; err != nil {
+		return serverError(c, err, "Failed to start login")
+	}
+
+	// Lax ยังส่ง cookie ไปกับ redirect แบบ GET กลับจากผู้ให้บริการ
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   int(oidcStateTTL.Seconds()),
+		Secure:   c.Secure(),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// OIDC Callback
+func HandleOIDCCallback(c *fiber.Ctx) error {
+	provider, ok := oidc.Lookup(c.Params("provider"))
+	if !ok {
+		return c.Status(404).JSON(utils.ErrorResponse("Unknown login provider"))
+	}
+	if e := c.Query("error"); e != "" {
+		return c.Status(400).JSON(utils.ErrorResponse("Login was cancelled or denied: " + e))
+	}
+
+	cookie := c.Cookies(oidcStateCookie)
+	c.Cookie(&fiber.Cookie{Name: oidcStateCookie, Path: "/", MaxAge: -1, Secure: c.Secure(), HTTPOnly: true, SameSite: fiber.CookieSameSiteLaxMode})
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(c.Query("state"))) != 1 {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid or expired login state"))
+	}
+
+	// state ใช้ได้ครั้งเดียว callback ที่ส่งมาซ้ำพร้อมกันอ่านเจอได้ทุกตัว แต่มีตัวเดียวที่ลบแถวได้
+	var state models.OIDCLoginState
+	err := database.DB.First(&state, "state = ?", c.Query("state")).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid or expired login state"))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to sign in")
+	}
+	consumed := database.DB.Delete(&models.OIDCLoginState{}, "state = ?", state.State)
+	if consumed.Error != nil {
+		return serverError(c, consumed.Error, "Failed to sign in")
+	}
+	if consumed.RowsAffected != 1 {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid or expired login state"))
+	}
+	if state.Provider != provider.Name || time.Now().After(state.ExpiresAt) {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid or expired login state"))
+	}
+
+	claims, err := provider.Exchange(c.Context(), c.Query("code"), state.CodeVerifier, state.Nonce)
+	if err != nil {
+		slog.WarnContext(c.UserContext(), "oidc code exchange failed", "provider", provider.Name, "error", err)
+		return c.Status(401).JSON(utils.ErrorResponse("Login with provider failed"))
+	}
+
+	if claims.Email == "" {
+		return c.Status(400).JSON(utils.ErrorResponse("Login provider did not share an email address"))
+	}
+
+	user, err := findOrLinkOIDCUser(provider.Name, claims)
+	if errors.Is(err, errEmailNotVerified) {
+		return c.Status(409).JSON(utils.ErrorResponse("An account with this email already exists, sign in with your password first"))
+	}
+	if errors.Is(err, errReservedEmail) {
+		return c.Status(403).JSON(utils.ErrorResponse("This email address cannot be used"))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to sign in")
+	}
+
+	err = composables.RecordLoginAttempt(c.UserContext(), models.LoginAttempt{
+		Identifier: composables.NormalizeLoginIdentifier(user.Email),
+		UserID:     &user.ID,
+		IP:         composables.ClientIP(c),
+		UserAgent:  composables.Truncate(c.Get(fiber.HeaderUserAgent), 255),
+		Success:    true,
+	})
+	if err != nil {
+		return serverError(c, err, "Failed to sign in")
+	}
+
+	tokenString, err := issueSessionToken(c, user)
+	if err != nil {
+		return serverError(c, err, "Failed to generate token")
+	}
+
+	// ถ้าตั้ง OIDC_SUCCESS_REDIRECT ไว้ ส่งผู้ใช้กลับหน้าเว็บพร้อม token ใน fragment (ไม่ถูกส่งไปที่ server)
+	if redirect := config.Get().OIDC.SuccessRedirect; redirect != "" {
+		return c.Redirect(redirect+"#token="+url.QueryEscape(tokenString), fiber.StatusFound)
+	}
+	return c.JSON(utils.SuccessResponse(fiber.Map{"token": tokenString}, "Login successful"))
+}
+
+// หา user จาก identity ที่เคยผูกไว้ ถ้าไม่มีให้ผูกกับบัญชีที่ใช้อีเมลเดียวกัน หรือสร้างบัญชีใหม่
+func findOrLinkOIDCUser(provider string, claims *oidc.Claims) (*models.User, error) {
+	var identity models.UserIdentity
+	err := database.DB.Preload("User").
+		Where("provider = ? AND subject = ?", provider, claims.Subject).
+		First(&identity).Error
+	if err == nil {
+		return &identity.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// ผู้ให้บริการที่ยืนยันอีเมลของบัญชี "deleted user" ต้องไม่ได้บัญชีนั้นไป
+	if models.ReservedEmail(claims.Email) {
+		return nil, errReservedEmail
+	}
+
+	var user models.User
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("LOWER(email) = ?", strings.ToLower(claims.Email)).First(&user).Error
+		if err == nil && !claims.EmailVerified {
+			// ไม่ผูกบัญชีจากอีเมลที่ผู้ให้บริการไม่ได้ยืนยัน ไม่งั้นใครก็ยึดบัญชีคนอื่นได้
+			return errEmailNotVerified
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if user.ID == 0 {
+			user = newOIDCUser(tx, claims)
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&models.UserIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  claims.Subject,
+			Email:    claims.Email,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// บัญชีที่สร้างจาก OIDC ไม่มีรหัสผ่าน login ด้วยรหัสผ่านไม่ได้
+func newOIDCUser(tx *gorm.DB, claims *oidc.Claims) models.User {
+	base := claims.PreferredUsername
+	if base == "" {
+		base = strings.Split(claims.Email, "@")[0]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		parts := strings.SplitN(strings.TrimSpace(claims.Name), " ", 2)
+		firstName = parts[0]
+		if len(parts) > 1 {
+			lastName = parts[1]
+		}
+	}
+	username := composables.UniqueUsername(tx, base)
+	nickname := claims.Nickname
+	if nickname == "" {
+		nickname = firstName
+	}
+	if nickname == "" {
+		nickname = username
+	}
+
+	return models.User{
+		Username:  username,
+		Email:     claims.Email,
+		FirstName: firstName,
+		LastName:  lastName,
+		Nickname:  nickname,
+		Role:      models.RoleUser,
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/keys"
+	"backend/oidc/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// เริ่ม login กับผู้ให้บริการจำลองแล้วคืน URL ของ callback ที่ผู้ให้บริการส่งกลับมา และ cookie state ที่ backend ตั้งไว้
+func startOIDCLogin(t *testing.T, app *fiber.App) (callback string, cookie *http.Cookie) {
+	t.Helper()
+	res, err := app.Test(httptest.NewRequest("GET", "/auth/oidc/mock/login", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != fiber.StatusFound {
+		t.Fatalf("login: status %d, want 302", res.StatusCode)
+	}
+	for _, c := range res.Cookies() {
+		if c.Name == oidcStateCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("login: want an HttpOnly SameSite=Lax %s cookie, got %v", oidcStateCookie, res.Cookies())
+	}
+
+	// ผู้ให้บริการจำลองอนุมัติทันทีแล้ว redirect กลับไปที่ callback พร้อม code และ state
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	authorize, err := client.Get(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorize.Body.Close()
+	location, err := url.Parse(authorize.Header.Get("Location"))
+	if err != nil || location.Query().Get("code") == "" {
+		t.Fatalf("authorize: status %d, location %q", authorize.StatusCode, authorize.Header.Get("Location"))
+	}
+	return location.RequestURI(), cookie
+}
+
+func callOIDCCallback(t *testing.T, app *fiber.App, callback string, cookie *http.Cookie) int {
+	t.Helper()
+	req := httptest.NewRequest("GET", callback, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode
+}
+
+func TestOIDCLoginRequiresStateCookie(t *testing.T) {
+	var provider *mock.Server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	var err error
+	if provider, err = mock.New(server.URL, "boblog", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(t.TempDir(), "blog.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("OIDC_PROVIDERS", "mock")
+	t.Setenv("OIDC_MOCK_ISSUER", server.URL)
+	t.Setenv("OIDC_MOCK_CLIENT_ID", "boblog")
+	t.Setenv("OIDC_MOCK_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_MOCK_REDIRECT_URL", "http://blog.test/auth/oidc/mock/callback")
+	cfg := config.MustLoad(nil)
+	if err := database.Connect(cfg.Database); err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if _, err := database.MigrateUp(database.DB); err != nil {
+		t.Fatal(err)
+	}
+	InitServices()
+	keys.Init(cfg.JWT)
+
+	app := fiber.New()
+	app.Get("/auth/oidc/:provider/login", HandleOIDCLogin)
+	app.Get("/auth/oidc/:provider/callback", HandleOIDCCallback)
+
+	t.Run("same browser", func(t *testing.T) {
+		callback, cookie := startOIDCLogin(t, app)
+		if status := callOIDCCallback(t, app, callback, cookie); status != fiber.StatusOK {
+			t.Fatalf("callback: status %d, want 200", status)
+		}
+	})
+
+	t.Run("replayed callback", func(t *testing.T) {
+		callback, cookie := startOIDCLogin(t, app)
+		if status := callOIDCCallback(t, app, callback, cookie); status != fiber.StatusOK {
+			t.Fatalf("first callback: status %d, want 200", status)
+		}
+		if status := callOIDCCallback(t, app, callback, cookie); status != fiber.StatusBadRequest {
+			t.Fatalf("second callback: status %d, want 400", status)
+		}
+	})
+
+	t.Run("without cookie", func(t *testing.T) {
+		callback, _ := startOIDCLogin(t, app)
+		if status := callOIDCCallback(t, app, callback, nil); status != fiber.StatusBadRequest {
+			t.Fatalf("callback: status %d, want 400", status)
+		}
+	})
+
+	// ผู้โจมตีเริ่ม login ของตัวเอง แล้วหลอกให้เหยื่อเปิด callback นั้นใน browser ที่มี state ของอีก flow หนึ่ง
+	t.Run("state of another flow", func(t *testing.T) {
+		attackerCallback, _ := startOIDCLogin(t, app)
+		_, victimCookie := startOIDCLogin(t, app)
+		if status := callOIDCCallback(t, app, attackerCallback, victimCookie); status != fiber.StatusBadRequest {
+			t.Fatalf("callback: status %d, want 400", status)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/ogimage"
+	"backend/utils"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// metaTag คือ <meta> หนึ่งแท็ก Open Graph ใช้ property ส่วน Twitter ใช้ name
+type metaTag struct {
+	Property string `json:"property,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Content  string `json:"content"`
+}
+
+func siteName() string {
+	return config.Get().Site.Name
+}
+
+// Get Article Meta (Open Graph / Twitter card)
+func HandleGetArticleMeta(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid slug format"))
+	}
+
+	var article models.Article
+	if err := database.DB.
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Preload("Cover").
+		First(&article, "slug = ?", slug).Error; err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Article not found"))
+	}
+
+	title := article.Title
+	if article.SEOTitle != "" {
+		title = article.SEOTitle
+	}
+	description := composables.ArticleDescription(&article)
+	canonical := composables.ArticleURL(&article)
+	author := composables.ArticleAuthorName(&article)
+
+	openGraph := []metaTag{
+		{Property: "og:type", Content: "article"},
+		{Property: "og:site_name", Content: siteName()},
+		{Property: "og:title", Content: title},
+		{Property: "og:description", Content: description},
+		{Property: "og:url", Content: canonical},
+		{Property: "article:published_time", Content: article.CreatedAt.UTC().Format(time.RFC3339)},
+		{Property: "article:modified_time", Content: article.UpdatedAt.UTC().Format(time.RFC3339)},
+		{Property: "article:author", Content: author},
+	}
+	if article.Category.Name != "" {
+		openGraph = append(openGraph, metaTag{Property: "article:section", Content: article.Category.Name})
+	}
+	for _, tag := range article.Tags {
+		openGraph = append(openGraph, metaTag{Property: "article:tag", Content: tag.Name})
+	}
+
+	twitter := []metaTag{
+		{Name: "twitter:card", Content: "summary_large_image"},
+		{Name: "twitter:title", Content: title},
+		{Name: "twitter:description", Content: description},
+	}
+
+	// บทความที่ไม่มีรูปปกใช้การ์ดที่ backend วาดให้ (ดู HandleGetArticleOGImage)
+	image := fiber.Map{
+		"url":    composables.SiteURL() + "/articles/" + url.PathEscape(article.Slug) + "/og.png",
+		"width":  ogimage.Width,
+		"height": ogimage.Height,
+		"alt":    article.Title,
+		"type":   "image/png",
+	}
+	if article.Cover != nil {
+		alt := article.Cover.AltText
+		if alt == "" {
+			alt = article.Title
+		}
+		image = fiber.Map{
+			"url":    composables.AbsoluteURL(article.Cover.URL),
+			"width":  article.Cover.Width,
+			"height": article.Cover.Height,
+			"alt":    alt,
+			"type":   article.Cover.ContentType,
+		}
+	}
+	openGraph = append(openGraph,
+		metaTag{Property: "og:image", Content: image["url"].(string)},
+		metaTag{Property: "og:image:type", Content: image["type"].(string)},
+		metaTag{Property: "og:image:width", Content: strconv.Itoa(image["width"].(int))},
+		metaTag{Property: "og:image:height", Content: strconv.Itoa(image["height"].(int))},
+		metaTag{Property: "og:image:alt", Content: image["alt"].(string)},
+	)
+	twitter = append(twitter,
+		metaTag{Name: "twitter:image", Content: image["url"].(string)},
+		metaTag{Name: "twitter:image:alt", Content: image["alt"].(string)},
+	)
+
+	// meta เปลี่ยนเมื่อบทความถูกแก้ไขเท่านั้น ให้ Nuxt cache ได้สั้นๆ
+	c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+	return c.JSON(utils.SuccessResponse(fiber.Map{
+		"title":         title,
+		"description":   description,
+		"canonical_url": canonical,
+		"image":         image,
+		"open_graph":    openGraph,
+		"twitter":       twitter,
+	}, "Article meta retrieved"))
+}
+
+// Get Article OG Image (การ์ด PNG สำหรับแชร์ลิงก์)
+func HandleGetArticleOGImage(c *fiber.Ctx) error {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid slug format"))
+	}
+
+	var article models.Article
+	if err := database.DB.
+		Preload("Author").
+		Preload("Category").
+		First(&article, "slug = ?", slug).Error; err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("Article not found"))
+	}
+
+	// ETag คือ revision ของรูป ตอบ 304 ได้โดยไม่ต้องโหลดรูปจาก storage
+	etag := `"` + composables.OGImageRevision(&article, siteName()) + `"`
+	if composables.NotModified(c, etag, time.Time{}) {
+		c.Set(fiber.HeaderETag, etag)
+		c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	data, err := composables.ArticleOGImage(c.Context(), &article, siteName())
+	if errors.Is(err, ogimage.ErrNoFont) {
+		slog.WarnContext(c.UserContext(), "share image font is missing", "error", err)
+		return c.Status(503).JSON(utils.ErrorResponse("Share image is not available"))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to render share image")
+	}
+	// URL ไม่เปลี่ยนตาม revision จึง cache ได้ไม่นาน แล้วตรวจซ้ำด้วย ETag
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(data)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type sessionResponse struct {
+	models.Session
+	Current bool `json:"current"`
+}
+
+// List Active Sessions
+func HandleListSessions(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	currentID, _ := c.Locals("sessionID").(uint)
+
+	var sessions []models.Session
+	if err := database.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return serverError(c, err, "Failed to load sessions")
+	}
+
+	result := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, sessionResponse{Session: s, Current: s.ID == currentID})
+	}
+	return c.JSON(utils.SuccessResponse(result, "Sessions retrieved"))
+}
+
+// Revoke Session
+func HandleRevokeSession(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	result := database.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", c.Params("id"), userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return serverError(c, result.Error, "Failed to revoke session")
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(404).JSON(utils.ErrorResponse("Session not found"))
+	}
+	return c.JSON(utils.SuccessResponse(nil, "Session revoked"))
+}
+
+// Revoke All Other Sessions
+func HandleRevokeOtherSessions(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+	currentID, _ := c.Locals("sessionID").(uint)
+
+	result := database.DB.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, currentID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return serverError(c, result.Error, "Failed to revoke sessions")
+	}
+	return c.JSON(utils.SuccessResponse(fiber.Map{"revoked": result.RowsAffected}, "Other sessions revoked"))
+}
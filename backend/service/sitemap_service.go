@@ -0,0 +1,149 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// จำนวน URL สูงสุดต่อไฟล์ตามข้อกำหนดของ sitemaps.org ถ้าเกินจะแบ่งเป็นหลายไฟล์และ /sitemap.xml เป็น sitemap index
+const sitemapMaxURLs = 50000
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	NS      string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	NS       string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Get Sitemap
+func HandleGetSitemap(c *fiber.Ctx) error {
+	var total int64
+	if err := database.DB.Model(&models.SitemapEntry{}).Count(&total).Error; err != nil {
+		return serverError(c, err, "Failed to load sitemap")
+	}
+	if total <= sitemapMaxURLs {
+		return sendSitemapPage(c, 1)
+	}
+
+	// แต่ละไฟล์ย่อยมี lastmod เป็นค่าล่าสุดของรายการในไฟล์นั้น
+	var entries []models.SitemapEntry
+	if err := database.DB.Select("id", "last_mod").Order("id").Find(&entries).Error; err != nil {
+		return serverError(c, err, "Failed to load sitemap")
+	}
+	index := sitemapIndex{NS: sitemapNS}
+	var updated time.Time
+	for start := 0; start < len(entries); start += sitemapMaxURLs {
+		var lastMod time.Time
+		for _, entry := range entries[start:min(start+sitemapMaxURLs, len(entries))] {
+			if entry.LastMod.After(lastMod) {
+				lastMod = entry.LastMod
+			}
+		}
+		if lastMod.After(updated) {
+			updated = lastMod
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     fmt.Sprintf("%s/sitemaps/sitemap-%d.xml", composables.SiteURL(), start/sitemapMaxURLs+1),
+			LastMod: lastMod.UTC().Format(time.RFC3339),
+		})
+	}
+	return sendSitemapXML(c, index, updated)
+}
+
+// Get Sitemap Page (ไฟล์ย่อยของ sitemap index)
+func HandleGetSitemapPage(c *fiber.Ctx) error {
+	page, err := c.ParamsInt("page")
+	if err != nil || page < 1 {
+		return c.Status(404).JSON(utils.ErrorResponse("Sitemap not found"))
+	}
+	return sendSitemapPage(c, page)
+}
+
+func sendSitemapPage(c *fiber.Ctx, page int) error {
+	var entries []models.SitemapEntry
+	if err := database.DB.Order("id").Offset((page - 1) * sitemapMaxURLs).Limit(sitemapMaxURLs).Find(&entries).Error; err != nil {
+		return serverError(c, err, "Failed to load sitemap")
+	}
+	if len(entries) == 0 && page > 1 {
+		return c.Status(404).JSON(utils.ErrorResponse("Sitemap not found"))
+	}
+
+	set := sitemapURLSet{NS: sitemapNS, URLs: make([]sitemapURL, len(entries))}
+	var updated time.Time
+	for i, entry := range entries {
+		set.URLs[i] = sitemapURL{Loc: composables.SitemapLoc(entry), LastMod: entry.LastMod.UTC().Format(time.RFC3339)}
+		if entry.LastMod.After(updated) {
+			updated = entry.LastMod
+		}
+	}
+	return sendSitemapXML(c, set, updated)
+}
+
+func sendSitemapXML(c *fiber.Ctx, v interface{}, updated time.Time) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return serverError(c, err, "Failed to build sitemap")
+	}
+	data = append([]byte(xml.Header), data...)
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	if !updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, updated.UTC().Format(http.TimeFormat))
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	if composables.NotModified(c, etag, updated) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Send(data)
+}
+
+// Get robots.txt
+// ROBOTS_DISALLOW คือรายการ path คั่นด้วย comma และ ROBOTS_NOINDEX=true ปิดทั้งเว็บ เช่นบนเครื่อง staging
+func HandleGetRobots(c *fiber.Ctx) error {
+	site := config.Get().Site
+	disallow := site.RobotsDisallow
+	if site.RobotsNoindex {
+		disallow = []string{"/"}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("User-agent: *\n")
+	if len(disallow) == 0 {
+		buf.WriteString("Disallow:\n")
+	}
+	for _, path := range disallow {
+		buf.WriteString("Disallow: " + path + "\n")
+	}
+	buf.WriteString("\nSitemap: " + composables.SiteURL() + "/sitemap.xml\n")
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	c.Set(fiber.HeaderContentType, "text/plain; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"backend/composables"
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"backend/validation"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// List Personal Access Tokens
+func HandleListTokens(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	var tokens []models.PersonalAccessToken
+	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return serverError(c, err, "Failed to load tokens")
+	}
+	return c.JSON(utils.SuccessResponse(tokens, "Tokens retrieved"))
+}
+
+// Create Personal Access Token
+func HandleCreateToken(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	var input validation.CreateTokenInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(utils.ErrorResponse("Invalid input format"))
+	}
+	if errs := validation.ValidateStructToken(input); errs != nil {
+		return c.Status(400).JSON(fiber.Map{"errors": errs})
+	}
+
+	raw, hash, err := composables.GeneratePersonalAccessToken()
+	if err != nil {
+		return serverError(c, err, "Failed to generate token")
+	}
+
+	token := models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      input.Name,
+		TokenHash: hash,
+		Prefix:    raw[:len(composables.PersonalAccessTokenPrefix)+4],
+		Scopes:    input.Scopes,
+	}
+	if input.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *input.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := database.DB.Create(&token).Error; err != nil {
+		return serverError(c, err, "Failed to create token")
+	}
+
+	// token จริงแสดงแค่ครั้งนี้ครั้งเดียว หลังจากนี้เก็บไว้แค่ hash
+	return c.Status(201).JSON(utils.SuccessResponse(fiber.Map{
+		"token":   raw,
+		"details": token,
+	}, "Token created, copy it now as it will not be shown again"))
+}
+
+// Revoke Personal Access Token
+func HandleRevokeToken(c *fiber.Ctx) error {
+	userID, err := composables.GetCurrentUserID(c)
+	if err != nil {
+		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Params("id"), userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return serverError(c, result.Error, "Failed to revoke token")
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(404).JSON(utils.ErrorResponse("Token not found"))
+	}
+	return c.JSON(utils.SuccessResponse(nil, "Token revoked"))
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"backend/storage"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Serve Uploaded File
+func HandleServeUpload(c *fiber.Ctx) error {
+	raw, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("File not found"))
+	}
+	key, err := storage.CleanKey(raw)
+	if err != nil {
+		return c.Status(404).JSON(utils.ErrorResponse("File not found"))
+	}
+
+	// ไฟล์ใน private/ เป็นของ backend เอง ตอบเหมือนไม่มีไฟล์
+	if storage.IsPrivate(key) {
+		return c.Status(404).JSON(utils.ErrorResponse("File not found"))
+	}
+
+	r, obj, err := storage.Default().Get(c.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(404).JSON(utils.ErrorResponse("File not found"))
+	}
+	if err != nil {
+		return serverError(c, err, "Failed to read file")
+	}
+
+	c.Set(fiber.HeaderContentType, obj.ContentType)
+	c.Set(fiber.HeaderLastModified, obj.ModTime.UTC().Format(http.TimeFormat))
+	// ชื่อไฟล์ที่อัปโหลดไม่ซ้ำกันเสมอ (มี timestamp) จึง cache ได้นาน
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	// fasthttp ปิด reader ให้เองเมื่อส่งเสร็จ
+	return c.SendStream(r, int(obj.Size))
+}
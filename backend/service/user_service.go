@@ -2,13 +2,17 @@ package service
 
 import (
 	"backend/composables"
-	"backend/database"
 	"backend/models"
+	"backend/passwords"
+	"backend/repository"
 	"backend/utils"
 	"backend/validation"
-	
+	"errors"
+
 
 	"github.com/gofiber/fiber/v2"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -18,32 +22,10 @@ func HandleRegister(c *fiber.Ctx) error {
 	if err := c.BodyParser(&input); err != nil {
 		return c.Status(400).JSON(utils.ErrorResponse("Failed to read form data")) 
 	}
-	if errs := validation.ValidateStructRegister(input); errs != nil {
-		return c.Status(400).JSON(fiber.Map{"errors": errs})
-	}
-	if input.Password != input.ConfirmPassword {
-		return c.Status(400).JSON(fiber.Map{"errors": map[string]string{"confirm_password": "Password does not match"}})
-	}
-	if exists, _ := composables.IsEmailExists(input.Email); exists {
-		return c.Status(400).JSON(fiber.Map{"errors": map[string]string{"email": "Email already exists"}})
-	}
-	if exists, _ := composables.IsUsernameExists(input.Username); exists {
-		return c.Status(400).JSON(fiber.Map{"errors": map[string]string{"username": "Username already exists"}})
-	}
-
-	hashedPassword := utils.HashPassword(input.Password)
-	user := models.User{
-		Username:     input.Username,
-		Email:        input.Email,
-		FirstName:    input.FirstName,
-		LastName:     input.LastName,
-		Nickname:     input.Nickname,
-		PasswordHash: hashedPassword,
-	}
-	if err := database.DB.Create(&user).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to create user"))
+	user, err := userService.Register(c.UserContext(), input)
+	if err != nil {
+		return domainError(c, err, "User not found", "", "Failed to create user")
 	}
-	user.PasswordHash = ""
 	return c.Status(201).JSON(utils.SuccessResponse(user, "Register successful"))
 }
 
@@ -57,17 +39,49 @@ func HandleLogin(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"errors": errs})
 	}
 
-	var user models.User
-	database.DB.
-		Where("email = ? OR username = ?", input.EmailOrUsername, input.EmailOrUsername).
-		First(&user)
-	if user.ID == 0 || !utils.CheckPassword(input.Password, user.PasswordHash) {
+	identifier := composables.NormalizeLoginIdentifier(input.EmailOrUsername)
+	attempt := models.LoginAttempt{
+		Identifier: identifier,
+		IP:         composables.ClientIP(c),
+		UserAgent:  composables.Truncate(c.Get(fiber.HeaderUserAgent), 255),
+	}
+
+	// ผูกการนับกับบัญชี ไม่ว่าจะเข้าด้วยอีเมลหรือ username ก็ใช้โควตาเดียวกัน
+	user, err := userService.Users.FindByLogin(c.UserContext(), input.EmailOrUsername)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return serverError(c, err, "Failed to login")
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+
+	wait, err := composables.BeginLoginAttempt(c.UserContext(), &attempt)
+	if err != nil {
+		return serverError(c, err, "Failed to login")
+	}
+	if wait > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return c.Status(429).JSON(utils.ErrorResponse("Too many failed login attempts, please try again later"))
+	}
+
+	if user == nil {
+		// เทียบรหัสกับ hash หลอก เพื่อให้เวลาตอบกลับเท่ากับกรณีที่มีบัญชีอยู่จริง
+		passwords.Check(input.Password, passwords.DummyHash)
+	}
+	if user == nil || !passwords.Check(input.Password, user.PasswordHash) {
+		if err := composables.FinishLoginAttempt(c.UserContext(), &attempt, false); err != nil {
+			return serverError(c, err, "Failed to login")
+		}
 		return c.Status(400).JSON(utils.ErrorResponse("Invalid email or password"))
 	}
 
-	tokenString, err := utils.GenerateJWT(user.ID, user.Email, "user") 
+	if err := composables.FinishLoginAttempt(c.UserContext(), &attempt, true); err != nil {
+		return serverError(c, err, "Failed to login")
+	}
+
+	tokenString, err := issueSessionToken(c, user)
 	if err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to generate token"))
+		return serverError(c, err, "Failed to generate token")
 	}
 
 	return c.JSON(utils.SuccessResponse(fiber.Map{"token": tokenString}, "Login successful"))
@@ -79,11 +93,10 @@ func HandleProfile(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
 	}
-	user, err := composables.GetUserByID(userID)
+	user, err := userService.Profile(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(404).JSON(utils.ErrorResponse("User not found"))
+		return domainError(c, err, "User not found", "", "Failed to get user")
 	}
-	user.PasswordHash = ""
 	return c.JSON(utils.SuccessResponse(user, "Current user"))
 }
 
@@ -94,11 +107,13 @@ func HandleUpdateProfile(c *fiber.Ctx) error {
 		return c.Status(401).JSON(utils.ErrorResponse("Unauthorized"))
 	}
 
-	user, err := composables.GetUserByID(userID)
+	user, err := userService.Users.FindByID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(404).JSON(utils.ErrorResponse("User not found"))
 	}
 
+	previousImage := user.Image
+
 	formHeader := c.Get("Content-Type")
 	isMultipart := formHeader != "" && strings.Contains(strings.ToLower(formHeader), "multipart/form-data")
 
@@ -110,7 +125,11 @@ func HandleUpdateProfile(c *fiber.Ctx) error {
 		if bio != "" {
 			user.Bio = &bio
 		}
-		if path, err := composables.HandleAvatarUpload(c, userID); err == nil && path != nil {
+		path, err := composables.HandleAvatarUpload(c, userID)
+		if err != nil {
+			return c.Status(400).JSON(utils.ErrorResponse(err.Error()))
+		}
+		if path != nil {
 			user.Image = path
 		}
 	} else {
@@ -129,9 +148,25 @@ func HandleUpdateProfile(c *fiber.Ctx) error {
 		user.Bio = input.Bio
 	}
 
-	if err := database.DB.Save(user).Error; err != nil {
-		return c.Status(500).JSON(utils.ErrorResponse("Failed to update user"))
+	if err := userService.Users.Save(c.UserContext(), user); err != nil {
+		// ไม่ให้ไฟล์ที่เพิ่งอัปโหลดค้างอยู่ถ้าบันทึกไม่สำเร็จ
+		if user.Image != nil && user.Image != previousImage {
+			composables.RemoveAvatarSet(composables.AvatarSetBase(*user.Image))
+		}
+		return serverError(c, err, "Failed to update user")
+	}
+	if user.Image != nil && user.Image != previousImage {
+		composables.RemoveOldAvatars(userID, *user.Image)
 	}
 	user.PasswordHash = ""
 	return c.JSON(utils.SuccessResponse(user, "Profile updated successfully"))
 }
+
+// สร้าง session ใหม่แล้วออก JWT ที่ผูกกับ session นั้น
+func issueSessionToken(c *fiber.Ctx, user *models.User) (string, error) {
+	session, err := composables.CreateSession(c, user.ID)
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateJWT(user.ID, user.Email, user.Role, session.TokenID)
+}
@@ -0,0 +1,124 @@
+// Package storage เก็บไฟล์ที่ผู้ใช้อัปโหลด โดยเลือกที่เก็บได้จาก STORAGE_DRIVER
+// (local = ดิสก์ของเครื่อง, s3 = S3 หรือบริการที่เข้ากันได้อย่าง MinIO)
+//
+// ไฟล์อ้างถึงด้วย key แบบ path เช่น "avatars/user_1_1700000000000_256.jpg"
+// key ที่ขึ้นต้นด้วย "private/" เป็นไฟล์ที่ backend ใช้เอง เช่น cache ของรูปแชร์ ไม่เปิดให้ดาวน์โหลดผ่าน /uploads
+package storage
+
+import (
+	"backend/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+
+	// URL ที่ backend ใช้ให้บริการไฟล์ ค่าใน User.Image ขึ้นต้นด้วย path นี้เสมอไม่ว่าจะเก็บไว้ที่ไหน
+	PublicPath    = "/uploads/"
+	privatePrefix = "private/"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Object คือข้อมูลของไฟล์ที่เก็บไว้
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage คือที่เก็บไฟล์ ทุก implementation ต้องใช้ key ชุดเดียวกันได้ เพื่อย้ายไฟล์ข้าม backend ได้
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Delete(ctx context.Context, key string) error
+	// List คืนทุกไฟล์ที่ key ขึ้นต้นด้วย prefix (prefix ไม่จำเป็นต้องจบด้วย "/")
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+var current Storage
+
+// Init เปิดที่เก็บไฟล์ตาม cfg.Driver
+func Init(cfg config.Storage) error {
+	s, err := Open(cfg.Driver, cfg)
+	if err != nil {
+		return err
+	}
+	current = s
+	return nil
+}
+
+// Default คือที่เก็บไฟล์ที่ตั้งค่าไว้ด้วย Init
+func Default() Storage {
+	return current
+}
+
+// Open สร้างที่เก็บไฟล์ตามชื่อ driver โดยใช้ค่าของ driver นั้นใน cfg
+// (local ใช้ LocalDir ส่วน s3 ใช้ cfg.S3) driver แยกจาก cfg.Driver เพื่อเปิดสองที่พร้อมกันได้ตอนย้ายไฟล์
+func Open(driver string, cfg config.Storage) (Storage, error) {
+	switch driver {
+	case "", DriverLocal:
+		return NewLocal(cfg.LocalDir)
+	case DriverS3:
+		return NewS3(context.Background(), S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			Region:    cfg.S3.Region,
+			UseSSL:    cfg.S3.UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// CleanKey ตรวจ key ไม่ให้ออกนอกที่เก็บ เช่น "../" หรือ path แบบ absolute
+func CleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+	cleaned := path.Clean(key)
+	if key == "" || cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// IsPrivate บอกว่า key นี้ห้ามเปิดผ่าน /uploads
+func IsPrivate(key string) bool {
+	return strings.HasPrefix(key, privatePrefix)
+}
+
+// PublicURL คือ URL ของไฟล์สาธารณะที่ backend ให้บริการ
+func PublicURL(key string) string {
+	return PublicPath + key
+}
+
+// KeyFromURL แปลง URL ที่ได้จาก PublicURL กลับเป็น key
+func KeyFromURL(u string) (string, bool) {
+	if !strings.HasPrefix(u, PublicPath) {
+		return "", false
+	}
+	key, err := CleanKey(strings.TrimPrefix(u, PublicPath))
+	return key, err == nil
+}
+
+// Copy คัดลอกไฟล์หนึ่งไฟล์จากที่เก็บหนึ่งไปอีกที่หนึ่ง
+func Copy(ctx context.Context, from, to Storage, key string) error {
+	r, obj, err := from.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return to.Put(ctx, key, r, obj.Size, obj.ContentType)
+}
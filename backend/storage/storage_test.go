@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ทดสอบพฤติกรรมที่ทุก driver ต้องเหมือนกัน
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+	files := map[string]string{
+		"articles/1/cover.png":      "cover",
+		"articles/1/body/a.jpg":     "first image",
+		"articles/10/cover.png":     "other article",
+		"private/og/articles/1.png": "og image",
+	}
+	for key, body := range files {
+		if err := s.Put(ctx, key, strings.NewReader(body), int64(len(body)), "image/png"); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+
+	r, obj, err := s.Get(ctx, "articles/1/cover.png")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(data) != "cover" {
+		t.Fatalf("get: read %q, %v", data, err)
+	}
+	if obj.Key != "articles/1/cover.png" || obj.Size != 5 || obj.ContentType != "image/png" || obj.ModTime.IsZero() {
+		t.Fatalf("get: object %+v", obj)
+	}
+
+	// ใส่ไฟล์ชื่อเดิมซ้ำคือเขียนทับ
+	if err := s.Put(ctx, "articles/1/cover.png", strings.NewReader("new cover"), 9, "image/png"); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if r, obj, err = s.Get(ctx, "articles/1/cover.png"); err != nil {
+		t.Fatalf("get after overwrite: %v", err)
+	}
+	r.Close()
+	if obj.Size != 9 {
+		t.Fatalf("get after overwrite: size %d, want 9", obj.Size)
+	}
+
+	for prefix, want := range map[string][]string{
+		"articles/1/":  {"articles/1/body/a.jpg", "articles/1/cover.png"},
+		"articles/1":   {"articles/1/body/a.jpg", "articles/1/cover.png", "articles/10/cover.png"},
+		"private/":     {"private/og/articles/1.png"},
+		"articles/99/": nil,
+	} {
+		objects, err := s.List(ctx, prefix)
+		if err != nil {
+			t.Fatalf("list %q: %v", prefix, err)
+		}
+		var keys []string
+		for _, o := range objects {
+			keys = append(keys, o.Key)
+		}
+		sort.Strings(keys)
+		if strings.Join(keys, ",") != strings.Join(want, ",") {
+			t.Errorf("list %q: got %v, want %v", prefix, keys, want)
+		}
+	}
+
+	if err := s.Delete(ctx, "articles/1/body/a.jpg"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, _, err := s.Get(ctx, "articles/1/body/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get deleted: %v, want ErrNotFound", err)
+	}
+	// ลบไฟล์ที่ไม่มีอยู่ไม่ถือเป็น error
+	if err := s.Delete(ctx, "articles/1/body/a.jpg"); err != nil {
+		t.Fatalf("delete twice: %v", err)
+	}
+
+	for _, key := range []string{"../secret", "articles/../../secret", "a\\b", ""} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("put %q: %v, want ErrInvalidKey", key, err)
+		}
+		if _, _, err := s.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("get %q: %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s)
+}
+
+func TestS3(t *testing.T) {
+	stub := newS3Stub()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	cfg := S3Config{
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Bucket:   "uploads",
+		// ระบุ region ไว้ client จะได้ไม่ต้องถามที่ตั้งของ bucket
+		Region:    "us-east-1",
+		AccessKey: "test",
+		SecretKey: "test-secret",
+	}
+	s, err := NewS3(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stub.buckets["uploads"] {
+		t.Fatal("NewS3 did not create the bucket")
+	}
+	testStorage(t, s)
+
+	// bucket ที่มีอยู่แล้วต้องใช้ต่อได้โดยไม่สร้างใหม่
+	stub.created = 0
+	if _, err := NewS3(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if stub.created != 0 {
+		t.Fatal("NewS3 created an existing bucket again")
+	}
+}
+
+// s3Stub คือ S3 จำลองแบบ path-style ที่รองรับเฉพาะคำสั่งที่ S3 ใน package นี้ใช้
+type s3Stub struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	objects map[string]s3StubObject
+	created int
+}
+
+type s3StubObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func newS3Stub() *s3Stub {
+	return &s3Stub{buckets: map[string]bool{}, objects: map[string]s3StubObject{}}
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if key == "" {
+		s.serveBucket(w, r, bucket)
+		return
+	}
+	if !s.buckets[bucket] {
+		s3StubError(w, r, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := s3StubBody(r)
+		if err != nil {
+			s3StubError(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		s.objects[key] = s3StubObject{data: data, contentType: r.Header.Get("Content-Type"), modTime: time.Now()}
+		w.Header().Set("ETag", `"stub"`)
+	case http.MethodGet, http.MethodHead:
+		obj, ok := s.objects[key]
+		if !ok {
+			s3StubError(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("Last-Modified", obj.modTime.UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"stub"`)
+		if r.Method == http.MethodGet {
+			w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3StubError(w, r, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *s3Stub) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	switch {
+	case r.Method == http.MethodPut:
+		s.buckets[bucket] = true
+		s.created++
+	case !s.buckets[bucket]:
+		s3StubError(w, r, http.StatusNotFound, "NoSuchBucket")
+	case r.Method == http.MethodHead:
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		s.list(w, r.URL.Query())
+	default:
+		s3StubError(w, r, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// list ตอบแบบ ListObjectsV2 ครั้งเดียวจบ ไม่มีการแบ่งหน้า
+func (s *s3Stub) list(w http.ResponseWriter, query url.Values) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+		StorageClass string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Prefix: query.Get("prefix"), MaxKeys: 1000}
+	for key, obj := range s.objects {
+		if strings.HasPrefix(key, result.Prefix) {
+			result.Contents = append(result.Contents, content{
+				Key:          key,
+				LastModified: obj.modTime.UTC().Format(time.RFC3339),
+				ETag:         `"stub"`,
+				Size:         len(obj.data),
+				StorageClass: "STANDARD",
+			})
+		}
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+// s3StubBody อ่านเนื้อไฟล์ ถ้า client ส่งแบบ aws-chunked (ใช้กับการเชื่อมต่อที่ไม่ใช่ TLS) จะตัดส่วนหัวของแต่ละ chunk ออก
+func s3StubBody(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var data bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, br, size); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func s3StubError(w http.ResponseWriter, r *http.Request, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		xml.NewEncoder(w).Encode(struct {
+			XMLName  xml.Name `xml:"Error"`
+			Code     string
+			Message  string
+			Resource string
+		}{Code: code, Message: code, Resource: r.URL.Path})
+	}
+}
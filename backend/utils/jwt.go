@@ -1,21 +1,30 @@
 package utils
 
 import (
-	"os"
+	"backend/keys"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// คือฟังก์ชันที่จะสร้าง JWT token ให้กับผู้ใช้
-func GenerateJWT(userID uint, email string, role string) (string, error) {
+// คือฟังก์ชันที่จะสร้าง JWT token ให้กับผู้ใช้ sessionID คือ TokenID ของ models.Session
+func GenerateJWT(userID uint, email string, role string, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
 		"id":    userID,
 		"email": email,
 		"role":  role,
-		"exp":   time.Now().Add(time.Hour * 72).Unix(),
+		"sid":   sessionID,
+		"exp":   time.Now().Add(keys.TokenTTL()).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	kid, method, key, err := keys.Signer()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(key)
 }
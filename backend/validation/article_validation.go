@@ -15,6 +15,12 @@ type CreateArticleInput struct {
 	Content      string   `json:"content" validate:"required"`
 	CategoryName string   `json:"category_name" validate:"required"`
 	TagNames     []string `json:"tag_names"`
+
+	CoverMediaID   *uint  `json:"cover_media_id"`
+	Excerpt        string `json:"excerpt" validate:"max=500"`
+	SEOTitle       string `json:"seo_title" validate:"max=255"`
+	SEODescription string `json:"seo_description" validate:"max=500"`
+	CanonicalURL   string `json:"canonical_url" validate:"omitempty,http_url,max=500"`
 }
 
 // Struct สำหรับแก้ไขบทความ
@@ -24,6 +30,16 @@ type UpdateArticleInput struct {
 	CategoryID *uint    `json:"category_id"`
 	TagIDs     []uint   `json:"tag_ids"`
 	NewTags    []string `json:"new_tags"`
+
+	// cover_media_id เป็น 0 คือเอารูปปกออก ส่วน canonical_url เป็น "" คือกลับไปใช้ URL ของบทความเอง
+	CoverMediaID   *uint   `json:"cover_media_id"`
+	Excerpt        *string `json:"excerpt" validate:"omitempty,max=500"`
+	SEOTitle       *string `json:"seo_title" validate:"omitempty,max=255"`
+	SEODescription *string `json:"seo_description" validate:"omitempty,max=500"`
+	CanonicalURL   *string `json:"canonical_url" validate:"omitzero,http_url,max=500"`
+
+	// version ของบทความที่อ่านไป ใช้แทน If-Match ได้
+	Version *uint `json:"version"`
 }
 
 // ฟังก์ชันตรวจสอบ struct ทั่วไป
@@ -39,6 +55,11 @@ func ValidateStructArticle(data interface{}) map[string]string {
 		switch e.Tag() {
 		case "required":
 			errors[field] = "required"
+		case "max":
+			errors[field] = "must be at most " + e.Param() + " characters"
+		case "http_url":
+			// canonical_url ถูกใส่ลงใน <link> ของหน้าเว็บ scheme อื่นอย่าง javascript: จึงใช้ไม่ได้
+			errors[field] = "must be a full http or https URL"
 		default:
 			errors[field] = "invalid data"
 		}
@@ -0,0 +1,34 @@
+package validation
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	if errs := ValidateStructArticle(UpdateArticleInput{}); errs != nil {
+		t.Fatalf("update without canonical_url: %v", errs)
+	}
+
+	for url, valid := range map[string]bool{
+		"":                                true,
+		"https://example.com/posts/hello": true,
+		"http://example.com":              true,
+		"javascript:alert(1)":             false,
+		"data:text/html,<script>":         false,
+		"ftp://example.com/file":          false,
+		"//example.com/posts/hello":       false,
+		"/articles/hello":                 false,
+	} {
+		input := CreateArticleInput{Title: "t", Slug: "s", Content: "c", CategoryName: "n", CanonicalURL: url}
+		errs := ValidateStructArticle(input)
+		if valid && errs != nil {
+			t.Errorf("create %q: %v, want valid", url, errs)
+		}
+		if !valid && errs["canonicalurl"] == "" {
+			t.Errorf("create %q: %v, want a canonicalurl error", url, errs)
+		}
+
+		update := UpdateArticleInput{CanonicalURL: &url}
+		if errs := ValidateStructArticle(update); (errs == nil) != valid {
+			t.Errorf("update %q: %v, want valid=%v", url, errs, valid)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var mediaValidator = validator.New()
+
+// Update Media Input Struct
+type UpdateMediaInput struct {
+	AltText *string `json:"alt_text" validate:"omitempty,max=500"`
+	Caption *string `json:"caption" validate:"omitempty,max=2000"`
+}
+
+// validate media input
+func ValidateStructMedia(data interface{}) map[string]string {
+	err := mediaValidator.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errors := make(map[string]string)
+	for _, e := range err.(validator.ValidationErrors) {
+		field := strings.ToLower(e.Field())
+		switch e.Tag() {
+		case "max":
+			errors[field] = fmt.Sprintf("Must be at most %s characters", e.Param())
+		default:
+			errors[field] = "Invalid data"
+		}
+	}
+	return errors
+}
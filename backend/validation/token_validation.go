@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"backend/models"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var tokenValidator = newTokenValidator()
+
+func newTokenValidator() *validator.Validate {
+	v := validator.New()
+	// key ของ error เป็นชื่อเดียวกับใน JSON เช่น expires_in_days
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+	// scope ที่ใช้ได้มาจาก models.TokenScopes ที่เดียว เพิ่ม scope ใหม่แล้วไม่ต้องแก้ที่นี่
+	v.RegisterValidation("scope", func(fl validator.FieldLevel) bool {
+		return slices.Contains(models.TokenScopes, fl.Field().String())
+	})
+	return v
+}
+
+// Create Personal Access Token Input Struct
+type CreateTokenInput struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,scope"`
+	ExpiresInDays *int     `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// validate create token input
+func ValidateStructToken(data interface{}) map[string]string {
+	err := tokenValidator.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errors := make(map[string]string)
+	for _, e := range err.(validator.ValidationErrors) {
+		field := e.Field()
+		switch e.Tag() {
+		case "required":
+			errors[field] = "This field is required"
+		case "scope":
+			errors["scopes"] = fmt.Sprintf("Unknown scope %q", e.Value())
+		case "min", "max":
+			errors[field] = fmt.Sprintf("Out of range (%s %s)", e.Tag(), e.Param())
+		default:
+			errors[field] = "Invalid data"
+		}
+	}
+	return errors
+}
@@ -0,0 +1,18 @@
+package validation
+
+import (
+	"backend/models"
+	"testing"
+)
+
+func TestValidateToken(t *testing.T) {
+	days := 0
+	errs := ValidateStructToken(CreateTokenInput{Name: "ci", Scopes: []string{models.ScopeArticlesRead, "admin"}, ExpiresInDays: &days})
+	if errs["scopes"] != `Unknown scope "admin"` || errs["expires_in_days"] == "" || len(errs) != 2 {
+		t.Fatalf("invalid input: %v", errs)
+	}
+
+	if errs := ValidateStructToken(CreateTokenInput{Name: "ci", Scopes: models.TokenScopes}); errs != nil {
+		t.Fatalf("every scope in models.TokenScopes: %v", errs)
+	}
+}
@@ -26,6 +26,14 @@ type LoginInput struct {
 	Password        string `json:"password" validate:"required"`
 }
 
+// Delete Account Input Struct
+// บัญชีที่มีรหัสผ่านต้องยืนยันด้วยรหัสผ่าน บัญชีที่ login ผ่าน OIDC ต้องพิมพ์ username ใน confirm
+type DeleteAccountInput struct {
+	Mode     string `json:"mode" validate:"required,oneof=anonymize remove"`
+	Password string `json:"password"`
+	Confirm  string `json:"confirm"`
+}
+
 // validate register input
 func ValidateStructRegister(data interface{}) map[string]string {
 	err := userValidator.Struct(data)
@@ -50,6 +58,28 @@ func ValidateStructRegister(data interface{}) map[string]string {
 	return errors
 }
 
+// validate delete account input
+func ValidateStructDeleteAccount(data interface{}) map[string]string {
+	err := userValidator.Struct(data)
+	if err == nil {
+		return nil
+	}
+
+	errors := make(map[string]string)
+	for _, e := range err.(validator.ValidationErrors) {
+		field := strings.ToLower(e.Field())
+		switch e.Tag() {
+		case "required":
+			errors[field] = "This field is required"
+		case "oneof":
+			errors[field] = fmt.Sprintf("Must be one of: %s", e.Param())
+		default:
+			errors[field] = "Invalid data"
+		}
+	}
+	return errors
+}
+
 // validate login input
 func ValidateStructLogin(data interface{}) map[string]string {
 	err := userValidator.Struct(data)
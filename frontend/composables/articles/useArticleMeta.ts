@@ -0,0 +1,43 @@
+import { computed } from 'vue'
+
+interface MetaTag {
+  property?: string
+  name?: string
+  content: string
+}
+
+export interface ArticleMeta {
+  title: string
+  description: string
+  canonical_url: string
+  open_graph: MetaTag[]
+  twitter: MetaTag[]
+}
+
+// Load Open Graph / Twitter card tags for an article so link previews work on the server-rendered page
+export function useArticleMeta(slug: string) {
+  const config = useRuntimeConfig()
+  // The Nuxt server talks to the backend directly, the browser goes through nginx
+  const base = import.meta.server ? config.apiBase : '/api'
+
+  const { data: meta } = useAsyncData(`article-meta-${slug}`, () =>
+    $fetch<{ data: ArticleMeta }>(`${base}/articles/${encodeURIComponent(slug)}/meta`)
+      .then(res => res.data)
+      .catch(() => null)
+  )
+
+  useHead(computed(() => {
+    if (!meta.value) return {}
+    return {
+      title: meta.value.title,
+      link: [{ rel: 'canonical', href: meta.value.canonical_url }],
+      meta: [
+        { name: 'description', content: meta.value.description },
+        ...meta.value.open_graph,
+        ...meta.value.twitter,
+      ],
+    }
+  }))
+
+  return { meta }
+}
@@ -72,6 +72,33 @@ export const createArticleAPI = async (payload: any, token: string) => {
   }
 }
 
+// Upload an image to the media library for use in an article
+export const uploadMediaAPI = async (file: File, altText: string, token: string) => {
+  try {
+    const body = new FormData()
+    body.append('file', file)
+    body.append('alt_text', altText)
+    const res = await $fetch<{ data: { url: string; alt_text: string; variants: { width: number; url: string }[] } }>('/api/media', {
+      method: 'POST',
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+      body
+    })
+    return { data: res.data, error: null }
+  } catch (e: any) {
+    return { data: null, error: e?.data?.message || 'ไม่สามารถอัปโหลดรูปภาพได้' }
+  }
+}
+
+// Build an <img> tag with responsive variants
+const escapeAttr = (value: string) => value.replace(/&/g, '&amp;').replace(/"/g, '&quot;').replace(/</g, '&lt;')
+
+export const mediaImageTag = (media: { url: string; alt_text: string; variants: { width: number; url: string }[] }) => {
+  const srcset = media.variants.map(v => `${v.url} ${v.width}w`).join(', ')
+  return `<img src="${media.url}" srcset="${srcset}" sizes="(max-width: 768px) 100vw, 768px" alt="${escapeAttr(media.alt_text)}" loading="lazy" />`
+}
+
 // Article List Management
 export function useArticleListState() {
   const allArticles = ref<Article[]>([])
@@ -144,7 +171,7 @@ export function useArticles() {
   })
   
   // Watch for filter changes
-  watch([filter.searchTerm, filter.selectedCategory], loadArticles)
+  watch([filter.searchTerm, filter.selectedCategory, filter.selectedTag, filter.selectedAuthor], loadArticles)
   
   return {
     // Data
@@ -316,6 +343,32 @@ export function useCreateArticle() {
     
     formState.value.loading = false
   }
+
+  // Upload an image and append it to the content
+  const uploadingImage = ref(false)
+  const insertImage = async (event: Event) => {
+    const input = event.target as HTMLInputElement
+    const file = input.files?.[0]
+    if (!file) return
+
+    const { isAuthenticated, token, error: authError } = checkAuth()
+    if (!isAuthenticated) {
+      formState.value.error.general = authError!
+      return
+    }
+
+    uploadingImage.value = true
+    const { data, error } = await uploadMediaAPI(file, file.name.replace(/\.[^.]+$/, ''), token!)
+    uploadingImage.value = false
+    input.value = ''
+
+    if (error || !data) {
+      formState.value.error.content = error!
+      return
+    }
+    const separator = formData.value.content && !formData.value.content.endsWith('\n') ? '\n' : ''
+    formData.value.content += separator + mediaImageTag(data) + '\n'
+  }
   
   // Computed properties for v-model binding
   const computedFields = {
@@ -351,8 +404,11 @@ export function useCreateArticle() {
     success: computed(() => formState.value.success),
     loading: computed(() => formState.value.loading),
     
+    uploadingImage,
+
     // Methods
     handleSubmit,
+    insertImage,
     resetForm,
     validateForm
   }
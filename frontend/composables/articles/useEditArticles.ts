@@ -13,6 +13,8 @@ export function useEditArticles() {
   const selectedTags = ref<{ id?: number; name: string }[]>([])
   const tagInput = ref('')
   const showSuggestions = ref(false)
+  // version ของบทความตอนโหลดมาแก้ ส่งกลับไปตอนบันทึกเพื่อไม่ให้ทับการแก้ของคนอื่น
+  const version = ref(0)
 
   // Options
   const categories = ref<{ id: number; name: string }[]>([])
@@ -33,6 +35,7 @@ export function useEditArticles() {
       const article = res.data
       title.value = article.title
       content.value = article.content
+      version.value = article.version
       selectedCategory.value = article.category?.id || null
       selectedTags.value = article.tags?.map((tag: any) => ({ id: tag.id, name: tag.name })) || []
     } catch (err) {
@@ -116,11 +119,17 @@ export function useEditArticles() {
           category_id: selectedCategory.value,
           tag_ids: selectedTags.value.filter(tag => tag.id).map(tag => tag.id),
           new_tags: selectedTags.value.filter(tag => !tag.id).map(tag => tag.name),
+          version: version.value,
         },
       })
       alert('✅ แก้ไขบทความเรียบร้อยแล้ว')
       router.push('/articles/my-articles')
-    } catch (err) {
+    } catch (err: any) {
+      if (err?.statusCode === 409) {
+        version.value = err.data?.version ?? version.value
+        alert('❌ บทความนี้ถูกแก้ไขไปแล้วหลังจากที่คุณเปิดมา กรุณาโหลดหน้าใหม่แล้วแก้ไขอีกครั้ง')
+        return
+      }
       alert('❌ ไม่สามารถแก้ไขบทความได้')
       console.error(err)
     }
@@ -10,6 +10,8 @@ export function useComment() {
     
     // สำหรับการแก้ไขคอมเมนต์
     const editingCommentId = ref<number | null>(null)
+    // version ของคอมเมนต์ตอนเริ่มแก้ ส่งกลับไปตอนบันทึกเพื่อไม่ให้ทับการแก้ของคนอื่น
+    const editVersion = ref(0)
     const editContent = ref('')
   
     // ดึงรายละเอียดบทความตาม slug
@@ -95,6 +97,7 @@ export function useComment() {
     const startEditComment = (comment: Comment) => {
       editingCommentId.value = comment.id
       editContent.value = comment.content
+      editVersion.value = comment.version
     }
 
     // ยกเลิกการแก้ไข
@@ -124,6 +127,7 @@ const saveEditComment = async (commentId: number) => {
       },
       body: {
         content: editContent.value,
+        version: editVersion.value,
       },
     })
 
@@ -135,8 +139,13 @@ const saveEditComment = async (commentId: number) => {
     await fetchComments()
     
     alert('แก้ไขความคิดเห็นสำเร็จ')
-  } catch (err) {
+  } catch (err: any) {
     console.error('❌ Failed to update comment:', err)
+    if (err?.statusCode === 409) {
+      await fetchComments()
+      alert('ความคิดเห็นนี้ถูกแก้ไขไปแล้ว กรุณาตรวจสอบแล้วแก้ไขใหม่อีกครั้ง')
+      return
+    }
     alert('ไม่สามารถแก้ไขความคิดเห็นได้ กรุณาลองใหม่อีกครั้ง')
   }
 }
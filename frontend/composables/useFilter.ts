@@ -7,8 +7,12 @@ export interface FilterOptions {
 export function useFilter(options: FilterOptions = {}) {
   const { debounceDelay = 500 } = options
   
-  // Filter states
-  const selectedCategory = ref<string | number>('')
+  // Filter states (start from the URL so links like /articles?tag=go from feeds and the sitemap work)
+  const route = useRoute()
+  const queryValue = (value: unknown) => (typeof value === 'string' ? value : '')
+  const selectedCategory = ref<string | number>(queryValue(route.query.category_id))
+  const selectedTag = ref(queryValue(route.query.tag))
+  const selectedAuthor = ref(queryValue(route.query.author))
   const searchTerm = ref('')
   const localSearchTerm = ref('') // for immediate v-model binding
   
@@ -51,6 +55,8 @@ export function useFilter(options: FilterOptions = {}) {
   const clearAllFilters = () => {
     clearSearch()
     clearCategory()
+    selectedTag.value = ''
+    selectedAuthor.value = ''
   }
   
   // Build query parameters for API calls
@@ -64,13 +70,21 @@ export function useFilter(options: FilterOptions = {}) {
     if (selectedCategory.value) {
       params.append('category_id', selectedCategory.value.toString())
     }
+
+    if (selectedTag.value) {
+      params.append('tag', selectedTag.value)
+    }
+
+    if (selectedAuthor.value) {
+      params.append('author', selectedAuthor.value)
+    }
     
     return params
   }
   
   // Check if any filters are active
   const hasActiveFilters = computed(() => {
-    return searchTerm.value.trim() !== '' || selectedCategory.value !== ''
+    return searchTerm.value.trim() !== '' || selectedCategory.value !== '' || selectedTag.value !== '' || selectedAuthor.value !== ''
   })
   
   // Get filter summary for display
@@ -84,6 +98,14 @@ export function useFilter(options: FilterOptions = {}) {
     if (selectedCategory.value) {
       filters.push(`หมวดหมู่: ${selectedCategory.value}`)
     }
+
+    if (selectedTag.value) {
+      filters.push(`แท็ก: ${selectedTag.value}`)
+    }
+
+    if (selectedAuthor.value) {
+      filters.push(`ผู้เขียน: ${selectedAuthor.value}`)
+    }
     
     return filters
   }
@@ -98,6 +120,8 @@ export function useFilter(options: FilterOptions = {}) {
   return {
     // State
     selectedCategory,
+    selectedTag,
+    selectedAuthor,
     searchTerm,
     localSearchTerm,
     
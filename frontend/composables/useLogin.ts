@@ -1,4 +1,4 @@
-import { ref } from 'vue'
+import { ref, onMounted } from 'vue'
 import { useRouter } from 'vue-router'
 
 export const useLogin = () => {
@@ -38,10 +38,38 @@ export const useLogin = () => {
     }
   }
 
+  // ผู้ให้บริการ login ภายนอก (OIDC)
+  const providers = ref<string[]>([])
+
+  const loginWith = (provider: string) => {
+    window.location.href = `/api/auth/oidc/${provider}/login`
+  }
+
+  onMounted(async () => {
+    // กลับมาจากผู้ให้บริการพร้อม token ใน #token=...
+    const hashToken = new URLSearchParams(window.location.hash.slice(1)).get('token')
+    if (hashToken) {
+      localStorage.setItem('token', hashToken)
+      history.replaceState(null, '', window.location.pathname)
+      router.push('/')
+      return
+    }
+
+    try {
+      const res = await fetch('/api/auth/oidc/providers')
+      const json = await res.json()
+      providers.value = json.data || []
+    } catch {
+      providers.value = []
+    }
+  })
+
   return {
     emailOrUsername,
     password,
     error,
     login,
+    providers,
+    loginWith,
   }
 }
\ No newline at end of file
@@ -8,6 +8,8 @@ export const ArticelsText = {
     update_articel : "แก้ไขบทความ",
     delete_articel : "ลบบทความ",
     create_articel : "สร้างบทความ",
+    insert_image : "แทรกรูปภาพ",
+    uploading_image : "กำลังอัปโหลดรูปภาพ...",
 }
 
 
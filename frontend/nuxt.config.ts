@@ -6,9 +6,19 @@ export default defineNuxtConfig({
       title: 'BoBlog', // ชื่อแท็บเบราว์เซอร์
       meta: [
         { name: 'description', content: 'ระบบจัดการบทความออนไลน์' }
+      ],
+      // ให้ feed reader หา feed ของเว็บได้เอง
+      link: [
+        { rel: 'alternate', type: 'application/rss+xml', title: 'BoBlog RSS', href: '/feed.xml' },
+        { rel: 'alternate', type: 'application/atom+xml', title: 'BoBlog Atom', href: '/atom.xml' },
+        { rel: 'alternate', type: 'application/feed+json', title: 'BoBlog JSON Feed', href: '/feed.json' }
       ]
     }
   },
+  runtimeConfig: {
+    // Backend URL used during server-side rendering (override with NUXT_API_BASE)
+    apiBase: 'http://backend:8080',
+  },
   compatibilityDate: "2024-11-01",
   devtools: { enabled: true },
   css: ['~/assets/css/main.css'],
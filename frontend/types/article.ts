@@ -29,6 +29,7 @@ export interface Article {
     content: string
     created_at: string
     updated_at: string
+    version: number
     user?: {
       username: string
       id: number